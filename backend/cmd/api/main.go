@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -10,10 +11,23 @@ import (
 	"time"
 
 	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/alerting"
 	"github.com/sid-romero/fleetpulse/internal/api"
+	"github.com/sid-romero/fleetpulse/internal/auth"
 	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/events"
+	"github.com/sid-romero/fleetpulse/internal/mqtt"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+	"github.com/sid-romero/fleetpulse/internal/repository/memory"
+	"github.com/sid-romero/fleetpulse/internal/repository/postgres"
+	"github.com/sid-romero/fleetpulse/internal/rpc"
+	"github.com/sid-romero/fleetpulse/internal/scoring"
 	"github.com/sid-romero/fleetpulse/internal/service"
+	"github.com/sid-romero/fleetpulse/internal/trip"
+	"github.com/sid-romero/fleetpulse/internal/validation"
 	"github.com/sid-romero/fleetpulse/internal/websocket"
 	"golang.org/x/sync/errgroup"
 )
@@ -33,14 +47,86 @@ func main() {
 		Int("port", cfg.Server.Port).
 		Msg("Starting FleetPulse API server")
 
-	// Initialize services
-	vehicleService := service.NewVehicleService()
-	alertService := service.NewAlertService()
-	telemetryService := service.NewTelemetryService()
-	analyticsService := service.NewAnalyticsService()
+	// Initialize storage
+	repos, closeRepos, err := openRepositories(cfg)
+	if err != nil {
+		logger.Fatal().Err(err).Str("driver", cfg.Database.Driver).Msg("Failed to initialize storage")
+	}
+	defer closeRepos()
 
-	// Initialize WebSocket hub
+	// Initialize WebSocket hub and domain event bus
 	wsHub := websocket.NewHub(logger)
+	bus := events.NewBus(logger)
+
+	// Initialize services
+	vehicleService := service.NewVehicleService(repos.vehicles, bus, logger)
+	alertService := service.NewAlertService(repos.alerts, bus, logger)
+	validator, err := validation.NewValidator(cfg.Validation)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid telemetry validation config")
+	}
+	telemetryService := service.NewTelemetryService(repos.telemetry, repos.vehicles, validator, cfg.Trips.IdleTimeout, bus, logger)
+	analyticsService := service.NewAnalyticsService(repos.vehicles, repos.alerts, repos.telemetry, repos.driverAssignments, bus, logger)
+	geofenceService := service.NewGeofenceService(repos.geofences, alertService, bus, logger)
+	userService := service.NewUserService(repos.users, logger)
+	deviceKeyService := service.NewDeviceKeyService(repos.deviceKeys, repos.vehicles, logger)
+	tripService := service.NewTripService(repos.trips, trip.NewSegmenter(cfg.Trips), bus, logger)
+	rollupService := service.NewRollupService(repos.telemetry, cfg.Rollups.Interval, cfg.Rollups.Lateness, logger)
+	retentionPolicy, err := loadRetentionPolicy(cfg)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid retention settings")
+	}
+	retentionService := service.NewRetentionService(repos.telemetry, retentionPolicy, cfg.Retention.Interval, logger)
+	maintenanceService := service.NewMaintenanceService(repos.maintenance, repos.maintenancePlans, vehicleService, alertService, bus, logger)
+	driverService := service.NewDriverService(repos.drivers, repos.driverAssignments, vehicleService, bus, logger)
+	detector, err := scoring.NewDetector(cfg.Scoring)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("Invalid scoring settings")
+	}
+	scoringService := service.NewScoringService(repos.drivingEvents, repos.trips, driverService, detector, cfg.Scoring.Window, cfg.Scoring.Interval, logger)
+	utilizationService := service.NewUtilizationService(repos.statusChanges, repos.vehicles, logger)
+
+	var mqttSubscriber *mqtt.Subscriber
+	if cfg.MQTT.Enabled {
+		if mqttSubscriber, err = mqtt.NewSubscriber(cfg.MQTT, telemetryService, logger); err != nil {
+			logger.Fatal().Err(err).Msg("Invalid MQTT settings")
+		}
+	}
+
+	telemetryServer := rpc.NewTelemetryServer(telemetryService, cfg.GRPC.SubscriberBuffer, logger)
+
+	// Initialize alert rules engine
+	alertRules, err := loadAlertRules(cfg)
+	if err != nil {
+		logger.Fatal().Err(err).Str("file", cfg.Alerts.RulesFile).Msg("Failed to load alert rules")
+	}
+	alertMonitor := service.NewAlertMonitor(alerting.NewEngine(alertRules), alertService, bus, logger)
+
+	// Subscribe event consumers
+	queueSize := cfg.Events.QueueSize
+	bus.Subscribe("websocket", wsHub.HandleEvent, queueSize, websocket.BroadcastEventTypes...)
+	bus.Subscribe("alert-engine", alertMonitor.HandleEvent, queueSize, domain.EventTypeTelemetryReceived)
+	bus.Subscribe("geofences", geofenceService.HandleEvent, queueSize, domain.EventTypeTelemetryReceived)
+	bus.Subscribe("trips", tripService.HandleEvent, queueSize, domain.EventTypeTelemetryReceived)
+	bus.Subscribe("driver-scoring", scoringService.HandleEvent, queueSize, domain.EventTypeTelemetryReceived)
+	bus.Subscribe("maintenance", maintenanceService.HandleEvent, queueSize, domain.EventTypeVehicleUpdated)
+	bus.Subscribe("status-history", utilizationService.HandleEvent, queueSize, domain.EventTypeVehicleStatusChange)
+	bus.Subscribe("fleet-stats", analyticsService.HandleEvent, queueSize, service.FleetStatsEventTypes...)
+	bus.Subscribe("audit", events.NewAuditLog(logger), queueSize, events.AuditedEventTypes...)
+	if cfg.GRPC.Enabled {
+		bus.Subscribe("grpc-subscribers", telemetryServer.HandleEvent, queueSize, domain.EventTypeTelemetryReceived)
+	}
+
+	// Share broadcast events with other API replicas
+	relay, closeRelay, err := openEventRelay(cfg, logger)
+	if err != nil {
+		logger.Fatal().Err(err).Str("transport", cfg.Events.Transport).Msg("Failed to initialize event transport")
+	}
+	defer closeRelay()
+	if relay != nil {
+		relayed := append(append([]domain.EventType{}, websocket.RelayedEventTypes...), service.GeofenceChangeEventTypes...)
+		bus.Subscribe("redis-relay", relay.Forward, queueSize, relayed...)
+	}
 
 	// Initialize HTTP handler
 	handler := api.NewHandler(
@@ -48,11 +134,30 @@ func main() {
 		alertService,
 		telemetryService,
 		analyticsService,
+		geofenceService,
+		userService,
+		deviceKeyService,
+		tripService,
+		retentionService,
+		maintenanceService,
+		driverService,
+		scoringService,
+		utilizationService,
 		logger,
 	)
 
+	// Initialize authentication
+	var verifier *auth.Verifier
+	if cfg.Auth.Enabled {
+		if verifier, err = auth.NewVerifier(cfg.Auth); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to initialize authentication")
+		}
+	} else {
+		logger.Warn().Msg("Authentication is disabled; every request is anonymous")
+	}
+
 	// Create router
-	router := api.NewRouter(cfg, handler, wsHub, logger)
+	router := api.NewRouter(cfg, handler, wsHub, verifier, logger)
 
 	// Create HTTP server
 	server := &http.Server{
@@ -76,6 +181,65 @@ func main() {
 		return nil
 	})
 
+	// Run event bus
+	g.Go(func() error {
+		bus.Run(gCtx)
+		return nil
+	})
+
+	// Close trips of vehicles that stopped reporting
+	g.Go(func() error {
+		tripService.Run(gCtx)
+		return nil
+	})
+
+	// Keep telemetry rollups up to date
+	g.Go(func() error {
+		rollupService.Run(gCtx)
+		return nil
+	})
+
+	// Prune telemetry past its retention
+	g.Go(func() error {
+		retentionService.Run(gCtx)
+		return nil
+	})
+
+	// Raise maintenance that falls due by date
+	g.Go(func() error {
+		maintenanceService.Run(gCtx)
+		return nil
+	})
+
+	// Keep driver ratings in step with their scores
+	g.Go(func() error {
+		scoringService.Run(gCtx)
+		return nil
+	})
+
+	// Ingest telemetry that devices publish over MQTT
+	if mqttSubscriber != nil {
+		g.Go(func() error {
+			mqttSubscriber.Run(gCtx)
+			return nil
+		})
+	}
+
+	// Deliver events relayed from other replicas to local WebSocket clients,
+	// gRPC subscribers and fleet stats, and geofence changes to the geofence
+	// cache
+	if relay != nil {
+		g.Go(func() error {
+			relay.Run(gCtx, func(ctx context.Context, event *domain.Event) {
+				geofenceService.HandleChange(ctx, event)
+				wsHub.HandleEvent(ctx, event)
+				analyticsService.HandleEvent(ctx, event)
+				telemetryServer.HandleEvent(ctx, event)
+			})
+			return nil
+		})
+	}
+
 	// Run HTTP server
 	g.Go(func() error {
 		logger.Info().
@@ -88,10 +252,43 @@ func main() {
 		return nil
 	})
 
-	// Run telemetry broadcaster (simulates real-time updates)
-	g.Go(func() error {
-		return runTelemetryBroadcaster(gCtx, wsHub, logger)
-	})
+	// Run gRPC server for streaming telemetry
+	if cfg.GRPC.Enabled {
+		grpcServer := rpc.NewServer(telemetryServer, rpc.NewAuthenticator(verifier, userService, deviceKeyService, logger))
+		listener, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.GRPC.Port))
+		if err != nil {
+			logger.Fatal().Err(err).Int("port", cfg.GRPC.Port).Msg("Failed to listen for gRPC")
+		}
+
+		g.Go(func() error {
+			logger.Info().
+				Str("addr", listener.Addr().String()).
+				Msg("gRPC server listening")
+
+			if err := grpcServer.Serve(listener); err != nil {
+				return fmt.Errorf("gRPC server error: %w", err)
+			}
+			return nil
+		})
+
+		g.Go(func() error {
+			<-gCtx.Done()
+			logger.Info().Msg("Shutting down gRPC server...")
+			telemetryServer.Close()
+
+			stopped := make(chan struct{})
+			go func() {
+				grpcServer.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-time.After(cfg.Server.ShutdownTimeout):
+				grpcServer.Stop()
+			}
+			return nil
+		})
+	}
 
 	// Handle graceful shutdown
 	g.Go(func() error {
@@ -155,46 +352,125 @@ func setupLogger(cfg *config.Config) zerolog.Logger {
 		Logger()
 }
 
-// runTelemetryBroadcaster simulates real-time telemetry updates
-func runTelemetryBroadcaster(ctx context.Context, hub *websocket.Hub, logger zerolog.Logger) error {
-	ticker := time.NewTicker(3 * time.Second)
-	defer ticker.Stop()
+// repositories groups the storage backends handed to the services
+type repositories struct {
+	vehicles          repository.VehicleRepository
+	alerts            repository.AlertRepository
+	telemetry         repository.TelemetryRepository
+	geofences         repository.GeofenceRepository
+	users             repository.UserRepository
+	deviceKeys        repository.DeviceKeyRepository
+	trips             repository.TripRepository
+	maintenance       repository.MaintenanceRepository
+	maintenancePlans  repository.MaintenancePlanRepository
+	drivers           repository.DriverRepository
+	driverAssignments repository.DriverAssignmentRepository
+	drivingEvents     repository.DrivingEventRepository
+	statusChanges     repository.VehicleStatusChangeRepository
+}
 
-	vehicles := []string{
-		"11111111-1111-1111-1111-111111111111",
-		"22222222-2222-2222-2222-222222222222",
-		"33333333-3333-3333-3333-333333333333",
-		"44444444-4444-4444-4444-444444444444",
+// openRepositories selects the storage backend from cfg.Database.Driver.
+// The memory driver is seeded with the development fleet and needs no database.
+func openRepositories(cfg *config.Config) (*repositories, func(), error) {
+	switch cfg.Database.Driver {
+	case "memory":
+		vehicles := memory.SeedVehicles()
+		drivers := memory.NewDriverRepository(memory.SeedDrivers()...)
+		return &repositories{
+			vehicles:          memory.NewVehicleRepository(vehicles...).JoinDrivers(drivers),
+			alerts:            memory.NewAlertRepository(memory.SeedAlerts()...),
+			telemetry:         memory.NewTelemetryRepository(),
+			geofences:         memory.NewGeofenceRepository(),
+			users:             memory.NewUserRepository(memory.SeedUsers()...),
+			deviceKeys:        memory.NewDeviceKeyRepository(),
+			trips:             memory.NewTripRepository(),
+			maintenance:       memory.NewMaintenanceRepository(),
+			maintenancePlans:  memory.NewMaintenancePlanRepository(),
+			drivers:           drivers,
+			driverAssignments: memory.NewDriverAssignmentRepository(memory.SeedDriverAssignments(vehicles)...),
+			drivingEvents:     memory.NewDrivingEventRepository(),
+			statusChanges:     memory.NewVehicleStatusChangeRepository(),
+		}, func() {}, nil
+
+	case "postgres":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		db, err := postgres.Open(ctx, cfg.Database)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &repositories{
+			vehicles:          postgres.NewVehicleRepository(db),
+			alerts:            postgres.NewAlertRepository(db),
+			telemetry:         postgres.NewTelemetryRepository(db),
+			geofences:         postgres.NewGeofenceRepository(db),
+			users:             postgres.NewUserRepository(db),
+			deviceKeys:        postgres.NewDeviceKeyRepository(db),
+			trips:             postgres.NewTripRepository(db),
+			maintenance:       postgres.NewMaintenanceRepository(db),
+			maintenancePlans:  postgres.NewMaintenancePlanRepository(db),
+			drivers:           postgres.NewDriverRepository(db),
+			driverAssignments: postgres.NewDriverAssignmentRepository(db),
+			drivingEvents:     postgres.NewDrivingEventRepository(db),
+			statusChanges:     postgres.NewVehicleStatusChangeRepository(db),
+		}, func() { db.Close() }, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown database driver %q", cfg.Database.Driver)
 	}
+}
 
-	for {
-		select {
-		case <-ctx.Done():
-			return nil
-		case <-ticker.C:
-			// Only broadcast if there are connected clients
-			if hub.GetClientCount() == 0 {
-				continue
-			}
+// loadRetentionPolicy builds the retention policy, rejecting raw retention
+// too short for the rollup job to re-read late records
+func loadRetentionPolicy(cfg *config.Config) (domain.RetentionPolicy, error) {
+	r := cfg.Retention
+	if r.RawDays > 0 && time.Duration(r.RawDays)*24*time.Hour <= cfg.Rollups.Lateness {
+		return domain.RetentionPolicy{}, fmt.Errorf("RETENTION_RAW_DAYS must cover ROLLUP_LATENESS (%s)", cfg.Rollups.Lateness)
+	}
+	return domain.RetentionPolicy{Days: map[domain.Resolution]int{
+		domain.ResolutionRaw: r.RawDays,
+		domain.Resolution1m:  r.Rollup1mDays,
+		domain.Resolution15m: r.Rollup15mDays,
+		domain.Resolution1h:  r.Rollup1hDays,
+	}}, nil
+}
 
-			// Simulate telemetry for each vehicle
-			for _, vid := range vehicles {
-				telemetry := map[string]interface{}{
-					"vehicleId":    vid,
-					"timestamp":    time.Now().UTC(),
-					"speed":        float64(40 + time.Now().Second()%30),
-					"batteryLevel": 75 + time.Now().Minute()%20,
-					"location": map[string]float64{
-						"lat": 40.7128 + float64(time.Now().Second()%10)*0.001,
-						"lng": -74.0060 + float64(time.Now().Second()%10)*0.001,
-					},
-					"engineTemp": 85 + time.Now().Second()%15,
-				}
-
-				if err := hub.Broadcast(websocket.MessageTypeTelemetry, telemetry); err != nil {
-					logger.Error().Err(err).Msg("Failed to broadcast telemetry")
-				}
-			}
+// loadAlertRules reads the configured rules file, falling back to the
+// built-in rules with thresholds from the environment
+func loadAlertRules(cfg *config.Config) ([]alerting.Rule, error) {
+	if cfg.Alerts.RulesFile == "" {
+		return alerting.DefaultRules(cfg.Alerts), nil
+	}
+	return alerting.LoadRules(cfg.Alerts.RulesFile, cfg.Alerts.Cooldown)
+}
+
+// openEventRelay connects the Redis event relay when cfg.Events.Transport is
+// redis. The local transport returns a nil relay: events stay in-process,
+// which is all a single-node deployment needs.
+func openEventRelay(cfg *config.Config, logger zerolog.Logger) (*events.RedisRelay, func(), error) {
+	switch cfg.Events.Transport {
+	case "local":
+		return nil, func() {}, nil
+
+	case "redis":
+		client := redis.NewClient(&redis.Options{
+			Addr:     fmt.Sprintf("%s:%d", cfg.Redis.Host, cfg.Redis.Port),
+			Password: cfg.Redis.Password,
+			DB:       cfg.Redis.DB,
+		})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := client.Ping(ctx).Err(); err != nil {
+			client.Close()
+			return nil, nil, fmt.Errorf("ping redis: %w", err)
 		}
+		relay := events.NewRedisRelay(client, cfg.Events.Stream, cfg.Events.StreamMaxLen, logger)
+		return relay, func() { client.Close() }, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unknown event transport %q", cfg.Events.Transport)
 	}
 }
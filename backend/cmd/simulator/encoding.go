@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/sid-romero/fleetpulse/internal/codec"
+	pb "github.com/sid-romero/fleetpulse/internal/pb/fleetpulse/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// contentTypes maps each TELEMETRY_FORMAT to the content type it is sent as
+var contentTypes = map[string]string{
+	"json":     codec.ContentTypeJSON,
+	"protobuf": codec.ContentTypeProtobuf,
+	"cbor":     codec.ContentTypeCBOR,
+}
+
+// encodeTelemetry encodes a payload in format
+func encodeTelemetry(format string, telemetry TelemetryPayload) ([]byte, error) {
+	switch format {
+	case "json":
+		return json.Marshal(telemetry)
+	case "cbor":
+		return cbor.Marshal(telemetry)
+	case "protobuf":
+		timestamp, err := time.Parse(time.RFC3339, telemetry.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		record := &pb.Telemetry{
+			VehicleId: telemetry.VehicleID,
+			Timestamp: timestamppb.New(timestamp),
+			Location: &pb.Location{
+				Lat:     telemetry.Location.Lat,
+				Lng:     telemetry.Location.Lng,
+				Address: telemetry.Location.Address,
+			},
+			Speed:           float32(telemetry.Speed),
+			BatteryLevel:    int32(telemetry.BatteryLevel),
+			EngineTemp:      float32(telemetry.EngineTemp),
+			EngineRpm:       int32(telemetry.EngineRPM),
+			Heading:         float32(telemetry.Heading),
+			Ignition:        proto.Bool(telemetry.Ignition),
+			ChargingCurrent: proto.Float32(float32(telemetry.ChargingCurrent)),
+		}
+		if telemetry.FuelLevel != nil {
+			record.FuelLevel = proto.Int32(int32(*telemetry.FuelLevel))
+		}
+		return proto.Marshal(record)
+	}
+	return nil, fmt.Errorf("unknown telemetry format %q", format)
+}
@@ -16,6 +16,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
 )
 
 // SimulatedVehicle represents a vehicle being simulated
@@ -31,9 +32,11 @@ type SimulatedVehicle struct {
 	Heading      float64 // Direction in degrees
 	EngineTemp   float64
 	EngineRPM    int
+	ChargingCurrent float64 // amps, 0 when unplugged
 	IsMoving     bool
 	Route        []Location
 	RouteIndex   int
+	DeviceKey    string // sent as X-Device-Key when set
 }
 
 type Location struct {
@@ -52,6 +55,8 @@ type TelemetryPayload struct {
 	EngineTemp   float64  `json:"engineTemp"`
 	EngineRPM    int      `json:"engineRpm"`
 	Heading      float64  `json:"heading"`
+	Ignition     bool     `json:"ignition"`
+	ChargingCurrent float64 `json:"chargingCurrent"`
 }
 
 // Config for simulator
@@ -59,6 +64,8 @@ type Config struct {
 	APIURL         string
 	VehicleCount   int
 	UpdateInterval time.Duration
+	DeviceKeysFile string // JSON object mapping vehicle IDs to device keys
+	Format         string // json, protobuf or cbor
 }
 
 func main() {
@@ -73,17 +80,35 @@ func main() {
 		APIURL:         getEnv("API_URL", "http://localhost:8080"),
 		VehicleCount:   getEnvAsInt("VEHICLE_COUNT", 4),
 		UpdateInterval: getEnvAsDuration("UPDATE_INTERVAL", 3*time.Second),
+		DeviceKeysFile: getEnv("DEVICE_KEYS_FILE", ""),
+		Format:         getEnv("TELEMETRY_FORMAT", "json"),
+	}
+	if _, ok := contentTypes[cfg.Format]; !ok {
+		logger.Fatal().Str("format", cfg.Format).Msg("TELEMETRY_FORMAT must be json, protobuf or cbor")
 	}
 
 	logger.Info().
 		Str("apiUrl", cfg.APIURL).
 		Int("vehicleCount", cfg.VehicleCount).
 		Dur("updateInterval", cfg.UpdateInterval).
+		Str("format", cfg.Format).
 		Msg("Starting vehicle simulator")
 
 	// Initialize vehicles
 	vehicles := initializeVehicles(cfg.VehicleCount)
 
+	// Load device keys
+	keys, err := loadDeviceKeys(cfg.DeviceKeysFile)
+	if err != nil {
+		logger.Fatal().Err(err).Str("file", cfg.DeviceKeysFile).Msg("Failed to load device keys")
+	}
+	for _, v := range vehicles {
+		v.DeviceKey = keys[v.ID.String()]
+		if cfg.DeviceKeysFile != "" && v.DeviceKey == "" {
+			logger.Warn().Str("vehicleId", v.ID.String()).Msg("No device key for vehicle, sending unauthenticated")
+		}
+	}
+
 	// Context for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -201,14 +226,25 @@ func runSimulation(ctx context.Context, cfg Config, vehicles []*SimulatedVehicle
 
 	client := &http.Client{Timeout: 10 * time.Second}
 
+	// Payload sizes, to compare formats
+	var sent, sentBytes int
+
 	for {
 		select {
 		case <-ctx.Done():
+			if sent > 0 {
+				logger.Info().
+					Str("format", cfg.Format).
+					Int("records", sent).
+					Int("bytes", sentBytes).
+					Int("avgBytes", sentBytes/sent).
+					Msg("Telemetry payload sizes")
+			}
 			return
 		case <-ticker.C:
 			for _, vehicle := range vehicles {
 				// Update vehicle state
-				updateVehicleState(vehicle)
+				updateVehicleState(vehicle, cfg.UpdateInterval)
 
 				// Create telemetry payload
 				telemetry := TelemetryPayload{
@@ -221,17 +257,23 @@ func runSimulation(ctx context.Context, cfg Config, vehicles []*SimulatedVehicle
 					EngineTemp:   vehicle.EngineTemp,
 					EngineRPM:    vehicle.EngineRPM,
 					Heading:      vehicle.Heading,
+					Ignition:     vehicle.IsMoving,
+					ChargingCurrent: vehicle.ChargingCurrent,
 				}
 
 				// Send telemetry
-				if err := sendTelemetry(client, cfg.APIURL, telemetry); err != nil {
+				size, err := sendTelemetry(client, cfg.APIURL, cfg.Format, vehicle.DeviceKey, telemetry)
+				if err != nil {
 					logger.Error().
 						Err(err).
 						Str("vehicleId", vehicle.ID.String()).
 						Msg("Failed to send telemetry")
 				} else {
+					sent++
+					sentBytes += size
 					logger.Debug().
 						Str("vehicleId", vehicle.ID.String()).
+						Int("bytes", size).
 						Float64("speed", vehicle.Speed).
 						Int("battery", vehicle.BatteryLevel).
 						Msg("Telemetry sent")
@@ -241,7 +283,9 @@ func runSimulation(ctx context.Context, cfg Config, vehicles []*SimulatedVehicle
 	}
 }
 
-func updateVehicleState(v *SimulatedVehicle) {
+// updateVehicleState advances a vehicle by one update interval
+func updateVehicleState(v *SimulatedVehicle, interval time.Duration) {
+	v.ChargingCurrent = 0
 	switch v.Status {
 	case "active":
 		// Vehicle is moving
@@ -250,18 +294,28 @@ func updateVehicleState(v *SimulatedVehicle) {
 		// Update speed (30-80 km/h with some variation)
 		v.Speed = 30 + rand.Float64()*50 + (rand.Float64()-0.5)*10
 		
-		// Move along route
-		if v.RouteIndex < len(v.Route)-1 {
-			v.RouteIndex++
-		} else {
-			v.RouteIndex = 0 // Loop route
+		// Drive along the route, looping back to its start, as far as the
+		// speed allows in one update
+		from := v.Location
+		step := v.Speed * 1000 / 3600 * interval.Seconds() // meters
+		for step > 0 {
+			next := v.Route[(v.RouteIndex+1)%len(v.Route)]
+			remaining := distanceMeters(v.Location, next)
+			if remaining > step {
+				f := step / remaining
+				v.Location = Location{
+					Lat: v.Location.Lat + (next.Lat-v.Location.Lat)*f,
+					Lng: v.Location.Lng + (next.Lng-v.Location.Lng)*f,
+				}
+				break
+			}
+			v.Location = next
+			v.RouteIndex = (v.RouteIndex + 1) % len(v.Route)
+			step -= remaining
 		}
-		v.Location = v.Route[v.RouteIndex]
 		
 		// Calculate heading
-		if v.RouteIndex > 0 {
-			v.Heading = calculateHeading(v.Route[v.RouteIndex-1], v.Location)
-		}
+		v.Heading = calculateHeading(from, v.Location)
 		
 		// Drain battery (0.1-0.3% per update)
 		v.BatteryLevel = max(0, v.BatteryLevel-int(rand.Float64()*0.3))
@@ -270,12 +324,18 @@ func updateVehicleState(v *SimulatedVehicle) {
 		v.EngineTemp = 85 + rand.Float64()*15
 		v.EngineRPM = 2000 + rand.Intn(2000)
 
+		// Small chance to park, ending the trip
+		if rand.Float64() < 0.01 {
+			v.Status = "idle"
+		}
+
 	case "charging":
 		// Vehicle is stationary, charging
 		v.IsMoving = false
 		v.Speed = 0
 		v.EngineRPM = 0
-		v.EngineTemp = max(20, v.EngineTemp-0.5) // Cooling down
+		v.EngineTemp = math.Max(20, v.EngineTemp-0.5) // Cooling down
+		v.ChargingCurrent = 32 + rand.Float64()*16
 		
 		// Charge battery (0.5-1% per update)
 		v.BatteryLevel = min(100, v.BatteryLevel+int(0.5+rand.Float64()*0.5))
@@ -290,7 +350,7 @@ func updateVehicleState(v *SimulatedVehicle) {
 		v.IsMoving = false
 		v.Speed = 0
 		v.EngineRPM = 0
-		v.EngineTemp = max(20, v.EngineTemp-0.2)
+		v.EngineTemp = math.Max(20, v.EngineTemp-0.2)
 		
 		// Small chance to start moving
 		if rand.Float64() < 0.05 && v.BatteryLevel > 20 {
@@ -310,6 +370,10 @@ func updateVehicleState(v *SimulatedVehicle) {
 	}
 }
 
+func distanceMeters(a, b Location) float64 {
+	return domain.DistanceMeters(domain.Location{Lat: a.Lat, Lng: a.Lng}, domain.Location{Lat: b.Lat, Lng: b.Lng})
+}
+
 func calculateHeading(from, to Location) float64 {
 	dLng := to.Lng - from.Lng
 	y := math.Sin(dLng) * math.Cos(to.Lat)
@@ -318,27 +382,51 @@ func calculateHeading(from, to Location) float64 {
 	return math.Mod(heading+360, 360)
 }
 
-func sendTelemetry(client *http.Client, apiURL string, telemetry TelemetryPayload) error {
-	data, err := json.Marshal(telemetry)
+// sendTelemetry posts a payload encoded in format and returns its size
+func sendTelemetry(client *http.Client, apiURL, format, deviceKey string, telemetry TelemetryPayload) (int, error) {
+	data, err := encodeTelemetry(format, telemetry)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	resp, err := client.Post(
-		apiURL+"/api/v1/telemetry",
-		"application/json",
-		bytes.NewBuffer(data),
-	)
+	req, err := http.NewRequest(http.MethodPost, apiURL+"/api/v1/telemetry", bytes.NewBuffer(data))
 	if err != nil {
-		return err
+		return 0, err
+	}
+	req.Header.Set("Content-Type", contentTypes[format])
+	if deviceKey != "" {
+		req.Header.Set("X-Device-Key", deviceKey)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("API returned status %d", resp.StatusCode)
+		return 0, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
-	return nil
+	return len(data), nil
+}
+
+// loadDeviceKeys reads a JSON object mapping vehicle IDs to the device keys
+// issued for them. No file means no keys.
+func loadDeviceKeys(path string) (map[string]string, error) {
+	keys := make(map[string]string)
+	if path == "" {
+		return keys, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return nil, fmt.Errorf("parse device keys: %w", err)
+	}
+	return keys, nil
 }
 
 // Helper functions
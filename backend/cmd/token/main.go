@@ -0,0 +1,53 @@
+// Command token mints HS256 access tokens signed with AUTH_JWT_SECRET, for
+// local development and scripts. Production tokens should come from the
+// identity provider whose keys are configured in AUTH_JWKS_FILE.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+	"github.com/sid-romero/fleetpulse/internal/config"
+)
+
+func main() {
+	// Default to the seeded development admin
+	subject := flag.String("sub", "e1111111-1111-1111-1111-111111111111", "user ID to issue the token for")
+	ttl := flag.Duration("ttl", 12*time.Hour, "token lifetime")
+	flag.Parse()
+
+	_ = godotenv.Load()
+	cfg := config.Load()
+
+	if cfg.Auth.JWTSecret == "" {
+		fmt.Fprintln(os.Stderr, "AUTH_JWT_SECRET is not set")
+		os.Exit(1)
+	}
+	if _, err := uuid.Parse(*subject); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid user ID %q\n", *subject)
+		os.Exit(1)
+	}
+
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		Subject:   *subject,
+		Issuer:    cfg.Auth.Issuer,
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(*ttl)),
+	}
+	if cfg.Auth.Audience != "" {
+		claims.Audience = jwt.ClaimStrings{cfg.Auth.Audience}
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Auth.JWTSecret))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "sign token: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Println(token)
+}
@@ -0,0 +1,139 @@
+package alerting
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// Engine evaluates alert rules against incoming telemetry, tracking per
+// vehicle and rule whether the rule is currently triggered
+type Engine struct {
+	rules []Rule
+	state map[stateKey]*ruleState
+	mu    sync.Mutex
+}
+
+type stateKey struct {
+	vehicleID uuid.UUID
+	rule      int
+}
+
+type ruleState struct {
+	triggered bool
+	lastFired time.Time
+}
+
+// Violation is a rule newly breached by a telemetry record
+type Violation struct {
+	Rule      Rule
+	Value     float64
+	Vehicle   domain.Vehicle
+	Telemetry domain.Telemetry
+}
+
+// NewEngine creates an Engine for the given rules
+func NewEngine(rules []Rule) *Engine {
+	return &Engine{
+		rules: rules,
+		state: make(map[stateKey]*ruleState),
+	}
+}
+
+// Evaluate returns the rules breached by telemetry that should raise an alert.
+// A rule that is already triggered for the vehicle stays silent until the
+// value recovers past its clear level, and a re-trigger within the rule's
+// cooldown is suppressed. Time is taken from the telemetry timestamp so
+// replayed batches behave like live data.
+func (e *Engine) Evaluate(vehicle *domain.Vehicle, telemetry *domain.Telemetry) []Violation {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var violations []Violation
+	for i := range e.rules {
+		rule := &e.rules[i]
+		value := metricInfo[rule.Metric].value(telemetry)
+
+		key := stateKey{vehicleID: vehicle.ID, rule: i}
+		state, ok := e.state[key]
+		if !ok {
+			state = &ruleState{}
+			e.state[key] = state
+		}
+
+		if state.triggered {
+			if rule.recovered(value) {
+				state.triggered = false
+			}
+			continue
+		}
+		if !rule.breached(value) {
+			continue
+		}
+
+		state.triggered = true
+		if !state.lastFired.IsZero() && telemetry.Timestamp.Sub(state.lastFired) < rule.Cooldown {
+			continue
+		}
+		state.lastFired = telemetry.Timestamp
+
+		violations = append(violations, Violation{
+			Rule:      *rule,
+			Value:     value,
+			Vehicle:   *vehicle,
+			Telemetry: *telemetry,
+		})
+	}
+	return violations
+}
+
+// Alert builds the alert record for the violation
+func (v *Violation) Alert() *domain.Alert {
+	info := metricInfo[v.Rule.Metric]
+	vehicleID := v.Vehicle.ID
+
+	return &domain.Alert{
+		VehicleID: &vehicleID,
+		Type:      v.Rule.Type,
+		Severity:  v.Rule.Severity,
+		Status:    domain.AlertStatusActive,
+		Message: fmt.Sprintf("%s: %s %s%s %s threshold of %s%s",
+			v.Vehicle.Name, info.label,
+			formatValue(v.Value), info.unit, v.Rule.Operator,
+			formatValue(v.Rule.Threshold), info.unit),
+		CreatedAt: v.Telemetry.Timestamp,
+	}
+}
+
+// Event builds the domain event for the violation, or returns nil when the
+// rule's metric has no dedicated event type
+func (v *Violation) Event() (*domain.Event, error) {
+	source := v.Vehicle.ID.String()
+
+	switch {
+	case v.Rule.Metric == MetricSpeed && v.Rule.Operator == OperatorAbove:
+		return domain.NewEvent(domain.EventTypeSpeedExceeded, source, domain.SpeedExceededData{
+			VehicleID:    v.Vehicle.ID,
+			CurrentSpeed: v.Telemetry.Speed,
+			SpeedLimit:   float32(v.Rule.Threshold),
+			Location:     v.Telemetry.Location,
+		})
+	case v.Rule.Metric == MetricBattery && v.Rule.Operator == OperatorBelow:
+		return domain.NewEvent(domain.EventTypeBatteryLow, source, domain.BatteryLowData{
+			VehicleID:    v.Vehicle.ID,
+			BatteryLevel: v.Telemetry.BatteryLevel,
+			Range:        v.Vehicle.Range,
+			Location:     v.Telemetry.Location,
+		})
+	}
+	return nil, nil
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
@@ -0,0 +1,173 @@
+package alerting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+func TestEngineEvaluate(t *testing.T) {
+	rule := Rule{
+		Type:      "speed_excess",
+		Metric:    MetricSpeed,
+		Operator:  OperatorAbove,
+		Threshold: 120,
+		Clear:     110,
+		Severity:  domain.AlertSeverityWarning,
+		Cooldown:  5 * time.Minute,
+	}
+
+	// reading is a speed some minutes into the test
+	type reading struct {
+		minute int
+		speed  float32
+	}
+
+	tests := []struct {
+		name     string
+		readings []reading
+		fired    []int // indexes of the readings that raise an alert
+	}{
+		{
+			name:     "within the limit",
+			readings: []reading{{0, 100}, {1, 120}},
+		},
+		{
+			name:     "breach fires once while triggered",
+			readings: []reading{{0, 130}, {1, 135}, {2, 140}},
+			fired:    []int{0},
+		},
+		{
+			name:     "no re-arm within the hysteresis margin",
+			readings: []reading{{0, 130}, {10, 115}, {20, 130}},
+			fired:    []int{0},
+		},
+		{
+			name:     "re-arms once recovered",
+			readings: []reading{{0, 130}, {10, 105}, {20, 130}},
+			fired:    []int{0, 2},
+		},
+		{
+			name:     "re-trigger within the cooldown suppressed",
+			readings: []reading{{0, 130}, {1, 105}, {2, 130}, {3, 105}, {6, 130}},
+			fired:    []int{0, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := NewEngine([]Rule{rule})
+			vehicle := &domain.Vehicle{ID: uuid.New(), Name: "Test vehicle"}
+			start := time.Now().Add(-time.Hour)
+
+			var fired []int
+			for i, r := range tt.readings {
+				violations := engine.Evaluate(vehicle, &domain.Telemetry{
+					VehicleID: vehicle.ID,
+					Timestamp: start.Add(time.Duration(r.minute) * time.Minute),
+					Speed:     r.speed,
+				})
+				if len(violations) > 0 {
+					fired = append(fired, i)
+				}
+			}
+			if !equalInts(fired, tt.fired) {
+				t.Errorf("fired on readings %v, want %v", fired, tt.fired)
+			}
+		})
+	}
+}
+
+func TestEngineEvaluateTracksVehiclesApart(t *testing.T) {
+	engine := NewEngine([]Rule{{
+		Type:      "battery_low",
+		Metric:    MetricBattery,
+		Operator:  OperatorBelow,
+		Threshold: 20,
+		Clear:     25,
+		Severity:  domain.AlertSeverityWarning,
+	}})
+	now := time.Now()
+
+	for i := 0; i < 2; i++ {
+		vehicle := &domain.Vehicle{ID: uuid.New(), Name: "Test vehicle"}
+		violations := engine.Evaluate(vehicle, &domain.Telemetry{VehicleID: vehicle.ID, Timestamp: now, BatteryLevel: 10})
+		if len(violations) != 1 {
+			t.Errorf("vehicle %d: %d violations, want 1", i, len(violations))
+		}
+	}
+}
+
+func TestViolationAlertAndEvent(t *testing.T) {
+	vehicle := domain.Vehicle{ID: uuid.New(), Name: "Van 7"}
+	telemetry := domain.Telemetry{VehicleID: vehicle.ID, Timestamp: time.Now(), Speed: 131.26, BatteryLevel: 10}
+
+	tests := []struct {
+		name      string
+		rule      Rule
+		message   string
+		eventType domain.EventType // empty when the rule has no dedicated event
+	}{
+		{
+			name:      "speed above",
+			rule:      Rule{Type: "speed_excess", Metric: MetricSpeed, Operator: OperatorAbove, Threshold: 120, Severity: domain.AlertSeverityWarning},
+			message:   "Van 7: speed 131.3 km/h above threshold of 120 km/h",
+			eventType: domain.EventTypeSpeedExceeded,
+		},
+		{
+			name:      "battery below",
+			rule:      Rule{Type: "battery_low", Metric: MetricBattery, Operator: OperatorBelow, Threshold: 20, Severity: domain.AlertSeverityWarning},
+			message:   "Van 7: battery 10% below threshold of 20%",
+			eventType: domain.EventTypeBatteryLow,
+		},
+		{
+			name:    "engine temperature above",
+			rule:    Rule{Type: "engine_overheat", Metric: MetricEngineTemp, Operator: OperatorAbove, Threshold: -1, Severity: domain.AlertSeverityCritical},
+			message: "Van 7: engine temperature 0°C above threshold of -1°C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			violation := Violation{
+				Rule:      tt.rule,
+				Value:     metricInfo[tt.rule.Metric].value(&telemetry),
+				Vehicle:   vehicle,
+				Telemetry: telemetry,
+			}
+
+			alert := violation.Alert()
+			if alert.Message != tt.message {
+				t.Errorf("message = %q, want %q", alert.Message, tt.message)
+			}
+			if alert.Type != tt.rule.Type || alert.Severity != tt.rule.Severity || *alert.VehicleID != vehicle.ID {
+				t.Errorf("alert = %+v, want type %s severity %s for %s", alert, tt.rule.Type, tt.rule.Severity, vehicle.ID)
+			}
+
+			event, err := violation.Event()
+			if err != nil {
+				t.Fatalf("Event: %v", err)
+			}
+			switch {
+			case tt.eventType == "" && event != nil:
+				t.Errorf("event = %s, want none", event.Type)
+			case tt.eventType != "" && (event == nil || event.Type != tt.eventType):
+				t.Errorf("event = %v, want %s", event, tt.eventType)
+			}
+		})
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,190 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// Metric identifies the telemetry value a rule watches
+type Metric string
+
+const (
+	MetricSpeed      Metric = "speed"
+	MetricBattery    Metric = "battery"
+	MetricEngineTemp Metric = "engine_temp"
+	MetricEngineRPM  Metric = "engine_rpm"
+)
+
+// Operator defines which side of the threshold is a violation
+type Operator string
+
+const (
+	OperatorAbove Operator = "above"
+	OperatorBelow Operator = "below"
+)
+
+// Rule raises an alert when a metric crosses Threshold. The rule re-arms only
+// once the value has recovered past Clear (hysteresis), and never fires more
+// than once per Cooldown for the same vehicle (de-duplication).
+type Rule struct {
+	Type      string               `json:"type"` // alert type, e.g. battery_low
+	Metric    Metric               `json:"metric"`
+	Operator  Operator             `json:"operator"`
+	Threshold float64              `json:"threshold"`
+	Clear     float64              `json:"clear"`
+	Severity  domain.AlertSeverity `json:"severity"`
+	Cooldown  time.Duration        `json:"-"`
+}
+
+// DefaultRules builds the built-in rule set from configured thresholds
+func DefaultRules(cfg config.AlertsConfig) []Rule {
+	margin := func(threshold float64) float64 { return threshold * cfg.Hysteresis }
+
+	return []Rule{
+		{
+			Type:      "speed_excess",
+			Metric:    MetricSpeed,
+			Operator:  OperatorAbove,
+			Threshold: cfg.SpeedLimit,
+			Clear:     cfg.SpeedLimit - margin(cfg.SpeedLimit),
+			Severity:  domain.AlertSeverityWarning,
+			Cooldown:  cfg.Cooldown,
+		},
+		{
+			Type:      "battery_low",
+			Metric:    MetricBattery,
+			Operator:  OperatorBelow,
+			Threshold: cfg.BatteryLow,
+			Clear:     cfg.BatteryLow + margin(cfg.BatteryLow),
+			Severity:  domain.AlertSeverityWarning,
+			Cooldown:  cfg.Cooldown,
+		},
+		{
+			Type:      "battery_critical",
+			Metric:    MetricBattery,
+			Operator:  OperatorBelow,
+			Threshold: cfg.BatteryCritical,
+			Clear:     cfg.BatteryCritical + margin(cfg.BatteryCritical),
+			Severity:  domain.AlertSeverityCritical,
+			Cooldown:  cfg.Cooldown,
+		},
+		{
+			Type:      "engine_overheat",
+			Metric:    MetricEngineTemp,
+			Operator:  OperatorAbove,
+			Threshold: cfg.EngineTempMax,
+			Clear:     cfg.EngineTempMax - margin(cfg.EngineTempMax),
+			Severity:  domain.AlertSeverityCritical,
+			Cooldown:  cfg.Cooldown,
+		},
+		{
+			Type:      "engine_rpm_high",
+			Metric:    MetricEngineRPM,
+			Operator:  OperatorAbove,
+			Threshold: cfg.EngineRPMMax,
+			Clear:     cfg.EngineRPMMax - margin(cfg.EngineRPMMax),
+			Severity:  domain.AlertSeverityInfo,
+			Cooldown:  cfg.Cooldown,
+		},
+	}
+}
+
+// ruleSpec is the on-disk form of a Rule
+type ruleSpec struct {
+	Rule
+	Clear    *float64 `json:"clear"` // nil when unset, as 0 is a valid level
+	Cooldown string   `json:"cooldown"`
+}
+
+// LoadRules reads a JSON array of rules from path. Rules without a cooldown
+// use defaultCooldown; rules without a clear value have no hysteresis. A
+// clear value past the threshold, which would leave the rule unable to stay
+// triggered, is rejected.
+func LoadRules(path string, defaultCooldown time.Duration) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read alert rules: %w", err)
+	}
+
+	var specs []ruleSpec
+	if err := json.Unmarshal(data, &specs); err != nil {
+		return nil, fmt.Errorf("parse alert rules: %w", err)
+	}
+
+	rules := make([]Rule, 0, len(specs))
+	for i, spec := range specs {
+		rule := spec.Rule
+		name := fmt.Sprintf("alert rule %d", i)
+		if rule.Type != "" {
+			name += fmt.Sprintf(" (%s)", rule.Type)
+		}
+		rule.Clear = rule.Threshold
+		if spec.Clear != nil {
+			rule.Clear = *spec.Clear
+		}
+		rule.Cooldown = defaultCooldown
+		if spec.Cooldown != "" {
+			if rule.Cooldown, err = time.ParseDuration(spec.Cooldown); err != nil {
+				return nil, fmt.Errorf("%s: invalid cooldown: %w", name, err)
+			}
+		}
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r *Rule) validate() error {
+	if r.Type == "" {
+		return fmt.Errorf("type is required")
+	}
+	if _, ok := metricInfo[r.Metric]; !ok {
+		return fmt.Errorf("unknown metric %q", r.Metric)
+	}
+	if r.Operator != OperatorAbove && r.Operator != OperatorBelow {
+		return fmt.Errorf("unknown operator %q", r.Operator)
+	}
+	switch r.Severity {
+	case domain.AlertSeverityCritical, domain.AlertSeverityWarning, domain.AlertSeverityInfo:
+	default:
+		return fmt.Errorf("unknown severity %q", r.Severity)
+	}
+	if r.breached(r.Clear) {
+		return fmt.Errorf("clear level %g is %s the threshold of %g", r.Clear, r.Operator, r.Threshold)
+	}
+	return nil
+}
+
+// breached reports whether value violates the rule
+func (r *Rule) breached(value float64) bool {
+	if r.Operator == OperatorAbove {
+		return value > r.Threshold
+	}
+	return value < r.Threshold
+}
+
+// recovered reports whether value is back past the clear level
+func (r *Rule) recovered(value float64) bool {
+	if r.Operator == OperatorAbove {
+		return value <= r.Clear
+	}
+	return value >= r.Clear
+}
+
+var metricInfo = map[Metric]struct {
+	label string
+	unit  string
+	value func(*domain.Telemetry) float64
+}{
+	MetricSpeed:      {"speed", " km/h", func(t *domain.Telemetry) float64 { return float64(t.Speed) }},
+	MetricBattery:    {"battery", "%", func(t *domain.Telemetry) float64 { return float64(t.BatteryLevel) }},
+	MetricEngineTemp: {"engine temperature", "°C", func(t *domain.Telemetry) float64 { return float64(t.EngineTemp) }},
+	MetricEngineRPM:  {"engine RPM", "", func(t *domain.Telemetry) float64 { return float64(t.EngineRPM) }},
+}
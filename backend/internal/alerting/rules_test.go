@@ -0,0 +1,148 @@
+package alerting
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadRules(t *testing.T) {
+	const defaultCooldown = 10 * time.Minute
+
+	tests := []struct {
+		name     string
+		json     string
+		clear    float64
+		cooldown time.Duration
+		wantErr  string // substring of the error, empty for none
+	}{
+		{
+			name:     "clear and cooldown set",
+			json:     `[{"type": "speed_excess", "metric": "speed", "operator": "above", "threshold": 120, "clear": 110, "severity": "warning", "cooldown": "1m"}]`,
+			clear:    110,
+			cooldown: time.Minute,
+		},
+		{
+			name:     "clear defaults to the threshold",
+			json:     `[{"type": "speed_excess", "metric": "speed", "operator": "above", "threshold": 120, "severity": "warning"}]`,
+			clear:    120,
+			cooldown: defaultCooldown,
+		},
+		{
+			name:     "clear of zero kept",
+			json:     `[{"type": "moving_after_hours", "metric": "speed", "operator": "above", "threshold": 5, "clear": 0, "severity": "critical"}]`,
+			clear:    0,
+			cooldown: defaultCooldown,
+		},
+		{
+			name:     "clear at the threshold",
+			json:     `[{"type": "speed_excess", "metric": "speed", "operator": "above", "threshold": 120, "clear": 120, "severity": "warning"}]`,
+			clear:    120,
+			cooldown: defaultCooldown,
+		},
+		{
+			name:    "clear above an above threshold",
+			json:    `[{"type": "speed_excess", "metric": "speed", "operator": "above", "threshold": 120, "clear": 130, "severity": "warning"}]`,
+			wantErr: "alert rule 0 (speed_excess): clear level 130 is above the threshold of 120",
+		},
+		{
+			name:    "clear below a below threshold",
+			json:    `[{"type": "battery_low", "metric": "battery", "operator": "below", "threshold": 20, "clear": 10, "severity": "warning"}]`,
+			wantErr: "alert rule 0 (battery_low): clear level 10 is below the threshold of 20",
+		},
+		{
+			name:    "invalid cooldown",
+			json:    `[{"type": "speed_excess", "metric": "speed", "operator": "above", "threshold": 120, "severity": "warning", "cooldown": "soon"}]`,
+			wantErr: "invalid cooldown",
+		},
+		{
+			name:    "missing type",
+			json:    `[{"metric": "speed", "operator": "above", "threshold": 120, "severity": "warning"}]`,
+			wantErr: "alert rule 0: type is required",
+		},
+		{
+			name:    "unknown metric",
+			json:    `[{"type": "tire_pressure", "metric": "tire_pressure", "operator": "below", "threshold": 2, "severity": "warning"}]`,
+			wantErr: "unknown metric",
+		},
+		{
+			name:    "unknown operator",
+			json:    `[{"type": "speed_excess", "metric": "speed", "operator": "equals", "threshold": 120, "severity": "warning"}]`,
+			wantErr: "unknown operator",
+		},
+		{
+			name:    "unknown severity",
+			json:    `[{"type": "speed_excess", "metric": "speed", "operator": "above", "threshold": 120, "severity": "urgent"}]`,
+			wantErr: "unknown severity",
+		},
+		{
+			name:    "malformed file",
+			json:    `{"type": "speed_excess"}`,
+			wantErr: "parse alert rules",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "rules.json")
+			if err := os.WriteFile(path, []byte(tt.json), 0o600); err != nil {
+				t.Fatalf("write rules: %v", err)
+			}
+
+			rules, err := LoadRules(path, defaultCooldown)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadRules() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadRules: %v", err)
+			}
+			if len(rules) != 1 {
+				t.Fatalf("loaded %d rules, want 1", len(rules))
+			}
+			if rules[0].Clear != tt.clear {
+				t.Errorf("clear = %v, want %v", rules[0].Clear, tt.clear)
+			}
+			if rules[0].Cooldown != tt.cooldown {
+				t.Errorf("cooldown = %v, want %v", rules[0].Cooldown, tt.cooldown)
+			}
+		})
+	}
+}
+
+func TestRuleBreachedAndRecovered(t *testing.T) {
+	above := Rule{Operator: OperatorAbove, Threshold: 120, Clear: 110}
+	below := Rule{Operator: OperatorBelow, Threshold: 20, Clear: 25}
+
+	tests := []struct {
+		name      string
+		rule      Rule
+		value     float64
+		breached  bool
+		recovered bool
+	}{
+		{"above past the threshold", above, 121, true, false},
+		{"above at the threshold", above, 120, false, false},
+		{"above within the margin", above, 115, false, false},
+		{"above at the clear level", above, 110, false, true},
+		{"below past the threshold", below, 19, true, false},
+		{"below at the threshold", below, 20, false, false},
+		{"below within the margin", below, 22, false, false},
+		{"below at the clear level", below, 25, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.breached(tt.value); got != tt.breached {
+				t.Errorf("breached(%v) = %v, want %v", tt.value, got, tt.breached)
+			}
+			if got := tt.rule.recovered(tt.value); got != tt.recovered {
+				t.Errorf("recovered(%v) = %v, want %v", tt.value, got, tt.recovered)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/service"
+)
+
+// defaultAnalyticsWindow is the range analysed when neither from nor period
+// is given
+const defaultAnalyticsWindow = 7 * 24 * time.Hour
+
+// GetConsumptionAnalytics returns battery consumption per time bucket
+func (h *Handler) GetConsumptionAnalytics(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.analyticsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.analyticsService.GetConsumption(r.Context(), query)
+	if h.respondAnalyticsValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to fetch consumption analytics")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch consumption data")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, data)
+}
+
+// GetDistanceAnalytics returns distance traveled per time bucket
+func (h *Handler) GetDistanceAnalytics(w http.ResponseWriter, r *http.Request) {
+	query, ok := h.analyticsQuery(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.analyticsService.GetDistance(r.Context(), query)
+	if h.respondAnalyticsValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to fetch distance analytics")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch distance data")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, data)
+}
+
+// analyticsQuery parses from/to (or period, e.g. 7d or 12h), bucket, tz,
+// vehicleId and groupBy
+func (h *Handler) analyticsQuery(w http.ResponseWriter, r *http.Request) (service.AnalyticsQuery, bool) {
+	params := r.URL.Query()
+	from, to, ok := h.analyticsRange(w, r)
+	if !ok {
+		return service.AnalyticsQuery{}, false
+	}
+
+	query := service.AnalyticsQuery{
+		From:     from,
+		To:       to,
+		Bucket:   domain.BucketDay,
+		Location: time.UTC,
+		GroupBy:  params.Get("groupBy"),
+	}
+	if bucket := params.Get("bucket"); bucket != "" {
+		query.Bucket = domain.Bucket(bucket)
+	}
+	if tz := params.Get("tz"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", "tz must be an IANA time zone such as Europe/Paris")
+			return service.AnalyticsQuery{}, false
+		}
+		query.Location = loc
+	}
+	if vehicleID := params.Get("vehicleId"); vehicleID != "" {
+		id, err := uuid.Parse(vehicleID)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid vehicle ID format")
+			return service.AnalyticsQuery{}, false
+		}
+		query.VehicleID = &id
+	}
+	return query, true
+}
+
+// analyticsRange parses from/to, or period ending at to (default now)
+func (h *Handler) analyticsRange(w http.ResponseWriter, r *http.Request) (time.Time, time.Time, bool) {
+	window := defaultAnalyticsWindow
+	if period := r.URL.Query().Get("period"); period != "" {
+		var ok bool
+		if window, ok = parsePeriod(period); !ok {
+			h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", "period must be a number of days (e.g. 30d) or a duration (e.g. 12h)")
+			return time.Time{}, time.Time{}, false
+		}
+	}
+	return h.timeRange(w, r, window)
+}
+
+// parsePeriod accepts a number of days such as 30d, or a Go duration
+func parsePeriod(period string) (time.Duration, bool) {
+	if days, ok := strings.CutSuffix(period, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, false
+		}
+		return time.Duration(n) * 24 * time.Hour, true
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+func (h *Handler) respondAnalyticsValidationError(w http.ResponseWriter, err error) bool {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", err.Error())
+	return true
+}
@@ -0,0 +1,135 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/sid-romero/fleetpulse/internal/auth"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// DeviceKeyHeader carries a device API key on telemetry requests
+const DeviceKeyHeader = "X-Device-Key"
+
+// authGuard protects routes with JWT bearer authentication. With a nil
+// verifier authentication is disabled and every request is let through
+// anonymously, which is only meant for local development.
+type authGuard struct {
+	handler  *Handler
+	verifier *auth.Verifier
+}
+
+// authenticate requires a valid bearer token whose subject is an active
+// user, and stores that user on the request context. allowQueryToken also
+// accepts the token as ?access_token=, since browsers cannot set headers on
+// WebSocket upgrades.
+func (g *authGuard) authenticate(allowQueryToken bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if g.verifier == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h := g.handler
+
+			token := bearerToken(r)
+			if token == "" && allowQueryToken {
+				token = r.URL.Query().Get("access_token")
+			}
+			if token == "" {
+				h.respondUnauthorized(w, "Missing bearer token")
+				return
+			}
+
+			userID, err := g.verifier.Verify(token)
+			if err != nil {
+				h.logger.Debug().Err(err).Msg("Rejected bearer token")
+				h.respondUnauthorized(w, "Invalid or expired token")
+				return
+			}
+
+			user, err := h.userService.GetByID(r.Context(), userID)
+			if errors.Is(err, domain.ErrNotFound) || (err == nil && !user.IsActive) {
+				h.respondUnauthorized(w, "Unknown or disabled user")
+				return
+			}
+			if err != nil {
+				h.logger.Error().Err(err).Str("userId", userID.String()).Msg("Failed to load authenticated user")
+				h.respondError(w, http.StatusInternalServerError, "AUTH_ERROR", "Failed to authenticate request")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(auth.WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// require only lets through users whose role grants permission. Denials
+// are always answered with 403 FORBIDDEN.
+func (g *authGuard) require(permission auth.Permission) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if g.verifier == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := auth.UserFromContext(r.Context())
+			if !ok {
+				g.handler.respondUnauthorized(w, "Authentication required")
+				return
+			}
+			if !auth.Allowed(user.Role, permission) {
+				g.handler.respondError(w, http.StatusForbidden, "FORBIDDEN",
+					"Role "+string(user.Role)+" lacks permission "+string(permission))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// authenticateDevice admits telemetry sent with a device key, which binds
+// the request to the key's vehicle. Requests without a key fall back to
+// bearer authentication with the telemetry:ingest permission, as used by
+// fleet-wide gateways.
+func (g *authGuard) authenticateDevice(next http.Handler) http.Handler {
+	fallback := g.authenticate(false)(g.require(auth.PermissionTelemetryIngest)(next))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := g.handler
+
+		key := r.Header.Get(DeviceKeyHeader)
+		if key == "" {
+			fallback.ServeHTTP(w, r)
+			return
+		}
+
+		device, err := h.deviceKeyService.Authenticate(r.Context(), key)
+		if errors.Is(err, domain.ErrNotFound) {
+			h.respondUnauthorized(w, "Invalid or revoked device key")
+			return
+		}
+		if err != nil {
+			h.logger.Error().Err(err).Msg("Failed to verify device key")
+			h.respondError(w, http.StatusInternalServerError, "AUTH_ERROR", "Failed to authenticate request")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.WithDevice(r.Context(), device)))
+	})
+}
+
+func (h *Handler) respondUnauthorized(w http.ResponseWriter, message string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="fleetpulse"`)
+	h.respondError(w, http.StatusUnauthorized, "UNAUTHORIZED", message)
+}
+
+// bearerToken extracts the token from an "Authorization: Bearer" header
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,133 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// ========== Device Key Handlers ==========
+
+// ListDeviceKeys returns a vehicle's device keys, without their secrets
+func (h *Handler) ListDeviceKeys(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := h.deviceKeyVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.deviceKeyService.GetByVehicle(r.Context(), vehicleID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to fetch device keys")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch device keys")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, keys)
+}
+
+// IssueDeviceKey creates a key for a vehicle. The response is the only time
+// the key itself is returned.
+func (h *Handler) IssueDeviceKey(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := h.deviceKeyVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+			return
+		}
+	}
+
+	issued, err := h.deviceKeyService.Issue(r.Context(), vehicleID, req.Name)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to issue device key")
+		h.respondError(w, http.StatusInternalServerError, "CREATE_ERROR", "Failed to issue device key")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, issued)
+}
+
+// RotateDeviceKey revokes a key and returns its replacement
+func (h *Handler) RotateDeviceKey(w http.ResponseWriter, r *http.Request) {
+	vehicleID, keyID, ok := h.deviceKeyIDs(w, r)
+	if !ok {
+		return
+	}
+
+	issued, err := h.deviceKeyService.Rotate(r.Context(), vehicleID, keyID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Device key not found")
+		return
+	case errors.Is(err, domain.ErrConflict):
+		h.respondError(w, http.StatusConflict, "KEY_REVOKED", "Revoked keys cannot be rotated")
+		return
+	case err != nil:
+		h.logger.Error().Err(err).Str("keyId", keyID.String()).Msg("Failed to rotate device key")
+		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to rotate device key")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, issued)
+}
+
+// RevokeDeviceKey disables a key
+func (h *Handler) RevokeDeviceKey(w http.ResponseWriter, r *http.Request) {
+	vehicleID, keyID, ok := h.deviceKeyIDs(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.deviceKeyService.Revoke(r.Context(), vehicleID, keyID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Device key not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("keyId", keyID.String()).Msg("Failed to revoke device key")
+		h.respondError(w, http.StatusInternalServerError, "DELETE_ERROR", "Failed to revoke device key")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) deviceKeyVehicleID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid vehicle ID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *Handler) deviceKeyIDs(w http.ResponseWriter, r *http.Request) (uuid.UUID, uuid.UUID, bool) {
+	vehicleID, ok := h.deviceKeyVehicleID(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	keyID, err := uuid.Parse(chi.URLParam(r, "keyId"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid device key ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return vehicleID, keyID, true
+}
@@ -0,0 +1,310 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// ========== Driver Handlers ==========
+
+// ListDrivers returns all drivers
+func (h *Handler) ListDrivers(w http.ResponseWriter, r *http.Request) {
+	drivers, err := h.driverService.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to fetch drivers")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch drivers")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, drivers)
+}
+
+// GetDriver returns a single driver
+func (h *Handler) GetDriver(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.driverID(w, r)
+	if !ok {
+		return
+	}
+
+	driver, err := h.driverService.GetByID(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Driver not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("driverId", id.String()).Msg("Failed to fetch driver")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch driver")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, driver)
+}
+
+// CreateDriver creates a new driver
+func (h *Handler) CreateDriver(w http.ResponseWriter, r *http.Request) {
+	var driver domain.Driver
+	if err := json.NewDecoder(r.Body).Decode(&driver); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	created, err := h.driverService.Create(r.Context(), &driver)
+	if h.respondDriverError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to create driver")
+		h.respondError(w, http.StatusInternalServerError, "CREATE_ERROR", "Failed to create driver")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateDriver updates an existing driver; omitted fields keep their
+// current values
+func (h *Handler) UpdateDriver(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := h.driverID(w, r)
+	if !ok {
+		return
+	}
+
+	driver, err := h.driverService.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Driver not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("driverId", id.String()).Msg("Failed to fetch driver")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch driver")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(driver); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	driver.ID = id
+	updated, err := h.driverService.Update(ctx, driver)
+	if h.respondDriverError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("driverId", id.String()).Msg("Failed to update driver")
+		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to update driver")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteDriver unassigns a driver from their vehicle and removes them
+func (h *Handler) DeleteDriver(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.driverID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.driverService.Delete(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Driver not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("driverId", id.String()).Msg("Failed to delete driver")
+		h.respondError(w, http.StatusInternalServerError, "DELETE_ERROR", "Failed to delete driver")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListDriverAssignments returns the vehicles a driver was assigned to,
+// latest first
+func (h *Handler) ListDriverAssignments(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.driverID(w, r)
+	if !ok {
+		return
+	}
+
+	assignments, err := h.driverService.GetDriverAssignments(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Driver not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("driverId", id.String()).Msg("Failed to fetch driver assignments")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch driver assignments")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, assignments)
+}
+
+// assignDriverRequest is the body of AssignDriver
+type assignDriverRequest struct {
+	DriverID uuid.UUID `json:"driverId"`
+}
+
+// AssignDriver makes a driver the driver of a vehicle, unassigning the
+// vehicle's previous driver and the driver's previous vehicle
+func (h *Handler) AssignDriver(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := h.assignmentVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	var req assignDriverRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	assignment, err := h.driverService.Assign(r.Context(), vehicleID, req.DriverID)
+	if h.respondAssignmentError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to assign driver")
+		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to assign driver")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, assignment)
+}
+
+// UnassignDriver removes the driver of a vehicle and returns the closed
+// assignment
+func (h *Handler) UnassignDriver(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := h.assignmentVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	assignment, err := h.driverService.Unassign(r.Context(), vehicleID)
+	if h.respondAssignmentError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to unassign driver")
+		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to unassign driver")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, assignment)
+}
+
+// ListVehicleAssignments returns the drivers a vehicle was assigned to,
+// latest first. With at, only the assignment covering that time is
+// returned, attributing the vehicle's telemetry or alerts at that time to a
+// driver.
+func (h *Handler) ListVehicleAssignments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vehicleID, ok := h.assignmentVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	if v := r.URL.Query().Get("at"); v != "" {
+		at, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", "at must be an RFC 3339 timestamp")
+			return
+		}
+		h.respondAssignmentAt(w, r, vehicleID, at)
+		return
+	}
+
+	assignments, err := h.driverService.GetVehicleAssignments(ctx, vehicleID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to fetch driver assignments")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch driver assignments")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, assignments)
+}
+
+// respondAssignmentAt responds with the assignment covering at, as a list
+// that is empty when nobody was assigned
+func (h *Handler) respondAssignmentAt(w http.ResponseWriter, r *http.Request, vehicleID uuid.UUID, at time.Time) {
+	ctx := r.Context()
+	if _, err := h.vehicleService.GetByID(ctx, vehicleID); errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
+
+	assignments := []domain.DriverAssignment{}
+	assignment, err := h.driverService.AssignmentAt(ctx, vehicleID, at)
+	switch {
+	case err == nil:
+		assignments = append(assignments, *assignment)
+	case !errors.Is(err, domain.ErrNotFound):
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to fetch driver assignment")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch driver assignment")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, assignments)
+}
+
+func (h *Handler) driverID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid driver ID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *Handler) assignmentVehicleID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid vehicle ID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondDriverError reports validation failures and duplicate emails
+func (h *Handler) respondDriverError(w http.ResponseWriter, err error) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.respondError(w, http.StatusBadRequest, "INVALID_DRIVER", err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		h.respondError(w, http.StatusConflict, "EMAIL_TAKEN", "A driver with this email already exists")
+	default:
+		return false
+	}
+	return true
+}
+
+// respondAssignmentError reports unknown drivers, missing vehicles and
+// unassigning a vehicle without a driver
+func (h *Handler) respondAssignmentError(w http.ResponseWriter, err error) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.respondError(w, http.StatusBadRequest, "INVALID_ASSIGNMENT", err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+	case errors.Is(err, domain.ErrConflict):
+		h.respondError(w, http.StatusConflict, "NOT_ASSIGNED", err.Error())
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// ========== Geofence Handlers ==========
+
+// ListGeofences returns all geofences
+func (h *Handler) ListGeofences(w http.ResponseWriter, r *http.Request) {
+	geofences, err := h.geofenceService.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to fetch geofences")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch geofences")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, geofences)
+}
+
+// GetGeofence returns a single geofence
+func (h *Handler) GetGeofence(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.geofenceID(w, r)
+	if !ok {
+		return
+	}
+
+	geofence, err := h.geofenceService.GetByID(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Geofence not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("geofenceId", id.String()).Msg("Failed to fetch geofence")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch geofence")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, geofence)
+}
+
+// CreateGeofence creates a new geofence; geofences are active unless the
+// body says otherwise
+func (h *Handler) CreateGeofence(w http.ResponseWriter, r *http.Request) {
+	geofence := domain.Geofence{IsActive: true}
+	if err := json.NewDecoder(r.Body).Decode(&geofence); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	created, err := h.geofenceService.Create(r.Context(), &geofence)
+	if h.respondGeofenceValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to create geofence")
+		h.respondError(w, http.StatusInternalServerError, "CREATE_ERROR", "Failed to create geofence")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateGeofence updates an existing geofence; omitted fields keep their
+// current values
+func (h *Handler) UpdateGeofence(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := h.geofenceID(w, r)
+	if !ok {
+		return
+	}
+
+	geofence, err := h.geofenceService.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Geofence not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("geofenceId", id.String()).Msg("Failed to fetch geofence")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch geofence")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(geofence); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	geofence.ID = id
+	updated, err := h.geofenceService.Update(ctx, geofence)
+	if h.respondGeofenceValidationError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("geofenceId", id.String()).Msg("Failed to update geofence")
+		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to update geofence")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteGeofence removes a geofence
+func (h *Handler) DeleteGeofence(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.geofenceID(w, r)
+	if !ok {
+		return
+	}
+
+	err := h.geofenceService.Delete(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Geofence not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("geofenceId", id.String()).Msg("Failed to delete geofence")
+		h.respondError(w, http.StatusInternalServerError, "DELETE_ERROR", "Failed to delete geofence")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) geofenceID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid geofence ID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func (h *Handler) respondGeofenceValidationError(w http.ResponseWriter, err error) bool {
+	var validationErr *domain.ValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	h.respondError(w, http.StatusBadRequest, "INVALID_GEOFENCE", err.Error())
+	return true
+}
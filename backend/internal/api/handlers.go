@@ -2,6 +2,8 @@ package api
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,17 +11,27 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/codec"
 	"github.com/sid-romero/fleetpulse/internal/domain"
 	"github.com/sid-romero/fleetpulse/internal/service"
 )
 
 // Handler holds all HTTP handlers
 type Handler struct {
-	vehicleService   *service.VehicleService
-	alertService     *service.AlertService
-	telemetryService *service.TelemetryService
-	analyticsService *service.AnalyticsService
-	logger           zerolog.Logger
+	vehicleService     *service.VehicleService
+	alertService       *service.AlertService
+	telemetryService   *service.TelemetryService
+	analyticsService   *service.AnalyticsService
+	geofenceService    *service.GeofenceService
+	userService        *service.UserService
+	deviceKeyService   *service.DeviceKeyService
+	tripService        *service.TripService
+	retentionService   *service.RetentionService
+	maintenanceService *service.MaintenanceService
+	driverService      *service.DriverService
+	scoringService     *service.ScoringService
+	utilizationService *service.UtilizationService
+	logger             zerolog.Logger
 }
 
 // NewHandler creates a new Handler
@@ -28,14 +40,32 @@ func NewHandler(
 	alertService *service.AlertService,
 	telemetryService *service.TelemetryService,
 	analyticsService *service.AnalyticsService,
+	geofenceService *service.GeofenceService,
+	userService *service.UserService,
+	deviceKeyService *service.DeviceKeyService,
+	tripService *service.TripService,
+	retentionService *service.RetentionService,
+	maintenanceService *service.MaintenanceService,
+	driverService *service.DriverService,
+	scoringService *service.ScoringService,
+	utilizationService *service.UtilizationService,
 	logger zerolog.Logger,
 ) *Handler {
 	return &Handler{
-		vehicleService:   vehicleService,
-		alertService:     alertService,
-		telemetryService: telemetryService,
-		analyticsService: analyticsService,
-		logger:           logger,
+		vehicleService:     vehicleService,
+		alertService:       alertService,
+		telemetryService:   telemetryService,
+		analyticsService:   analyticsService,
+		geofenceService:    geofenceService,
+		userService:        userService,
+		deviceKeyService:   deviceKeyService,
+		tripService:        tripService,
+		retentionService:   retentionService,
+		maintenanceService: maintenanceService,
+		driverService:      driverService,
+		scoringService:     scoringService,
+		utilizationService: utilizationService,
+		logger:             logger,
 	}
 }
 
@@ -49,8 +79,9 @@ type APIResponse struct {
 }
 
 type APIError struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
+	Code    string      `json:"code"`
+	Message string      `json:"message"`
+	Details interface{} `json:"details,omitempty"`
 }
 
 type APIMeta struct {
@@ -75,6 +106,12 @@ func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{
 }
 
 func (h *Handler) respondError(w http.ResponseWriter, status int, code, message string) {
+	h.respondErrorDetails(w, status, code, message, nil)
+}
+
+// respondErrorDetails writes an error with structured details, such as the
+// records a batch was rejected for
+func (h *Handler) respondErrorDetails(w http.ResponseWriter, status int, code, message string, details interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	
@@ -83,6 +120,7 @@ func (h *Handler) respondError(w http.ResponseWriter, status int, code, message
 		Error: &APIError{
 			Code:    code,
 			Message: message,
+			Details: details,
 		},
 	}
 	
@@ -141,9 +179,13 @@ func (h *Handler) GetVehicle(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	vehicle, err := h.vehicleService.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Str("vehicleId", idStr).Msg("Failed to fetch vehicle")
-		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch vehicle")
 		return
 	}
 	
@@ -160,7 +202,13 @@ func (h *Handler) CreateVehicle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
+	// Drivers are assigned through PUT /vehicles/{id}/driver, which keeps
+	// the assignment history
+	vehicle.DriverID, vehicle.Driver = nil, nil
 	created, err := h.vehicleService.Create(ctx, &vehicle)
+	if h.respondVehicleError(w, err) {
+		return
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Msg("Failed to create vehicle")
 		h.respondError(w, http.StatusInternalServerError, "CREATE_ERROR", "Failed to create vehicle")
@@ -181,14 +229,32 @@ func (h *Handler) UpdateVehicle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	var vehicle domain.Vehicle
-	if err := json.NewDecoder(r.Body).Decode(&vehicle); err != nil {
+	vehicle, err := h.vehicleService.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", idStr).Msg("Failed to fetch vehicle")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch vehicle")
+		return
+	}
+	
+	// Decode onto the stored vehicle so omitted fields keep their current
+	// values; the driver only changes through PUT /vehicles/{id}/driver
+	driverID, driver := vehicle.DriverID, vehicle.Driver
+	vehicle.DriverID, vehicle.Driver = nil, nil
+	if err := json.NewDecoder(r.Body).Decode(vehicle); err != nil {
 		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
 		return
 	}
 	
 	vehicle.ID = id
-	updated, err := h.vehicleService.Update(ctx, &vehicle)
+	vehicle.DriverID, vehicle.Driver = driverID, driver
+	updated, err := h.vehicleService.Update(ctx, vehicle)
+	if h.respondVehicleError(w, err) {
+		return
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Str("vehicleId", idStr).Msg("Failed to update vehicle")
 		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to update vehicle")
@@ -198,7 +264,28 @@ func (h *Handler) UpdateVehicle(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, updated)
 }
 
-// GetVehicleTelemetry returns telemetry data for a vehicle
+// respondVehicleError reports unknown statuses and status changes the
+// vehicle state machine does not allow
+func (h *Handler) respondVehicleError(w http.ResponseWriter, err error) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.respondError(w, http.StatusBadRequest, "INVALID_VEHICLE", err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		h.respondError(w, http.StatusConflict, "INVALID_TRANSITION", err.Error())
+	default:
+		return false
+	}
+	return true
+}
+
+// defaultTelemetryWindow is how far back telemetry is returned when no from
+// is given
+const defaultTelemetryWindow = 24 * time.Hour
+
+// GetVehicleTelemetry returns telemetry history for a vehicle. The
+// resolution parameter selects raw records or 1m, 15m or 1h rollups; auto,
+// the default, picks one from the length of the range.
 func (h *Handler) GetVehicleTelemetry(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	idStr := chi.URLParam(r, "id")
@@ -209,23 +296,32 @@ func (h *Handler) GetVehicleTelemetry(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// Parse time range
-	from := r.URL.Query().Get("from")
-	to := r.URL.Query().Get("to")
+	fromTime, toTime, ok := h.timeRange(w, r, defaultTelemetryWindow)
+	if !ok {
+		return
+	}
+	if !fromTime.Before(toTime) {
+		h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", "from must be before to")
+		return
+	}
 	
-	var fromTime, toTime time.Time
-	if from != "" {
-		fromTime, _ = time.Parse(time.RFC3339, from)
-	} else {
-		fromTime = time.Now().Add(-24 * time.Hour)
+	resolution := domain.Resolution(r.URL.Query().Get("resolution"))
+	switch resolution {
+	case "", "auto":
+		resolution = domain.ResolutionFor(toTime.Sub(fromTime))
+	case domain.ResolutionRaw, domain.Resolution1m, domain.Resolution15m, domain.Resolution1h:
+	default:
+		h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", "resolution must be auto, raw, 1m, 15m or 1h")
+		return
 	}
-	if to != "" {
-		toTime, _ = time.Parse(time.RFC3339, to)
+	w.Header().Set("X-Telemetry-Resolution", string(resolution))
+	
+	var telemetry interface{}
+	if resolution == domain.ResolutionRaw {
+		telemetry, err = h.telemetryService.GetByVehicle(ctx, id, fromTime, toTime)
 	} else {
-		toTime = time.Now()
+		telemetry, err = h.telemetryService.GetRollups(ctx, id, resolution, fromTime, toTime)
 	}
-	
-	telemetry, err := h.telemetryService.GetByVehicle(ctx, id, fromTime, toTime)
 	if err != nil {
 		h.logger.Error().Err(err).Str("vehicleId", idStr).Msg("Failed to fetch telemetry")
 		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch telemetry")
@@ -287,10 +383,15 @@ func (h *Handler) GetAlert(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	alert, err := h.alertService.GetByID(ctx, id)
-	if err != nil {
+	if errors.Is(err, domain.ErrNotFound) {
 		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Alert not found")
 		return
 	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("alertId", idStr).Msg("Failed to fetch alert")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch alert")
+		return
+	}
 	
 	h.respondJSON(w, http.StatusOK, alert)
 }
@@ -306,10 +407,11 @@ func (h *Handler) AcknowledgeAlert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// TODO: Get user ID from auth context
-	userID := uuid.New()
-	
-	alert, err := h.alertService.Acknowledge(ctx, id, userID)
+	alert, err := h.alertService.Acknowledge(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Alert not found")
+		return
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Str("alertId", idStr).Msg("Failed to acknowledge alert")
 		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to acknowledge alert")
@@ -330,10 +432,11 @@ func (h *Handler) ResolveAlert(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	
-	// TODO: Get user ID from auth context
-	userID := uuid.New()
-	
-	alert, err := h.alertService.Resolve(ctx, id, userID)
+	alert, err := h.alertService.Resolve(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Alert not found")
+		return
+	}
 	if err != nil {
 		h.logger.Error().Err(err).Str("alertId", idStr).Msg("Failed to resolve alert")
 		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to resolve alert")
@@ -359,57 +462,24 @@ func (h *Handler) GetFleetStats(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusOK, stats)
 }
 
-// GetConsumptionAnalytics returns fuel/energy consumption data
-func (h *Handler) GetConsumptionAnalytics(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	
-	period := r.URL.Query().Get("period")
-	if period == "" {
-		period = "7d"
-	}
-	
-	data, err := h.analyticsService.GetConsumption(ctx, period)
-	if err != nil {
-		h.logger.Error().Err(err).Msg("Failed to fetch consumption analytics")
-		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch consumption data")
-		return
-	}
-	
-	h.respondJSON(w, http.StatusOK, data)
-}
-
-// GetDistanceAnalytics returns distance traveled data
-func (h *Handler) GetDistanceAnalytics(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	
-	period := r.URL.Query().Get("period")
-	if period == "" {
-		period = "7d"
-	}
-	
-	data, err := h.analyticsService.GetDistance(ctx, period)
-	if err != nil {
-		h.logger.Error().Err(err).Msg("Failed to fetch distance analytics")
-		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch distance data")
-		return
-	}
-	
-	h.respondJSON(w, http.StatusOK, data)
-}
-
 // ========== Telemetry Handlers ==========
 
-// IngestTelemetry receives telemetry data from vehicles/simulators
+// IngestTelemetry receives telemetry data from vehicles/simulators as JSON,
+// Protobuf or CBOR, picked by Content-Type
 func (h *Handler) IngestTelemetry(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	
-	var telemetry domain.Telemetry
-	if err := json.NewDecoder(r.Body).Decode(&telemetry); err != nil {
-		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid telemetry data")
+	body := http.MaxBytesReader(w, r.Body, maxRecordBodySize)
+	telemetry, err := codec.DecodeTelemetry(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		h.respondDecodeError(w, err)
 		return
 	}
 	
-	if err := h.telemetryService.Ingest(ctx, &telemetry); err != nil {
+	if err := h.telemetryService.Ingest(ctx, telemetry); err != nil {
+		if h.respondIngestError(w, err) {
+			return
+		}
 		h.logger.Error().Err(err).Msg("Failed to ingest telemetry")
 		h.respondError(w, http.StatusInternalServerError, "INGEST_ERROR", "Failed to process telemetry")
 		return
@@ -418,13 +488,34 @@ func (h *Handler) IngestTelemetry(w http.ResponseWriter, r *http.Request) {
 	h.respondJSON(w, http.StatusAccepted, map[string]string{"status": "accepted"})
 }
 
-// BatchIngestTelemetry receives multiple telemetry records
+// BatchIngestTelemetry receives multiple telemetry records, optionally
+// gzip-compressed. Newline-delimited JSON is streamed and may be partially
+// accepted; other formats are stored all or nothing, and their bodies are
+// limited to maxBatchBodySize both compressed and decompressed.
 func (h *Handler) BatchIngestTelemetry(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	
-	var telemetryBatch []domain.Telemetry
-	if err := json.NewDecoder(r.Body).Decode(&telemetryBatch); err != nil {
-		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid telemetry data")
+	// Streamed bodies are read a line at a time, so only their lines are limited
+	ndjson := codec.IsNDJSON(r.Header.Get("Content-Type"))
+	limit := int64(maxBatchBodySize)
+	if ndjson {
+		limit = 0
+	}
+	
+	body, ok := h.requestBody(w, r, limit)
+	if !ok {
+		return
+	}
+	defer body.Close()
+	
+	if ndjson {
+		h.streamTelemetry(w, r, body)
+		return
+	}
+	
+	telemetryBatch, err := codec.DecodeBatch(r.Header.Get("Content-Type"), body)
+	if err != nil {
+		h.respondDecodeError(w, err)
 		return
 	}
 	
@@ -434,6 +525,9 @@ func (h *Handler) BatchIngestTelemetry(w http.ResponseWriter, r *http.Request) {
 	}
 	
 	if err := h.telemetryService.BatchIngest(ctx, telemetryBatch); err != nil {
+		if h.respondIngestError(w, err) {
+			return
+		}
 		h.logger.Error().Err(err).Int("count", len(telemetryBatch)).Msg("Failed to batch ingest telemetry")
 		h.respondError(w, http.StatusInternalServerError, "INGEST_ERROR", "Failed to process telemetry batch")
 		return
@@ -444,3 +538,51 @@ func (h *Handler) BatchIngestTelemetry(w http.ResponseWriter, r *http.Request) {
 		"received": len(telemetryBatch),
 	})
 }
+
+// respondDecodeError writes the client error for a telemetry body that
+// could not be decoded
+func (h *Handler) respondDecodeError(w http.ResponseWriter, err error) {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		h.respondError(w, http.StatusBadRequest, "INVALID_TELEMETRY", err.Error())
+		return
+	}
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		h.respondError(w, http.StatusRequestEntityTooLarge, "BODY_TOO_LARGE",
+			fmt.Sprintf("Body must not exceed %d bytes", tooLarge.Limit))
+		return
+	}
+	h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid telemetry data")
+}
+
+// respondIngestError writes a client error for rejected telemetry, listing
+// the rejected records in its details, and reports whether it handled err
+func (h *Handler) respondIngestError(w http.ResponseWriter, err error) bool {
+	status, code, message, ok := ingestRejection(err)
+	if !ok {
+		return false
+	}
+	records := rejectedRecords(err)
+	if len(records) > 1 {
+		message = fmt.Sprintf("%d records were rejected", len(records))
+	}
+	h.respondErrorDetails(w, status, code, message, records)
+	return true
+}
+
+// ingestRejection returns the status, error code and message of telemetry
+// the pipeline rejected, and false for failures that are not the record's
+// fault
+func ingestRejection(err error) (int, string, string, bool) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return http.StatusBadRequest, "INVALID_TELEMETRY", err.Error(), true
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, "NOT_FOUND", "Vehicle not found", true
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, "FORBIDDEN", err.Error(), true
+	}
+	return 0, "", "", false
+}
@@ -0,0 +1,315 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// ========== Maintenance Handlers ==========
+
+// ListMaintenance returns a vehicle's maintenance records, latest first
+func (h *Handler) ListMaintenance(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := h.maintenanceVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	records, err := h.maintenanceService.GetByVehicle(r.Context(), vehicleID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to fetch maintenance records")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch maintenance records")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, records)
+}
+
+// GetMaintenance returns a single maintenance record
+func (h *Handler) GetMaintenance(w http.ResponseWriter, r *http.Request) {
+	vehicleID, recordID, ok := h.maintenanceIDs(w, r, "recordId", "maintenance record")
+	if !ok {
+		return
+	}
+
+	record, err := h.maintenanceService.GetByID(r.Context(), vehicleID, recordID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Maintenance record not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("recordId", recordID.String()).Msg("Failed to fetch maintenance record")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch maintenance record")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, record)
+}
+
+// CreateMaintenance records a service for a vehicle. A record created in
+// progress puts the vehicle in maintenance.
+func (h *Handler) CreateMaintenance(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := h.maintenanceVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	var record domain.MaintenanceRecord
+	if err := json.NewDecoder(r.Body).Decode(&record); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	record.VehicleID = vehicleID
+	created, err := h.maintenanceService.Create(r.Context(), &record)
+	if h.respondMaintenanceError(w, err, "Vehicle not found") {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to create maintenance record")
+		h.respondError(w, http.StatusInternalServerError, "CREATE_ERROR", "Failed to create maintenance record")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateMaintenance updates a maintenance record; omitted fields keep their
+// current values. Moving the record to in_progress puts the vehicle in
+// maintenance, and completing or cancelling it releases the vehicle.
+func (h *Handler) UpdateMaintenance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vehicleID, recordID, ok := h.maintenanceIDs(w, r, "recordId", "maintenance record")
+	if !ok {
+		return
+	}
+
+	record, err := h.maintenanceService.GetByID(ctx, vehicleID, recordID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Maintenance record not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("recordId", recordID.String()).Msg("Failed to fetch maintenance record")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch maintenance record")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(record); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	record.ID, record.VehicleID = recordID, vehicleID
+	updated, err := h.maintenanceService.Update(ctx, record)
+	if h.respondMaintenanceError(w, err, "Maintenance record not found") {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("recordId", recordID.String()).Msg("Failed to update maintenance record")
+		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to update maintenance record")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteMaintenance removes a maintenance record
+func (h *Handler) DeleteMaintenance(w http.ResponseWriter, r *http.Request) {
+	vehicleID, recordID, ok := h.maintenanceIDs(w, r, "recordId", "maintenance record")
+	if !ok {
+		return
+	}
+
+	err := h.maintenanceService.Delete(r.Context(), vehicleID, recordID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Maintenance record not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("recordId", recordID.String()).Msg("Failed to delete maintenance record")
+		h.respondError(w, http.StatusInternalServerError, "DELETE_ERROR", "Failed to delete maintenance record")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListMaintenancePlans returns a vehicle's maintenance plans
+func (h *Handler) ListMaintenancePlans(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := h.maintenanceVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	plans, err := h.maintenanceService.GetPlans(r.Context(), vehicleID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to fetch maintenance plans")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch maintenance plans")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, plans)
+}
+
+// GetMaintenancePlan returns a single maintenance plan
+func (h *Handler) GetMaintenancePlan(w http.ResponseWriter, r *http.Request) {
+	vehicleID, planID, ok := h.maintenanceIDs(w, r, "planId", "maintenance plan")
+	if !ok {
+		return
+	}
+
+	plan, err := h.maintenanceService.GetPlan(r.Context(), vehicleID, planID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Maintenance plan not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("planId", planID.String()).Msg("Failed to fetch maintenance plan")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch maintenance plan")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, plan)
+}
+
+// CreateMaintenancePlan creates a recurring plan for a vehicle; plans are
+// active unless the body says otherwise
+func (h *Handler) CreateMaintenancePlan(w http.ResponseWriter, r *http.Request) {
+	vehicleID, ok := h.maintenanceVehicleID(w, r)
+	if !ok {
+		return
+	}
+
+	plan := domain.MaintenancePlan{IsActive: true}
+	if err := json.NewDecoder(r.Body).Decode(&plan); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	plan.VehicleID = vehicleID
+	created, err := h.maintenanceService.CreatePlan(r.Context(), &plan)
+	if h.respondMaintenanceError(w, err, "Vehicle not found") {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to create maintenance plan")
+		h.respondError(w, http.StatusInternalServerError, "CREATE_ERROR", "Failed to create maintenance plan")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateMaintenancePlan updates a maintenance plan; omitted fields keep
+// their current values
+func (h *Handler) UpdateMaintenancePlan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vehicleID, planID, ok := h.maintenanceIDs(w, r, "planId", "maintenance plan")
+	if !ok {
+		return
+	}
+
+	plan, err := h.maintenanceService.GetPlan(ctx, vehicleID, planID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Maintenance plan not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("planId", planID.String()).Msg("Failed to fetch maintenance plan")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch maintenance plan")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(plan); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	plan.ID, plan.VehicleID = planID, vehicleID
+	updated, err := h.maintenanceService.UpdatePlan(ctx, plan)
+	if h.respondMaintenanceError(w, err, "Maintenance plan not found") {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("planId", planID.String()).Msg("Failed to update maintenance plan")
+		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to update maintenance plan")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, updated)
+}
+
+// DeleteMaintenancePlan removes a maintenance plan
+func (h *Handler) DeleteMaintenancePlan(w http.ResponseWriter, r *http.Request) {
+	vehicleID, planID, ok := h.maintenanceIDs(w, r, "planId", "maintenance plan")
+	if !ok {
+		return
+	}
+
+	err := h.maintenanceService.DeletePlan(r.Context(), vehicleID, planID)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Maintenance plan not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("planId", planID.String()).Msg("Failed to delete maintenance plan")
+		h.respondError(w, http.StatusInternalServerError, "DELETE_ERROR", "Failed to delete maintenance plan")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) maintenanceVehicleID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid vehicle ID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// maintenanceIDs parses the vehicle ID and the ID of the record or plan in
+// the URL parameter param
+func (h *Handler) maintenanceIDs(w http.ResponseWriter, r *http.Request, param, name string) (uuid.UUID, uuid.UUID, bool) {
+	vehicleID, ok := h.maintenanceVehicleID(w, r)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+	id, err := uuid.Parse(chi.URLParam(r, param))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid "+name+" ID format")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return vehicleID, id, true
+}
+
+// respondMaintenanceError reports validation errors, missing entities and
+// disallowed status changes
+func (h *Handler) respondMaintenanceError(w http.ResponseWriter, err error, notFound string) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.respondError(w, http.StatusBadRequest, "INVALID_MAINTENANCE", err.Error())
+	case errors.Is(err, domain.ErrNotFound):
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", notFound)
+	case errors.Is(err, domain.ErrConflict):
+		h.respondError(w, http.StatusConflict, "INVALID_TRANSITION", err.Error())
+	default:
+		return false
+	}
+	return true
+}
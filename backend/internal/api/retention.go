@@ -0,0 +1,16 @@
+package api
+
+import "net/http"
+
+// GetRetention returns the telemetry retention policy and the telemetry
+// stored per vehicle
+func (h *Handler) GetRetention(w http.ResponseWriter, r *http.Request) {
+	report, err := h.retentionService.GetReport(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to fetch telemetry storage")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch telemetry storage")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
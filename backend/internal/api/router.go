@@ -8,18 +8,23 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/auth"
 	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/metrics"
 	"github.com/sid-romero/fleetpulse/internal/websocket"
 )
 
-// Router creates and configures the HTTP router
+// Router creates and configures the HTTP router. A nil verifier disables
+// authentication.
 func NewRouter(
 	cfg *config.Config,
 	handler *Handler,
 	wsHub *websocket.Hub,
+	verifier *auth.Verifier,
 	logger zerolog.Logger,
 ) http.Handler {
 	r := chi.NewRouter()
+	guard := &authGuard{handler: handler, verifier: verifier}
 
 	// ========== Middleware Stack ==========
 	
@@ -54,14 +59,15 @@ func NewRouter(
 	// Panic recovery
 	r.Use(middleware.Recoverer)
 	
-	// Request timeout
-	r.Use(middleware.Timeout(30 * time.Second))
+	// Request timeout (streamed telemetry batches have their own)
+	r.Use(requestTimeout(30*time.Second, cfg.Server.StreamTimeout, cfg.Server.WriteTimeout))
 	
 	// CORS
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   cfg.CORS.AllowedOrigins,
 		AllowedMethods:   cfg.CORS.AllowedMethods,
 		AllowedHeaders:   cfg.CORS.AllowedHeaders,
+		ExposedHeaders:   cfg.CORS.ExposedHeaders,
 		AllowCredentials: cfg.CORS.AllowCredentials,
 		MaxAge:           cfg.CORS.MaxAge,
 	}))
@@ -76,52 +82,162 @@ func NewRouter(
 	r.Get("/healthz", handler.HealthCheck) // k8s style
 	r.Get("/readyz", handler.HealthCheck)  // k8s readiness
 	
-	// WebSocket endpoint
-	r.Get("/ws", wsHub.HandleWebSocket)
-	r.Get("/ws/telemetry", wsHub.HandleWebSocket)
+	// Process metrics for scrapers
+	r.Handle("/metrics", metrics.Handler())
+	
+	// Route permissions (see auth.Allowed for what each role is granted)
+	can := guard.require
+	read := can(auth.PermissionFleetRead)
+	manageVehicles := can(auth.PermissionVehiclesManage)
+	respondToAlerts := can(auth.PermissionAlertsRespond)
+	manageGeofences := can(auth.PermissionGeofencesManage)
+	manageUsers := can(auth.PermissionUsersManage)
+	manageDevices := can(auth.PermissionDevicesManage)
+	manageDrivers := can(auth.PermissionDriversManage)
+	administer := can(auth.PermissionSystemAdmin)
+	
+	// WebSocket endpoint (token in the Authorization header or ?access_token=)
+	r.With(guard.authenticate(true), read).Get("/ws", wsHub.HandleWebSocket)
+	r.With(guard.authenticate(true), read).Get("/ws/telemetry", wsHub.HandleWebSocket)
 	
 	// API v1
 	r.Route("/api/v1", func(r chi.Router) {
-		// Vehicles
-		r.Route("/vehicles", func(r chi.Router) {
-			r.Get("/", handler.ListVehicles)
-			r.Post("/", handler.CreateVehicle)
-			
-			r.Route("/{id}", func(r chi.Router) {
-				r.Get("/", handler.GetVehicle)
-				r.Put("/", handler.UpdateVehicle)
-				r.Patch("/", handler.UpdateVehicle)
-				r.Get("/telemetry", handler.GetVehicleTelemetry)
-			})
+		// Telemetry ingestion (for simulator/IoT devices): a device key in
+		// X-Device-Key, or a bearer token with the telemetry:ingest permission
+		r.Route("/telemetry", func(r chi.Router) {
+			r.Use(guard.authenticateDevice)
+			r.Post("/", handler.IngestTelemetry)
+			r.Post("/batch", handler.BatchIngestTelemetry)
 		})
 		
-		// Alerts
-		r.Route("/alerts", func(r chi.Router) {
-			r.Get("/", handler.ListAlerts)
+		r.Group(func(r chi.Router) {
+			r.Use(guard.authenticate(false))
 			
-			r.Route("/{id}", func(r chi.Router) {
-				r.Get("/", handler.GetAlert)
-				r.Post("/acknowledge", handler.AcknowledgeAlert)
-				r.Post("/resolve", handler.ResolveAlert)
-				r.Patch("/", func(w http.ResponseWriter, r *http.Request) {
-					// Handle PATCH for acknowledge/resolve via body
-					// This allows: PATCH /alerts/:id { "status": "acknowledged" }
-					handler.AcknowledgeAlert(w, r)
+			// Users
+			r.Route("/users", func(r chi.Router) {
+				r.Get("/me", handler.GetCurrentUser)
+				
+				r.Group(func(r chi.Router) {
+					r.Use(manageUsers)
+					r.Get("/", handler.ListUsers)
+					r.Post("/", handler.CreateUser)
+					r.Get("/{id}", handler.GetUser)
+					r.Put("/{id}", handler.UpdateUser)
+					r.Patch("/{id}", handler.UpdateUser)
 				})
 			})
-		})
-		
-		// Analytics
-		r.Route("/analytics", func(r chi.Router) {
-			r.Get("/stats", handler.GetFleetStats)
-			r.Get("/consumption", handler.GetConsumptionAnalytics)
-			r.Get("/distance", handler.GetDistanceAnalytics)
-		})
-		
-		// Telemetry ingestion (for simulator/IoT devices)
-		r.Route("/telemetry", func(r chi.Router) {
-			r.Post("/", handler.IngestTelemetry)
-			r.Post("/batch", handler.BatchIngestTelemetry)
+			
+			// Vehicles
+			r.Route("/vehicles", func(r chi.Router) {
+				r.With(read).Get("/", handler.ListVehicles)
+				r.With(manageVehicles).Post("/", handler.CreateVehicle)
+				
+				r.Route("/{id}", func(r chi.Router) {
+					r.With(read).Get("/", handler.GetVehicle)
+					r.With(manageVehicles).Put("/", handler.UpdateVehicle)
+					r.With(manageVehicles).Patch("/", handler.UpdateVehicle)
+					r.With(read).Get("/telemetry", handler.GetVehicleTelemetry)
+					r.With(read).Get("/trips", handler.ListVehicleTrips)
+					r.With(read).Get("/status-history", handler.GetVehicleStatusHistory)
+					
+					// Driver assignment
+					r.With(manageDrivers).Put("/driver", handler.AssignDriver)
+					r.With(manageDrivers).Delete("/driver", handler.UnassignDriver)
+					r.With(read).Get("/assignments", handler.ListVehicleAssignments)
+					
+					// Maintenance records and plans
+					r.Route("/maintenance", func(r chi.Router) {
+						r.With(read).Get("/", handler.ListMaintenance)
+						r.With(manageVehicles).Post("/", handler.CreateMaintenance)
+						
+						r.Route("/plans", func(r chi.Router) {
+							r.With(read).Get("/", handler.ListMaintenancePlans)
+							r.With(manageVehicles).Post("/", handler.CreateMaintenancePlan)
+							r.With(read).Get("/{planId}", handler.GetMaintenancePlan)
+							r.With(manageVehicles).Put("/{planId}", handler.UpdateMaintenancePlan)
+							r.With(manageVehicles).Patch("/{planId}", handler.UpdateMaintenancePlan)
+							r.With(manageVehicles).Delete("/{planId}", handler.DeleteMaintenancePlan)
+						})
+						
+						r.With(read).Get("/{recordId}", handler.GetMaintenance)
+						r.With(manageVehicles).Put("/{recordId}", handler.UpdateMaintenance)
+						r.With(manageVehicles).Patch("/{recordId}", handler.UpdateMaintenance)
+						r.With(manageVehicles).Delete("/{recordId}", handler.DeleteMaintenance)
+					})
+					
+					// Device keys
+					r.Route("/keys", func(r chi.Router) {
+						r.Use(manageDevices)
+						r.Get("/", handler.ListDeviceKeys)
+						r.Post("/", handler.IssueDeviceKey)
+						r.Post("/{keyId}/rotate", handler.RotateDeviceKey)
+						r.Delete("/{keyId}", handler.RevokeDeviceKey)
+					})
+				})
+			})
+			
+			// Drivers
+			r.Route("/drivers", func(r chi.Router) {
+				r.With(read).Get("/", handler.ListDrivers)
+				r.With(manageDrivers).Post("/", handler.CreateDriver)
+				
+				r.Route("/{id}", func(r chi.Router) {
+					r.With(read).Get("/", handler.GetDriver)
+					r.With(manageDrivers).Put("/", handler.UpdateDriver)
+					r.With(manageDrivers).Patch("/", handler.UpdateDriver)
+					r.With(manageDrivers).Delete("/", handler.DeleteDriver)
+					r.With(read).Get("/assignments", handler.ListDriverAssignments)
+					r.With(read).Get("/score", handler.GetDriverScore)
+					r.With(read).Get("/events", handler.ListDrivingEvents)
+				})
+			})
+			
+			// Trips
+			r.With(read).Get("/trips/{tripId}", handler.GetTrip)
+			
+			// Alerts
+			r.Route("/alerts", func(r chi.Router) {
+				r.With(read).Get("/", handler.ListAlerts)
+				
+				r.Route("/{id}", func(r chi.Router) {
+					r.With(read).Get("/", handler.GetAlert)
+					r.With(respondToAlerts).Post("/acknowledge", handler.AcknowledgeAlert)
+					r.With(respondToAlerts).Post("/resolve", handler.ResolveAlert)
+					r.With(respondToAlerts).Patch("/", func(w http.ResponseWriter, r *http.Request) {
+						// Handle PATCH for acknowledge/resolve via body
+						// This allows: PATCH /alerts/:id { "status": "acknowledged" }
+						handler.AcknowledgeAlert(w, r)
+					})
+				})
+			})
+			
+			// Geofences
+			r.Route("/geofences", func(r chi.Router) {
+				r.With(read).Get("/", handler.ListGeofences)
+				r.With(manageGeofences).Post("/", handler.CreateGeofence)
+				
+				r.Route("/{id}", func(r chi.Router) {
+					r.With(read).Get("/", handler.GetGeofence)
+					r.With(manageGeofences).Put("/", handler.UpdateGeofence)
+					r.With(manageGeofences).Patch("/", handler.UpdateGeofence)
+					r.With(manageGeofences).Delete("/", handler.DeleteGeofence)
+				})
+			})
+			
+			// Analytics
+			r.Route("/analytics", func(r chi.Router) {
+				r.Use(read)
+				r.Get("/stats", handler.GetFleetStats)
+				r.Get("/consumption", handler.GetConsumptionAnalytics)
+				r.Get("/distance", handler.GetDistanceAnalytics)
+				r.Get("/utilization", handler.GetUtilization)
+			})
+			
+			// Administration
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(administer)
+				r.Get("/retention", handler.GetRetention)
+			})
 		})
 	})
 	
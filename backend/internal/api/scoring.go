@@ -0,0 +1,64 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// ========== Driver Scoring Handlers ==========
+
+// GetDriverScore returns a driver's safety score over the from/to window,
+// by default the scoring window, with a breakdown per event type
+func (h *Handler) GetDriverScore(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.driverID(w, r)
+	if !ok {
+		return
+	}
+
+	from, to, ok := h.timeRange(w, r, h.scoringService.Window())
+	if !ok {
+		return
+	}
+
+	score, err := h.scoringService.GetScore(r.Context(), id, from, to)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Driver not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("driverId", id.String()).Msg("Failed to score driver")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to score driver")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, score)
+}
+
+// ListDrivingEvents returns a driver's risky driving events in the from/to
+// window, latest first
+func (h *Handler) ListDrivingEvents(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.driverID(w, r)
+	if !ok {
+		return
+	}
+
+	from, to, ok := h.timeRange(w, r, h.scoringService.Window())
+	if !ok {
+		return
+	}
+
+	events, err := h.scoringService.GetEvents(r.Context(), id, from, to)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Driver not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("driverId", id.String()).Msg("Failed to fetch driving events")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch driving events")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, events)
+}
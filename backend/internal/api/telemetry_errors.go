@@ -0,0 +1,54 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// RejectedRecord is the error detail of a telemetry record that was not
+// stored
+type RejectedRecord struct {
+	Record  int                 `json:"record"` // index in the batch, 0 for a single record
+	Reason  domain.RejectReason `json:"reason"`
+	Field   string              `json:"field,omitempty"`
+	Message string              `json:"message"`
+}
+
+// rejectedRecords lists the records err rejected, which may be several
+// joined *domain.RecordError from a batch
+func rejectedRecords(err error) []RejectedRecord {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+
+	records := make([]RejectedRecord, 0, len(errs))
+	for _, err := range errs {
+		var record RejectedRecord
+		var recordErr *domain.RecordError
+		if errors.As(err, &recordErr) {
+			record.Record, err = recordErr.Index, recordErr.Err
+		}
+		reason, ok := domain.RejectReasonOf(err)
+		if !ok {
+			continue
+		}
+		record.Reason = reason
+		record.Field, record.Message = describeRejection(err)
+		records = append(records, record)
+	}
+	return records
+}
+
+// describeRejection returns the field and message of a rejected record
+func describeRejection(err error) (string, string) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return validationErr.Field, validationErr.Message
+	case errors.Is(err, domain.ErrNotFound):
+		return "vehicleId", "Vehicle not found"
+	}
+	return "", err.Error()
+}
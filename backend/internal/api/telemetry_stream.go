@@ -0,0 +1,207 @@
+package api
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/sid-romero/fleetpulse/internal/codec"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/metrics"
+)
+
+// maxLineErrors bounds the line errors listed for a streamed batch
+const maxLineErrors = 100
+
+// Telemetry bodies decoded in full are limited so that a large or highly
+// compressed body can't exhaust memory
+const (
+	maxRecordBodySize = 64 << 10
+	maxBatchBodySize  = 8 << 20
+)
+
+// telemetryStreamPath is the route streamed telemetry batches are sent to
+const telemetryStreamPath = "/api/v1/telemetry/batch"
+
+// StreamResult reports how a streamed batch was ingested. Lines are
+// processed in order, so a client whose upload was cut short can resend
+// from the line after Lines.
+type StreamResult struct {
+	Status   string      `json:"status"` // accepted, or partial when any line was rejected
+	Lines    int         `json:"lines"`
+	Accepted int         `json:"accepted"`
+	Rejected int         `json:"rejected"`
+	Errors   []LineError `json:"errors"` // the first maxLineErrors rejections
+}
+
+// LineError explains why a line of a streamed batch was not stored. A body
+// cut short has no reason.
+type LineError struct {
+	Line    int                 `json:"line"`
+	Code    string              `json:"code"`
+	Reason  domain.RejectReason `json:"reason,omitempty"`
+	Field   string              `json:"field,omitempty"`
+	Message string              `json:"message"`
+}
+
+func (res *StreamResult) reject(e LineError) {
+	res.Status = "partial"
+	res.Rejected++
+	if len(res.Errors) < maxLineErrors {
+		res.Errors = append(res.Errors, e)
+	}
+}
+
+// isTelemetryStream reports whether r sends a streamed telemetry batch
+func isTelemetryStream(r *http.Request) bool {
+	return r.Method == http.MethodPost && r.URL.Path == telemetryStreamPath &&
+		codec.IsNDJSON(r.Header.Get("Content-Type"))
+}
+
+// requestTimeout cancels each request after timeout. Streamed telemetry
+// batches may take far longer to upload, so they are instead given until
+// streamTimeout to send their body, replacing the server's read timeout,
+// and writeTimeout more to store what was read and respond. Their context
+// outlives the connection's, which is cancelled when the upload is cut
+// short, so the lines read before that are still stored.
+func requestTimeout(timeout, streamTimeout, writeTimeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		timed := middleware.Timeout(timeout)(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !isTelemetryStream(r) {
+				timed.ServeHTTP(w, r)
+				return
+			}
+
+			deadline := time.Now().Add(streamTimeout)
+			rc := http.NewResponseController(w)
+			// A connection that can't set deadlines keeps the server's
+			if err := rc.SetReadDeadline(deadline); err == nil {
+				_ = rc.SetWriteDeadline(deadline.Add(writeTimeout))
+			}
+
+			ctx, cancel := context.WithDeadline(context.WithoutCancel(r.Context()), deadline.Add(writeTimeout))
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// requestBody returns the request body, decompressing it when it is
+// gzip-encoded. A positive limit applies to the body both as sent and
+// decompressed; reading past it fails with an *http.MaxBytesError. It
+// writes the error response and returns false when the body can't be read.
+func (h *Handler) requestBody(w http.ResponseWriter, r *http.Request, limit int64) (io.ReadCloser, bool) {
+	body := r.Body
+	if limit > 0 {
+		body = http.MaxBytesReader(w, body, limit)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(r.Header.Get("Content-Encoding"))) {
+	case "", "identity":
+		return body, true
+	case "gzip", "x-gzip":
+		decompressed, err := gzip.NewReader(body)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid gzip body")
+			return nil, false
+		}
+		if limit > 0 {
+			return http.MaxBytesReader(w, decompressed, limit), true
+		}
+		return decompressed, true
+	}
+	h.respondError(w, http.StatusUnsupportedMediaType, "UNSUPPORTED_ENCODING", "Content-Encoding must be gzip or identity")
+	return nil, false
+}
+
+// streamTelemetry ingests newline-delimited JSON record by record. Rejected
+// lines are reported and skipped while the rest are stored. A body that
+// can't be read past some line is reported as an error on that line, and
+// the lines before it are still stored. So is an upload that runs past its
+// deadline, which requestTimeout sets.
+func (h *Handler) streamTelemetry(w http.ResponseWriter, r *http.Request, body io.Reader) {
+	ctx := r.Context()
+	reader := codec.NewLineReader(body)
+	stream := h.telemetryService.NewStream()
+	result := StreamResult{Status: "accepted", Errors: []LineError{}}
+
+	// first line of the records queued for storage
+	queuedFrom := 0
+	flush := func() bool {
+		if err := stream.Flush(ctx); err != nil {
+			h.logger.Error().Err(err).Int("line", queuedFrom).Msg("Failed to store streamed telemetry")
+			h.respondError(w, http.StatusInternalServerError, "INGEST_ERROR",
+				fmt.Sprintf("Failed to store telemetry; resend from line %d", queuedFrom))
+			return false
+		}
+		queuedFrom = 0
+		return true
+	}
+
+	for {
+		line, telemetry, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if errors.Is(err, codec.ErrMalformedLine) {
+			metrics.TelemetryRejected.Add(string(domain.RejectInvalid), 1)
+			result.reject(LineError{Line: line, Code: "INVALID_BODY", Reason: domain.RejectInvalid, Message: err.Error()})
+			continue
+		}
+		if errors.Is(err, os.ErrDeadlineExceeded) {
+			result.reject(LineError{Line: line, Code: "UPLOAD_TIMEOUT", Message: "Upload took too long; resend from this line"})
+			break
+		}
+		if err != nil {
+			result.reject(LineError{Line: line, Code: "INVALID_BODY", Message: err.Error()})
+			break
+		}
+
+		err = stream.Add(ctx, telemetry)
+		if err != nil {
+			_, code, _, ok := ingestRejection(err)
+			if !ok {
+				h.logger.Error().Err(err).Int("line", line).Msg("Failed to check streamed telemetry")
+				h.respondError(w, http.StatusInternalServerError, "INGEST_ERROR",
+					fmt.Sprintf("Failed to process telemetry; resend from line %d", firstQueued(queuedFrom, line)))
+				return
+			}
+			reason, _ := domain.RejectReasonOf(err)
+			field, message := describeRejection(err)
+			result.reject(LineError{Line: line, Code: code, Reason: reason, Field: field, Message: message})
+			continue
+		}
+
+		if queuedFrom == 0 {
+			queuedFrom = line
+		}
+		if stream.Full() && !flush() {
+			return
+		}
+	}
+	if !flush() {
+		return
+	}
+
+	result.Lines = reader.Lines()
+	result.Accepted = stream.Accepted()
+	h.respondJSON(w, http.StatusAccepted, result)
+}
+
+// firstQueued returns the line to resend from when line fails while
+// records from queuedFrom are queued
+func firstQueued(queuedFrom, line int) int {
+	if queuedFrom > 0 {
+		return queuedFrom
+	}
+	return line
+}
@@ -0,0 +1,94 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// defaultTripWindow is how far back trips are listed when no from is given
+const defaultTripWindow = 7 * 24 * time.Hour
+
+// ========== Trip Handlers ==========
+
+// ListVehicleTrips returns the trips a vehicle started in the from/to window,
+// newest first; the trip in progress, if any, comes first
+func (h *Handler) ListVehicleTrips(w http.ResponseWriter, r *http.Request) {
+	vehicleID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid vehicle ID format")
+		return
+	}
+
+	from, to, ok := h.timeRange(w, r, defaultTripWindow)
+	if !ok {
+		return
+	}
+
+	trips, err := h.tripService.GetByVehicle(r.Context(), vehicleID, from, to)
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to fetch trips")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch trips")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, trips)
+}
+
+// GetTrip returns a single trip with its full path
+func (h *Handler) GetTrip(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "tripId"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid trip ID format")
+		return
+	}
+
+	trip, err := h.tripService.GetByID(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Trip not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("tripId", id.String()).Msg("Failed to fetch trip")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch trip")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, trip)
+}
+
+// timeRange parses the RFC 3339 from and to query parameters. to defaults to
+// now and from to window before to.
+func (h *Handler) timeRange(w http.ResponseWriter, r *http.Request, window time.Duration) (time.Time, time.Time, bool) {
+	query := r.URL.Query()
+
+	to := time.Now()
+	if value := query.Get("to"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", "to must be an RFC 3339 timestamp")
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	from := to.Add(-window)
+	if value := query.Get("from"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", "from must be an RFC 3339 timestamp")
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		h.respondError(w, http.StatusBadRequest, "INVALID_QUERY", "from must be before to")
+		return time.Time{}, time.Time{}, false
+	}
+	return from, to, true
+}
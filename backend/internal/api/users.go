@@ -0,0 +1,142 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/auth"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// ========== User Handlers ==========
+
+// GetCurrentUser returns the authenticated user
+func (h *Handler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
+	user, ok := auth.UserFromContext(r.Context())
+	if !ok {
+		h.respondUnauthorized(w, "Not authenticated")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, user)
+}
+
+// ListUsers returns all users
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	users, err := h.userService.GetAll(r.Context())
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to fetch users")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch users")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, users)
+}
+
+// GetUser returns a single user
+func (h *Handler) GetUser(w http.ResponseWriter, r *http.Request) {
+	id, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetByID(r.Context(), id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "User not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("userId", id.String()).Msg("Failed to fetch user")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch user")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, user)
+}
+
+// CreateUser creates a new user; users are active unless the body says
+// otherwise
+func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	user := domain.User{IsActive: true}
+	if err := json.NewDecoder(r.Body).Decode(&user); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	created, err := h.userService.Create(r.Context(), &user)
+	if h.respondUserError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to create user")
+		h.respondError(w, http.StatusInternalServerError, "CREATE_ERROR", "Failed to create user")
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, created)
+}
+
+// UpdateUser updates an existing user; omitted fields keep their current
+// values
+func (h *Handler) UpdateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id, ok := h.userID(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := h.userService.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "User not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("userId", id.String()).Msg("Failed to fetch user")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch user")
+		return
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(user); err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_BODY", "Invalid request body")
+		return
+	}
+
+	user.ID = id
+	updated, err := h.userService.Update(ctx, user)
+	if h.respondUserError(w, err) {
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("userId", id.String()).Msg("Failed to update user")
+		h.respondError(w, http.StatusInternalServerError, "UPDATE_ERROR", "Failed to update user")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, updated)
+}
+
+func (h *Handler) userID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid user ID format")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// respondUserError reports validation failures and duplicate emails
+func (h *Handler) respondUserError(w http.ResponseWriter, err error) bool {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		h.respondError(w, http.StatusBadRequest, "INVALID_USER", err.Error())
+	case errors.Is(err, domain.ErrConflict):
+		h.respondError(w, http.StatusConflict, "EMAIL_TAKEN", "A user with this email already exists")
+	default:
+		return false
+	}
+	return true
+}
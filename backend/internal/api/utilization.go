@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// defaultStatusHistoryWindow is how far back a status timeline goes when no
+// from is given
+const defaultStatusHistoryWindow = 7 * 24 * time.Hour
+
+// GetVehicleStatusHistory returns the periods a vehicle spent in each status
+// between from and to, oldest first
+func (h *Handler) GetVehicleStatusHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		h.respondError(w, http.StatusBadRequest, "INVALID_ID", "Invalid vehicle ID format")
+		return
+	}
+
+	from, to, ok := h.timeRange(w, r, defaultStatusHistoryWindow)
+	if !ok {
+		return
+	}
+
+	periods, err := h.utilizationService.GetTimeline(r.Context(), id, from, to)
+	if errors.Is(err, domain.ErrNotFound) {
+		h.respondError(w, http.StatusNotFound, "NOT_FOUND", "Vehicle not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error().Err(err).Str("vehicleId", id.String()).Msg("Failed to fetch status history")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch status history")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, periods)
+}
+
+// GetUtilization returns the share of time each vehicle spent in each status
+// over from/to or period
+func (h *Handler) GetUtilization(w http.ResponseWriter, r *http.Request) {
+	from, to, ok := h.analyticsRange(w, r)
+	if !ok {
+		return
+	}
+
+	report, err := h.utilizationService.GetReport(r.Context(), from, to)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to fetch utilization")
+		h.respondError(w, http.StatusInternalServerError, "FETCH_ERROR", "Failed to fetch utilization")
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, report)
+}
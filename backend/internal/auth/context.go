@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+type contextKey struct{}
+
+// WithUser returns a copy of ctx carrying the authenticated user
+func WithUser(ctx context.Context, user *domain.User) context.Context {
+	return context.WithValue(ctx, contextKey{}, user)
+}
+
+// UserFromContext returns the authenticated user, if any
+func UserFromContext(ctx context.Context) (*domain.User, bool) {
+	user, ok := ctx.Value(contextKey{}).(*domain.User)
+	return user, ok && user != nil
+}
+
+// UserID returns the authenticated user's ID, or nil for unauthenticated
+// calls (authentication disabled, background jobs)
+func UserID(ctx context.Context) *uuid.UUID {
+	user, ok := UserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	id := user.ID
+	return &id
+}
@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// deviceKeyPrefix marks FleetPulse device keys, which helps secret scanners
+const deviceKeyPrefix = "fpk_"
+
+// GenerateDeviceKey returns a new random device key along with its display
+// prefix and the hash to store
+func GenerateDeviceKey() (key, prefix, hash string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+
+	key = deviceKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:len(deviceKeyPrefix)+8], HashDeviceKey(key), nil
+}
+
+// HashDeviceKey returns the hex SHA-256 under which a device key is stored
+func HashDeviceKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+type deviceContextKey struct{}
+
+// WithDevice returns a copy of ctx carrying the device key that
+// authenticated the request
+func WithDevice(ctx context.Context, key *domain.DeviceKey) context.Context {
+	return context.WithValue(ctx, deviceContextKey{}, key)
+}
+
+// DeviceFromContext returns the device key that authenticated the request, if any
+func DeviceFromContext(ctx context.Context) (*domain.DeviceKey, bool) {
+	key, ok := ctx.Value(deviceContextKey{}).(*domain.DeviceKey)
+	return key, ok && key != nil
+}
@@ -0,0 +1,55 @@
+package auth
+
+import "github.com/sid-romero/fleetpulse/internal/domain"
+
+// Permission is an action on a group of API resources
+type Permission string
+
+const (
+	PermissionFleetRead       Permission = "fleet:read"
+	PermissionVehiclesManage  Permission = "vehicles:manage"
+	PermissionAlertsRespond   Permission = "alerts:respond"
+	PermissionGeofencesManage Permission = "geofences:manage"
+	PermissionTelemetryIngest Permission = "telemetry:ingest"
+	PermissionUsersManage     Permission = "users:manage"
+	PermissionDevicesManage   Permission = "devices:manage"
+	PermissionDriversManage   Permission = "drivers:manage"
+	PermissionSystemAdmin     Permission = "system:admin"
+)
+
+// rolePermissions grants permissions to roles. Devices only report
+// telemetry; viewers only read.
+var rolePermissions = map[domain.UserRole][]Permission{
+	domain.UserRoleAdmin: {
+		PermissionFleetRead,
+		PermissionVehiclesManage,
+		PermissionAlertsRespond,
+		PermissionGeofencesManage,
+		PermissionUsersManage,
+		PermissionDevicesManage,
+		PermissionDriversManage,
+		PermissionSystemAdmin,
+	},
+	domain.UserRoleDispatcher: {
+		PermissionFleetRead,
+		PermissionAlertsRespond,
+		PermissionGeofencesManage,
+		PermissionDriversManage,
+	},
+	domain.UserRoleViewer: {
+		PermissionFleetRead,
+	},
+	domain.UserRoleDevice: {
+		PermissionTelemetryIngest,
+	},
+}
+
+// Allowed reports whether role grants permission
+func Allowed(role domain.UserRole, permission Permission) bool {
+	for _, p := range rolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
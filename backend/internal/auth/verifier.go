@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/config"
+)
+
+// ErrInvalidToken is returned for tokens that fail verification
+var ErrInvalidToken = errors.New("invalid token")
+
+// Verifier validates JWT bearer tokens. HS256 tokens are checked against a
+// shared secret and RS256 tokens against the keys of a JWKS file; the token
+// subject must be a user ID.
+type Verifier struct {
+	secret []byte
+	keys   map[string]*rsa.PublicKey // by kid
+	parser *jwt.Parser
+}
+
+// NewVerifier creates a Verifier from cfg, which must provide a secret, a
+// JWKS file or both
+func NewVerifier(cfg config.AuthConfig) (*Verifier, error) {
+	v := &Verifier{secret: []byte(cfg.JWTSecret)}
+
+	var methods []string
+	if len(v.secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if cfg.JWKSFile != "" {
+		keys, err := LoadJWKS(cfg.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = keys
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+	if len(methods) == 0 {
+		return nil, errors.New("no token keys configured: set AUTH_JWT_SECRET or AUTH_JWKS_FILE")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods(methods),
+		jwt.WithLeeway(cfg.Leeway),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	v.parser = jwt.NewParser(opts...)
+
+	return v, nil
+}
+
+// Verify checks token's signature and claims and returns the user ID in its
+// subject
+func (v *Verifier) Verify(token string) (uuid.UUID, error) {
+	var claims jwt.RegisteredClaims
+	if _, err := v.parser.ParseWithClaims(token, &claims, v.key); err != nil {
+		return uuid.Nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("%w: subject is not a user ID", ErrInvalidToken)
+	}
+	return userID, nil
+}
+
+// key selects the verification key for a token from its algorithm and kid
+func (v *Verifier) key(token *jwt.Token) (interface{}, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.secret, nil
+
+	case *jwt.SigningMethodRSA:
+		kid, _ := token.Header["kid"].(string)
+		if key, ok := v.keys[kid]; ok {
+			return key, nil
+		}
+		// A token without kid is accepted when the key set is unambiguous
+		if kid == "" && len(v.keys) == 1 {
+			for _, key := range v.keys {
+				return key, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+}
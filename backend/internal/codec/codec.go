@@ -0,0 +1,108 @@
+// Package codec decodes telemetry sent to the HTTP API as JSON, Protobuf or
+// CBOR. Protobuf bodies use the Telemetry and TelemetryBatch messages of
+// proto/fleetpulse/v1; CBOR bodies have the same shape as JSON ones. Bodies
+// of any other content type are taken as JSON, as they always were. Batches
+// may also be newline-delimited JSON, read a record at a time by a
+// LineReader.
+package codec
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	pb "github.com/sid-romero/fleetpulse/internal/pb/fleetpulse/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// Supported telemetry content types
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeProtobuf = "application/x-protobuf"
+	ContentTypeCBOR     = "application/cbor"
+)
+
+// cborDecoder accepts vehicle IDs as text as well as 16-byte strings, and
+// timestamps as RFC 3339 text or epoch numbers
+var cborDecoder = func() cbor.DecMode {
+	mode, err := cbor.DecOptions{TextUnmarshaler: cbor.TextUnmarshalerTextString}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+	return mode
+}()
+
+// DecodeTelemetry decodes a single record from a body of contentType
+func DecodeTelemetry(contentType string, body io.Reader) (*domain.Telemetry, error) {
+	switch mediaType(contentType) {
+	case ContentTypeProtobuf:
+		var record pb.Telemetry
+		if err := unmarshalProto(body, &record); err != nil {
+			return nil, err
+		}
+		return FromProto(&record)
+	case ContentTypeCBOR:
+		var telemetry domain.Telemetry
+		if err := cborDecoder.NewDecoder(body).Decode(&telemetry); err != nil {
+			return nil, err
+		}
+		return &telemetry, nil
+	default:
+		var telemetry domain.Telemetry
+		if err := json.NewDecoder(body).Decode(&telemetry); err != nil {
+			return nil, err
+		}
+		return &telemetry, nil
+	}
+}
+
+// DecodeBatch decodes a list of records from a body of contentType
+func DecodeBatch(contentType string, body io.Reader) ([]domain.Telemetry, error) {
+	var (
+		batch []domain.Telemetry
+		err   error
+	)
+	switch mediaType(contentType) {
+	case ContentTypeProtobuf:
+		var records pb.TelemetryBatch
+		if err := unmarshalProto(body, &records); err != nil {
+			return nil, err
+		}
+		batch = make([]domain.Telemetry, 0, len(records.GetRecords()))
+		for i, record := range records.GetRecords() {
+			telemetry, err := FromProto(record)
+			if err != nil {
+				return nil, fmt.Errorf("record %d: %w", i, err)
+			}
+			batch = append(batch, *telemetry)
+		}
+	case ContentTypeCBOR:
+		err = cborDecoder.NewDecoder(body).Decode(&batch)
+	default:
+		err = json.NewDecoder(body).Decode(&batch)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return batch, nil
+}
+
+// mediaType strips the parameters of contentType
+func mediaType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ContentTypeJSON
+	}
+	return mediaType
+}
+
+func unmarshalProto(body io.Reader, m proto.Message) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, m)
+}
@@ -0,0 +1,156 @@
+package codec
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	pb "github.com/sid-romero/fleetpulse/internal/pb/fleetpulse/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var (
+	testVehicleID = uuid.MustParse("0b6f5c1e-6a0e-4a4f-9d55-2f1f5bb1c0de")
+	testTimestamp = time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC)
+)
+
+const testJSON = `{"vehicleId": "0b6f5c1e-6a0e-4a4f-9d55-2f1f5bb1c0de", "timestamp": "2024-03-01T12:30:00Z", "location": {"lat": 40.4, "lng": -3.7}, "speed": 42, "batteryLevel": 80, "fuelLevel": 55}`
+
+func testProto() *pb.Telemetry {
+	fuel := int32(55)
+	return &pb.Telemetry{
+		VehicleId:    testVehicleID.String(),
+		Timestamp:    timestamppb.New(testTimestamp),
+		Location:     &pb.Location{Lat: 40.4, Lng: -3.7},
+		Speed:        42,
+		BatteryLevel: 80,
+		FuelLevel:    &fuel,
+	}
+}
+
+// testCBOR encodes the test record with the given vehicle ID and timestamp
+// encodings
+func testCBOR(vehicleID, timestamp any) map[string]any {
+	return map[string]any{
+		"vehicleId":    vehicleID,
+		"timestamp":    timestamp,
+		"location":     map[string]any{"lat": 40.4, "lng": -3.7},
+		"speed":        42,
+		"batteryLevel": 80,
+		"fuelLevel":    55,
+	}
+}
+
+func marshalProto(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	data, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("marshal protobuf: %v", err)
+	}
+	return data
+}
+
+func marshalCBOR(t *testing.T, v any) []byte {
+	t.Helper()
+	data, err := cbor.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal CBOR: %v", err)
+	}
+	return data
+}
+
+// checkRecord fails unless telemetry is the test record
+func checkRecord(t *testing.T, telemetry *domain.Telemetry) {
+	t.Helper()
+	switch {
+	case telemetry.VehicleID != testVehicleID:
+		t.Errorf("vehicle = %s, want %s", telemetry.VehicleID, testVehicleID)
+	case !telemetry.Timestamp.Equal(testTimestamp):
+		t.Errorf("timestamp = %v, want %v", telemetry.Timestamp, testTimestamp)
+	case telemetry.Location.Lat != 40.4 || telemetry.Location.Lng != -3.7:
+		t.Errorf("location = %+v, want 40.4, -3.7", telemetry.Location)
+	case telemetry.Speed != 42 || telemetry.BatteryLevel != 80:
+		t.Errorf("speed, battery = %v, %d, want 42, 80", telemetry.Speed, telemetry.BatteryLevel)
+	case telemetry.FuelLevel == nil || *telemetry.FuelLevel != 55:
+		t.Errorf("fuel = %v, want 55", telemetry.FuelLevel)
+	}
+}
+
+func TestDecodeTelemetry(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		wantErr     bool
+	}{
+		{name: "JSON", contentType: ContentTypeJSON, body: []byte(testJSON)},
+		{name: "JSON with a charset", contentType: "application/json; charset=utf-8", body: []byte(testJSON)},
+		{name: "no content type", body: []byte(testJSON)},
+		{name: "unknown content type", contentType: "text/plain", body: []byte(testJSON)},
+		{name: "malformed JSON", contentType: ContentTypeJSON, body: []byte(`{"speed":`), wantErr: true},
+		{name: "Protobuf", contentType: ContentTypeProtobuf, body: marshalProto(t, testProto())},
+		{name: "Protobuf with a bad vehicle ID", contentType: ContentTypeProtobuf, body: marshalProto(t, &pb.Telemetry{VehicleId: "truck-7"}), wantErr: true},
+		{name: "malformed Protobuf", contentType: ContentTypeProtobuf, body: []byte{0xff, 0xff}, wantErr: true},
+		{name: "CBOR", contentType: ContentTypeCBOR, body: marshalCBOR(t, testCBOR(testVehicleID.String(), "2024-03-01T12:30:00Z"))},
+		{name: "CBOR with binary ID and epoch time", contentType: ContentTypeCBOR, body: marshalCBOR(t, testCBOR(testVehicleID[:], testTimestamp.Unix()))},
+		{name: "malformed CBOR", contentType: ContentTypeCBOR, body: []byte{0xbf}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telemetry, err := DecodeTelemetry(tt.contentType, bytes.NewReader(tt.body))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DecodeTelemetry() = %+v, want an error", telemetry)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeTelemetry: %v", err)
+			}
+			checkRecord(t, telemetry)
+		})
+	}
+}
+
+func TestDecodeBatch(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		records     int
+		wantErr     bool
+	}{
+		{name: "JSON", contentType: ContentTypeJSON, body: []byte("[" + testJSON + "," + testJSON + "]"), records: 2},
+		{name: "empty JSON", contentType: ContentTypeJSON, body: []byte("[]")},
+		{name: "JSON object", contentType: ContentTypeJSON, body: []byte(testJSON), wantErr: true},
+		{name: "Protobuf", contentType: ContentTypeProtobuf, body: marshalProto(t, &pb.TelemetryBatch{Records: []*pb.Telemetry{testProto(), testProto()}}), records: 2},
+		{name: "Protobuf with a bad record", contentType: ContentTypeProtobuf, body: marshalProto(t, &pb.TelemetryBatch{Records: []*pb.Telemetry{testProto(), {VehicleId: "truck-7"}}}), wantErr: true},
+		{name: "CBOR", contentType: ContentTypeCBOR, body: marshalCBOR(t, []any{testCBOR(testVehicleID.String(), "2024-03-01T12:30:00Z")}), records: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch, err := DecodeBatch(tt.contentType, bytes.NewReader(tt.body))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("DecodeBatch() = %+v, want an error", batch)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DecodeBatch: %v", err)
+			}
+			if len(batch) != tt.records {
+				t.Fatalf("decoded %d records, want %d", len(batch), tt.records)
+			}
+			for i := range batch {
+				checkRecord(t, &batch[i])
+			}
+		})
+	}
+}
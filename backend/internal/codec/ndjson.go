@@ -0,0 +1,92 @@
+package codec
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// ContentTypeNDJSON is newline-delimited JSON: one telemetry record per line
+const ContentTypeNDJSON = "application/x-ndjson"
+
+// maxLineSize bounds a single NDJSON record
+const maxLineSize = 64 << 10
+
+// IsNDJSON reports whether contentType is newline-delimited JSON
+func IsNDJSON(contentType string) bool {
+	return mediaType(contentType) == ContentTypeNDJSON
+}
+
+// ErrMalformedLine is returned for a line that is not a JSON telemetry
+// record
+var ErrMalformedLine = errors.New("malformed line")
+
+// LineReader reads newline-delimited JSON telemetry one record at a time,
+// so a body of any length is decoded in bounded memory
+type LineReader struct {
+	reader *bufio.Reader
+	line   int
+}
+
+// NewLineReader creates a LineReader reading from body
+func NewLineReader(body io.Reader) *LineReader {
+	return &LineReader{reader: bufio.NewReaderSize(body, maxLineSize)}
+}
+
+// Next returns the next record and its line number, skipping blank lines.
+// A line that is not a JSON record returns an error wrapping
+// ErrMalformedLine and reading can go on. Any other error ends the body
+// before the returned line, and io.EOF marks its end.
+func (r *LineReader) Next() (int, *domain.Telemetry, error) {
+	for {
+		data, err := r.reader.ReadSlice('\n')
+		switch {
+		case errors.Is(err, io.EOF) && len(data) == 0:
+			return r.line, nil, io.EOF
+		case errors.Is(err, bufio.ErrBufferFull):
+			if err := r.skipLine(); err != nil {
+				return r.line + 1, nil, err
+			}
+			r.line++
+			return r.line, nil, fmt.Errorf("%w: longer than %d bytes", ErrMalformedLine, maxLineSize)
+		case err != nil && !errors.Is(err, io.EOF):
+			return r.line + 1, nil, err
+		}
+
+		r.line++
+		data = bytes.TrimSpace(data)
+		if len(data) == 0 {
+			continue
+		}
+
+		var telemetry domain.Telemetry
+		if err := json.Unmarshal(data, &telemetry); err != nil {
+			return r.line, nil, fmt.Errorf("%w: %v", ErrMalformedLine, err)
+		}
+		return r.line, &telemetry, nil
+	}
+}
+
+// Lines returns the number of lines read in full so far
+func (r *LineReader) Lines() int {
+	return r.line
+}
+
+// skipLine discards the rest of the current line
+func (r *LineReader) skipLine() error {
+	for {
+		_, err := r.reader.ReadSlice('\n')
+		if errors.Is(err, bufio.ErrBufferFull) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	}
+}
@@ -0,0 +1,106 @@
+package codec
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLineReader(t *testing.T) {
+	longLine := `{"speed": 42, "address": "` + strings.Repeat("x", maxLineSize) + `"}`
+
+	// result is what Next returns: the line number, and whether it returned
+	// a record, a malformed line or another error
+	type result struct {
+		line int
+		kind string // record, malformed or error
+	}
+
+	tests := []struct {
+		name    string
+		body    io.Reader
+		results []result
+		lines   int
+	}{
+		{
+			name:    "records",
+			body:    strings.NewReader(testJSON + "\n" + testJSON + "\n"),
+			results: []result{{1, "record"}, {2, "record"}},
+			lines:   2,
+		},
+		{
+			name:    "final line without a newline",
+			body:    strings.NewReader(testJSON + "\n" + testJSON),
+			results: []result{{1, "record"}, {2, "record"}},
+			lines:   2,
+		},
+		{
+			name:    "blank lines",
+			body:    strings.NewReader("\n" + testJSON + "\n  \r\n" + testJSON + "\r\n\n"),
+			results: []result{{2, "record"}, {4, "record"}},
+			lines:   5,
+		},
+		{
+			name:    "empty body",
+			body:    strings.NewReader(""),
+			results: nil,
+		},
+		{
+			name:    "malformed line",
+			body:    strings.NewReader(testJSON + "\n{\"speed\":\n" + testJSON + "\n"),
+			results: []result{{1, "record"}, {2, "malformed"}, {3, "record"}},
+			lines:   3,
+		},
+		{
+			name:    "over-long line",
+			body:    strings.NewReader(longLine + "\n" + testJSON + "\n"),
+			results: []result{{1, "malformed"}, {2, "record"}},
+			lines:   2,
+		},
+		{
+			name:    "body cut short",
+			body:    io.MultiReader(strings.NewReader(testJSON+"\n"+testJSON[:20]), iotest.ErrReader(io.ErrUnexpectedEOF)),
+			results: []result{{1, "record"}, {2, "error"}},
+			lines:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := NewLineReader(tt.body)
+
+			var results []result
+			for {
+				line, telemetry, err := reader.Next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				if errors.Is(err, ErrMalformedLine) {
+					results = append(results, result{line, "malformed"})
+					continue
+				}
+				if err != nil {
+					results = append(results, result{line, "error"})
+					break
+				}
+				checkRecord(t, telemetry)
+				results = append(results, result{line, "record"})
+			}
+
+			if len(results) != len(tt.results) {
+				t.Fatalf("Next() returned %v, want %v", results, tt.results)
+			}
+			for i := range results {
+				if results[i] != tt.results[i] {
+					t.Errorf("Next() returned %v, want %v", results, tt.results)
+					break
+				}
+			}
+			if reader.Lines() != tt.lines {
+				t.Errorf("Lines() = %d, want %d", reader.Lines(), tt.lines)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package codec
+
+import (
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	pb "github.com/sid-romero/fleetpulse/internal/pb/fleetpulse/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromProto converts a received record. An empty vehicle ID or timestamp is
+// left zero for the ingestion pipeline to fill in.
+func FromProto(t *pb.Telemetry) (*domain.Telemetry, error) {
+	if t == nil {
+		return nil, &domain.ValidationError{Field: "telemetry", Message: "is required"}
+	}
+
+	telemetry := &domain.Telemetry{
+		Location: domain.Location{
+			Lat:     t.GetLocation().GetLat(),
+			Lng:     t.GetLocation().GetLng(),
+			Address: t.GetLocation().GetAddress(),
+		},
+		Speed:           t.GetSpeed(),
+		BatteryLevel:    int(t.GetBatteryLevel()),
+		EngineTemp:      t.GetEngineTemp(),
+		EngineRPM:       int(t.GetEngineRpm()),
+		Heading:         t.GetHeading(),
+		Ignition:        t.Ignition,
+		ChargingCurrent: t.ChargingCurrent,
+	}
+	if t.VehicleId != "" {
+		id, err := uuid.Parse(t.VehicleId)
+		if err != nil {
+			return nil, &domain.ValidationError{Field: "vehicleId", Message: "must be a UUID"}
+		}
+		telemetry.VehicleID = id
+	}
+	if t.Timestamp != nil {
+		telemetry.Timestamp = t.Timestamp.AsTime()
+	}
+	if t.FuelLevel != nil {
+		fuel := int(*t.FuelLevel)
+		telemetry.FuelLevel = &fuel
+	}
+	return telemetry, nil
+}
+
+// ToProto converts a stored record
+func ToProto(t *domain.Telemetry) *pb.Telemetry {
+	telemetry := &pb.Telemetry{
+		Id:        t.ID.String(),
+		VehicleId: t.VehicleID.String(),
+		Timestamp: timestamppb.New(t.Timestamp),
+		Location: &pb.Location{
+			Lat:     t.Location.Lat,
+			Lng:     t.Location.Lng,
+			Address: t.Location.Address,
+		},
+		Speed:           t.Speed,
+		BatteryLevel:    int32(t.BatteryLevel),
+		EngineTemp:      t.EngineTemp,
+		EngineRpm:       int32(t.EngineRPM),
+		Heading:         t.Heading,
+		Ignition:        t.Ignition,
+		ChargingCurrent: t.ChargingCurrent,
+	}
+	if t.FuelLevel != nil {
+		fuel := int32(*t.FuelLevel)
+		telemetry.FuelLevel = &fuel
+	}
+	return telemetry
+}
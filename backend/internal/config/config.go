@@ -8,11 +8,21 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	Logging  LoggingConfig
-	CORS     CORSConfig
+	Server     ServerConfig
+	Database   DatabaseConfig
+	Redis      RedisConfig
+	Logging    LoggingConfig
+	CORS       CORSConfig
+	Alerts     AlertsConfig
+	Events     EventsConfig
+	Auth       AuthConfig
+	Trips      TripsConfig
+	Rollups    RollupsConfig
+	Retention  RetentionConfig
+	Scoring    ScoringConfig
+	MQTT       MQTTConfig
+	GRPC       GRPCConfig
+	Validation ValidationConfig
 }
 
 // ServerConfig holds HTTP server settings
@@ -22,11 +32,13 @@ type ServerConfig struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
-	Environment     string // development, staging, production
+	StreamTimeout   time.Duration // how long a streamed telemetry upload may take
+	Environment     string        // development, staging, production
 }
 
 // DatabaseConfig holds PostgreSQL settings
 type DatabaseConfig struct {
+	Driver          string // postgres, memory
 	Host            string
 	Port            int
 	User            string
@@ -58,10 +70,111 @@ type CORSConfig struct {
 	AllowedOrigins   []string
 	AllowedMethods   []string
 	AllowedHeaders   []string
+	ExposedHeaders   []string
 	AllowCredentials bool
 	MaxAge           int
 }
 
+// AlertsConfig holds thresholds for the built-in alert rules
+type AlertsConfig struct {
+	RulesFile       string  // optional JSON rule set replacing the built-in rules
+	SpeedLimit      float64 // km/h
+	BatteryLow      float64 // percent
+	BatteryCritical float64 // percent
+	EngineTempMax   float64 // Celsius
+	EngineRPMMax    float64
+	Hysteresis      float64       // fraction of the threshold a value must recover by to re-arm a rule
+	Cooldown        time.Duration // minimum gap between repeated alerts for the same vehicle and rule
+}
+
+// EventsConfig holds domain event bus settings
+type EventsConfig struct {
+	QueueSize    int    // events buffered per subscriber before new ones are dropped
+	Transport    string // local or redis; redis fans events out across API replicas
+	Stream       string // Redis stream carrying events between replicas
+	StreamMaxLen int64  // approximate number of events retained in the stream
+}
+
+// AuthConfig holds JWT bearer authentication settings. Tokens are verified
+// with JWTSecret (HS256) and/or the RSA keys in JWKSFile (RS256).
+type AuthConfig struct {
+	Enabled   bool
+	JWTSecret string
+	JWKSFile  string
+	Issuer    string // expected iss claim, unchecked when empty
+	Audience  string // expected aud claim, unchecked when empty
+	Leeway    time.Duration
+}
+
+// TripsConfig holds the thresholds used to segment telemetry into trips
+type TripsConfig struct {
+	MinSpeed    float64       // km/h; slower records count as stationary
+	IdleTimeout time.Duration // stationary or silent time that ends a trip
+	MinDistance float64       // meters; shorter trips are discarded as GPS drift
+}
+
+// RollupsConfig holds settings for the telemetry rollup job
+type RollupsConfig struct {
+	Interval time.Duration // how often rollups are brought up to date
+	Lateness time.Duration // how far back late telemetry is folded into rollups
+}
+
+// RetentionConfig holds how many days each telemetry tier is kept; 0 keeps
+// a tier forever
+type RetentionConfig struct {
+	Interval      time.Duration // between pruning passes
+	RawDays       int
+	Rollup1mDays  int
+	Rollup15mDays int
+	Rollup1hDays  int
+}
+
+// ScoringConfig holds the thresholds of the risky driving events that driver
+// scores are computed from
+type ScoringConfig struct {
+	HarshAcceleration float64       // km/h gained per second
+	HarshBraking      float64       // km/h lost per second
+	MaxGap            time.Duration // records further apart are not compared for harsh events
+	SpeedLimit        float64       // km/h
+	IdleTimeout       time.Duration // stationary time with the ignition on that counts as idling
+	NightStart        int           // hour night driving starts, in TimeZone
+	NightEnd          int           // hour night driving ends, in TimeZone
+	TimeZone          string        // IANA zone of the night hours
+	Window            time.Duration // default period scores are computed over
+	Interval          time.Duration // how often driver ratings are refreshed from scores
+}
+
+// MQTTConfig holds the settings of the MQTT telemetry subscriber. Devices
+// publish to <TopicPrefix>/<vehicleId>/telemetry, or /telemetry/batch for an
+// array of records.
+type MQTTConfig struct {
+	Enabled      bool
+	BrokerURL    string // such as tcp://localhost:1883 or ssl://broker:8883
+	ClientID     string // must be unique per API replica
+	Username     string
+	Password     string
+	TopicPrefix  string
+	SharedGroup  string // subscribes through $share/<group>/ so replicas split the messages
+	TelemetryQoS byte   // QoS of the single record subscription
+	BatchQoS     byte   // QoS of the batch subscription
+}
+
+// GRPCConfig holds the settings of the gRPC telemetry streaming server
+type GRPCConfig struct {
+	Enabled          bool
+	Port             int
+	SubscriberBuffer int // records queued per Subscribe stream before it is dropped as too slow
+}
+
+// ValidationConfig holds the bounds a telemetry record must keep to relative
+// to the server clock and the vehicle's previous record
+type ValidationConfig struct {
+	MaxClockSkew time.Duration // how far ahead of the server clock a timestamp may be
+	MaxAge       time.Duration // how far behind it a timestamp may be; 0 accepts any age
+	MaxSpeed     float64       // km/h; a faster implied move from the previous position is a jump
+	MinJump      float64       // meters; shorter moves are put down to GPS jitter
+}
+
 // Load loads configuration from environment variables
 func Load() *Config {
 	return &Config{
@@ -71,9 +184,11 @@ func Load() *Config {
 			ReadTimeout:     getEnvAsDuration("SERVER_READ_TIMEOUT", 15*time.Second),
 			WriteTimeout:    getEnvAsDuration("SERVER_WRITE_TIMEOUT", 15*time.Second),
 			ShutdownTimeout: getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", 30*time.Second),
+			StreamTimeout:   getEnvAsDuration("SERVER_STREAM_TIMEOUT", 10*time.Minute),
 			Environment:     getEnv("ENVIRONMENT", "development"),
 		},
 		Database: DatabaseConfig{
+			Driver:          getEnv("DB_DRIVER", "postgres"),
 			Host:            getEnv("DB_HOST", "localhost"),
 			Port:            getEnvAsInt("DB_PORT", 5432),
 			User:            getEnv("DB_USER", "fleetpulse"),
@@ -99,9 +214,85 @@ func Load() *Config {
 			AllowedOrigins:   getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:5173", "http://localhost:3000"}),
 			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 			AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-Request-ID"},
+			ExposedHeaders:   []string{"X-Telemetry-Resolution"},
 			AllowCredentials: true,
 			MaxAge:           300,
 		},
+		Alerts: AlertsConfig{
+			RulesFile:       getEnv("ALERT_RULES_FILE", ""),
+			SpeedLimit:      getEnvAsFloat("ALERT_SPEED_LIMIT", 110),
+			BatteryLow:      getEnvAsFloat("ALERT_BATTERY_LOW", 20),
+			BatteryCritical: getEnvAsFloat("ALERT_BATTERY_CRITICAL", 10),
+			EngineTempMax:   getEnvAsFloat("ALERT_ENGINE_TEMP_MAX", 105),
+			EngineRPMMax:    getEnvAsFloat("ALERT_ENGINE_RPM_MAX", 4500),
+			Hysteresis:      getEnvAsFloat("ALERT_HYSTERESIS", 0.1),
+			Cooldown:        getEnvAsDuration("ALERT_COOLDOWN", 15*time.Minute),
+		},
+		Events: EventsConfig{
+			QueueSize:    getEnvAsInt("EVENT_QUEUE_SIZE", 1024),
+			Transport:    getEnv("EVENT_TRANSPORT", "local"),
+			Stream:       getEnv("EVENT_STREAM", "fleetpulse:events"),
+			StreamMaxLen: int64(getEnvAsInt("EVENT_STREAM_MAXLEN", 10000)),
+		},
+		Auth: AuthConfig{
+			// Required by default everywhere but local development
+			Enabled:   getEnvAsBool("AUTH_ENABLED", getEnv("ENVIRONMENT", "development") != "development"),
+			JWTSecret: getEnv("AUTH_JWT_SECRET", ""),
+			JWKSFile:  getEnv("AUTH_JWKS_FILE", ""),
+			Issuer:    getEnv("AUTH_ISSUER", ""),
+			Audience:  getEnv("AUTH_AUDIENCE", ""),
+			Leeway:    getEnvAsDuration("AUTH_LEEWAY", 30*time.Second),
+		},
+		Trips: TripsConfig{
+			MinSpeed:    getEnvAsFloat("TRIP_MIN_SPEED", 5),
+			IdleTimeout: getEnvAsDuration("TRIP_IDLE_TIMEOUT", 5*time.Minute),
+			MinDistance: getEnvAsFloat("TRIP_MIN_DISTANCE", 200),
+		},
+		Rollups: RollupsConfig{
+			Interval: getEnvAsDuration("ROLLUP_INTERVAL", time.Minute),
+			Lateness: getEnvAsDuration("ROLLUP_LATENESS", time.Hour),
+		},
+		Retention: RetentionConfig{
+			Interval:      getEnvAsDuration("RETENTION_INTERVAL", time.Hour),
+			RawDays:       getEnvAsInt("RETENTION_RAW_DAYS", 30),
+			Rollup1mDays:  getEnvAsInt("RETENTION_1M_DAYS", 90),
+			Rollup15mDays: getEnvAsInt("RETENTION_15M_DAYS", 365),
+			Rollup1hDays:  getEnvAsInt("RETENTION_1H_DAYS", 0),
+		},
+		Scoring: ScoringConfig{
+			HarshAcceleration: getEnvAsFloat("SCORING_HARSH_ACCELERATION", 10),
+			HarshBraking:      getEnvAsFloat("SCORING_HARSH_BRAKING", 13),
+			MaxGap:            getEnvAsDuration("SCORING_MAX_GAP", 10*time.Second),
+			SpeedLimit:        getEnvAsFloat("SCORING_SPEED_LIMIT", 100),
+			IdleTimeout:       getEnvAsDuration("SCORING_IDLE_TIMEOUT", 5*time.Minute),
+			NightStart:        getEnvAsInt("SCORING_NIGHT_START", 22),
+			NightEnd:          getEnvAsInt("SCORING_NIGHT_END", 5),
+			TimeZone:          getEnv("SCORING_TIMEZONE", "UTC"),
+			Window:            getEnvAsDuration("SCORING_WINDOW", 30*24*time.Hour),
+			Interval:          getEnvAsDuration("SCORING_INTERVAL", time.Hour),
+		},
+		MQTT: MQTTConfig{
+			Enabled:      getEnvAsBool("MQTT_ENABLED", false),
+			BrokerURL:    getEnv("MQTT_BROKER_URL", "tcp://localhost:1883"),
+			ClientID:     getEnv("MQTT_CLIENT_ID", "fleetpulse-api"),
+			Username:     getEnv("MQTT_USERNAME", ""),
+			Password:     getEnv("MQTT_PASSWORD", ""),
+			TopicPrefix:  getEnv("MQTT_TOPIC_PREFIX", "fleet"),
+			SharedGroup:  getEnv("MQTT_SHARED_GROUP", ""),
+			TelemetryQoS: byte(getEnvAsInt("MQTT_TELEMETRY_QOS", 1)),
+			BatchQoS:     byte(getEnvAsInt("MQTT_BATCH_QOS", 1)),
+		},
+		GRPC: GRPCConfig{
+			Enabled:          getEnvAsBool("GRPC_ENABLED", true),
+			Port:             getEnvAsInt("GRPC_PORT", 9090),
+			SubscriberBuffer: getEnvAsInt("GRPC_SUBSCRIBER_BUFFER", 256),
+		},
+		Validation: ValidationConfig{
+			MaxClockSkew: getEnvAsDuration("TELEMETRY_MAX_CLOCK_SKEW", 5*time.Minute),
+			MaxAge:       getEnvAsDuration("TELEMETRY_MAX_AGE", 7*24*time.Hour),
+			MaxSpeed:     getEnvAsFloat("TELEMETRY_MAX_SPEED", 300),
+			MinJump:      getEnvAsFloat("TELEMETRY_MIN_JUMP", 500),
+		},
 	}
 }
 
@@ -123,6 +314,15 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsFloat(key string, defaultValue float64) float64 {
+	if value, exists := os.LookupEnv(key); exists {
+		if floatVal, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatVal
+		}
+	}
+	return defaultValue
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	if value, exists := os.LookupEnv(key); exists {
 		if boolVal, err := strconv.ParseBool(value); err == nil {
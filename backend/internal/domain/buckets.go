@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Bucket is the width of an analytics time bucket
+type Bucket string
+
+const (
+	BucketHour  Bucket = "hour"
+	BucketDay   Bucket = "day"
+	BucketWeek  Bucket = "week" // starting Monday
+	BucketMonth Bucket = "month"
+)
+
+// Valid reports whether b is a known bucket width
+func (b Bucket) Valid() bool {
+	switch b {
+	case BucketHour, BucketDay, BucketWeek, BucketMonth:
+		return true
+	}
+	return false
+}
+
+// Truncate returns the start of the bucket containing t, with day, week and
+// month boundaries taken in loc
+func (b Bucket) Truncate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	y, m, d := t.Date()
+
+	switch b {
+	case BucketHour:
+		return time.Date(y, m, d, t.Hour(), 0, 0, 0, loc)
+	case BucketWeek:
+		offset := (int(t.Weekday()) + 6) % 7 // days since Monday
+		return time.Date(y, m, d-offset, 0, 0, 0, 0, loc)
+	case BucketMonth:
+		return time.Date(y, m, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+}
+
+// Next returns the start of the bucket following the one starting at start
+func (b Bucket) Next(start time.Time) time.Time {
+	y, m, d := start.Date()
+	loc := start.Location()
+
+	switch b {
+	case BucketHour:
+		return start.Add(time.Hour)
+	case BucketWeek:
+		return time.Date(y, m, d+7, 0, 0, 0, 0, loc)
+	case BucketMonth:
+		return time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+}
+
+// TelemetryBucket sums a vehicle's telemetry over one time bucket. Each
+// movement between consecutive records counts towards the bucket of the
+// later record.
+type TelemetryBucket struct {
+	VehicleID  uuid.UUID
+	Start      time.Time
+	DistanceKm float64
+	EnergyUsed float64 // battery percentage points drained; charging is not netted off
+	Samples    int
+}
+
+// Resolution is the granularity of telemetry history: raw records or one of
+// the rolled-up aggregates
+type Resolution string
+
+const (
+	ResolutionRaw Resolution = "raw"
+	Resolution1m  Resolution = "1m"
+	Resolution15m Resolution = "15m"
+	Resolution1h  Resolution = "1h"
+)
+
+// RollupResolutions are the resolutions maintained by the rollup job
+var RollupResolutions = []Resolution{Resolution1m, Resolution15m, Resolution1h}
+
+// RollupMaxGap is the longest gap between consecutive records whose
+// movement counts towards a rollup's distance; across longer gaps the vehicle
+// was offline and the straight line between fixes isn't a driven distance
+const RollupMaxGap = time.Hour
+
+// Interval returns the bucket width of a rolled-up resolution, or 0 for raw
+// and unknown resolutions
+func (r Resolution) Interval() time.Duration {
+	switch r {
+	case Resolution1m:
+		return time.Minute
+	case Resolution15m:
+		return 15 * time.Minute
+	case Resolution1h:
+		return time.Hour
+	}
+	return 0
+}
+
+// ResolutionFor picks the coarsest resolution a history query needs, so
+// that long ranges return a few thousand points at most
+func ResolutionFor(span time.Duration) Resolution {
+	switch {
+	case span <= 6*time.Hour:
+		return ResolutionRaw
+	case span <= 3*24*time.Hour:
+		return Resolution1m
+	case span <= 30*24*time.Hour:
+		return Resolution15m
+	default:
+		return Resolution1h
+	}
+}
+
+// TelemetryRollup summarizes a vehicle's telemetry over one bucket of a
+// rolled-up resolution. Timestamp, Location, Speed, BatteryLevel and
+// EngineTemp mirror domain.Telemetry so charts can plot either.
+type TelemetryRollup struct {
+	VehicleID     uuid.UUID  `json:"vehicleId"`
+	Resolution    Resolution `json:"resolution"`
+	Timestamp     time.Time  `json:"timestamp"` // bucket start
+	Samples       int        `json:"samples"`
+	Location      Location   `json:"location"` // last position in the bucket
+	Speed         float32    `json:"speed"`    // average
+	SpeedMin      float32    `json:"speedMin"`
+	SpeedMax      float32    `json:"speedMax"`
+	BatteryLevel  float32    `json:"batteryLevel"` // average
+	BatteryMin    int        `json:"batteryMin"`
+	BatteryMax    int        `json:"batteryMax"`
+	EngineTemp    float32    `json:"engineTemp"` // average
+	EngineTempMin float32    `json:"engineTempMin"`
+	EngineTempMax float32    `json:"engineTempMax"`
+	DistanceKm    float64    `json:"distanceKm"`
+}
@@ -0,0 +1,13 @@
+package domain
+
+import "errors"
+
+// ErrNotFound is returned when a requested entity does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrForbidden is returned when the caller may not act on an entity
+var ErrForbidden = errors.New("forbidden")
+
+// ErrConflict is returned when a write would violate a uniqueness constraint
+// or conflicts with the entity's current state
+var ErrConflict = errors.New("conflict")
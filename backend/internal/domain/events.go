@@ -12,15 +12,23 @@ type EventType string
 
 const (
 	EventTypeTelemetryReceived   EventType = "telemetry.received"
+	EventTypeVehicleUpdated      EventType = "vehicle.updated"
 	EventTypeVehicleStatusChange EventType = "vehicle.status.changed"
 	EventTypeAlertCreated        EventType = "alert.created"
 	EventTypeAlertAcknowledged   EventType = "alert.acknowledged"
 	EventTypeAlertResolved       EventType = "alert.resolved"
 	EventTypeGeofenceEnter       EventType = "geofence.enter"
 	EventTypeGeofenceExit        EventType = "geofence.exit"
+	EventTypeGeofenceCreated     EventType = "geofence.created"
+	EventTypeGeofenceUpdated     EventType = "geofence.updated"
+	EventTypeGeofenceDeleted     EventType = "geofence.deleted"
 	EventTypeSpeedExceeded       EventType = "speed.exceeded"
 	EventTypeBatteryLow          EventType = "battery.low"
 	EventTypeMaintenanceDue      EventType = "maintenance.due"
+	EventTypeTripCompleted       EventType = "trip.completed"
+	EventTypeDriverAssigned      EventType = "driver.assigned"
+	EventTypeDriverUnassigned    EventType = "driver.unassigned"
+	EventTypeFleetStatsUpdated   EventType = "fleet.stats.updated"
 )
 
 // Event represents a domain event
@@ -53,6 +61,7 @@ type TelemetryEventData struct {
 	VehicleID    uuid.UUID `json:"vehicleId"`
 	Telemetry    Telemetry `json:"telemetry"`
 	PreviousData *Telemetry `json:"previousData,omitempty"`
+	Vehicle      *Vehicle   `json:"vehicle,omitempty"` // snapshot when the record was published
 }
 
 // VehicleStatusChangeData payload
@@ -61,6 +70,7 @@ type VehicleStatusChangeData struct {
 	PreviousStatus VehicleStatus `json:"previousStatus"`
 	NewStatus     VehicleStatus `json:"newStatus"`
 	Reason        string        `json:"reason,omitempty"`
+	ChangedAt     time.Time     `json:"changedAt"`
 }
 
 // AlertEventData payload
@@ -78,6 +88,12 @@ type GeofenceEventData struct {
 	EventType   string    `json:"eventType"` // enter, exit
 }
 
+// GeofenceChangeData payload; Geofence is nil once deleted
+type GeofenceChangeData struct {
+	GeofenceID uuid.UUID `json:"geofenceId"`
+	Geofence   *Geofence `json:"geofence,omitempty"`
+}
+
 // SpeedExceededData payload
 type SpeedExceededData struct {
 	VehicleID    uuid.UUID `json:"vehicleId"`
@@ -93,3 +109,20 @@ type BatteryLowData struct {
 	Range        int       `json:"range"`
 	Location     Location  `json:"location"`
 }
+
+// MaintenanceDueData payload
+type MaintenanceDueData struct {
+	VehicleID       uuid.UUID  `json:"vehicleId"`
+	PlanID          uuid.UUID  `json:"planId"`
+	Type            string     `json:"type"`
+	Reason          string     `json:"reason"` // distance, time
+	Odometer        int        `json:"odometer"`
+	NextDueOdometer *int       `json:"nextDueOdometer,omitempty"`
+	NextDueDate     *time.Time `json:"nextDueDate,omitempty"`
+}
+
+// DriverAssignmentData payload
+type DriverAssignmentData struct {
+	Assignment DriverAssignment `json:"assignment"`
+	ActionBy   *uuid.UUID       `json:"actionBy,omitempty"`
+}
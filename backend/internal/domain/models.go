@@ -34,6 +34,16 @@ const (
 	AlertStatusResolved     AlertStatus = "resolved"
 )
 
+// UserRole determines what an operator may do
+type UserRole string
+
+const (
+	UserRoleAdmin      UserRole = "admin"
+	UserRoleDispatcher UserRole = "dispatcher"
+	UserRoleViewer     UserRole = "viewer"
+	UserRoleDevice     UserRole = "device" // telemetry-reporting units
+)
+
 // Location represents a geographic position
 type Location struct {
 	Lat     float64 `json:"lat"`
@@ -53,6 +63,51 @@ type Driver struct {
 	UpdatedAt time.Time `json:"updatedAt"`
 }
 
+// DriverAssignment is a period during which a driver was assigned to a
+// vehicle; it is open until the driver is unassigned
+type DriverAssignment struct {
+	ID           uuid.UUID  `json:"id"`
+	DriverID     uuid.UUID  `json:"driverId"`
+	VehicleID    uuid.UUID  `json:"vehicleId"`
+	AssignedAt   time.Time  `json:"assignedAt"`
+	UnassignedAt *time.Time `json:"unassignedAt,omitempty"`
+	AssignedBy   *uuid.UUID `json:"assignedBy,omitempty"` // user who made the assignment
+}
+
+// Covers reports whether the driver was assigned at t
+func (a *DriverAssignment) Covers(t time.Time) bool {
+	return !t.Before(a.AssignedAt) && (a.UnassignedAt == nil || t.Before(*a.UnassignedAt))
+}
+
+// User represents an operator of the FleetPulse API
+type User struct {
+	ID        uuid.UUID `json:"id"`
+	Email     string    `json:"email"`
+	Name      string    `json:"name"`
+	Role      UserRole  `json:"role"`
+	IsActive  bool      `json:"isActive"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// DeviceKey is an API key letting a telematics unit report telemetry for a
+// single vehicle. Only a hash of the secret is stored.
+type DeviceKey struct {
+	ID        uuid.UUID  `json:"id"`
+	VehicleID uuid.UUID  `json:"vehicleId"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"` // leading characters of the key, for identification
+	KeyHash   string     `json:"-"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// IssuedDeviceKey is a newly issued key; Key is shown only this once
+type IssuedDeviceKey struct {
+	DeviceKey
+	Key string `json:"key"`
+}
+
 // Vehicle represents a fleet vehicle
 type Vehicle struct {
 	ID           uuid.UUID     `json:"id"`
@@ -103,42 +158,157 @@ type Telemetry struct {
 	FuelLevel    *int      `json:"fuelLevel,omitempty"`
 	EngineTemp   float32   `json:"engineTemp"`
 	EngineRPM    int       `json:"engineRpm"`
-	Heading      float32   `json:"heading"` // degrees
+	Heading      float32   `json:"heading"`            // degrees
+	Ignition     *bool     `json:"ignition,omitempty"` // unset for units without an ignition sense line
+	// ChargingCurrent is the current drawn from a charger in amps, 0 when
+	// unplugged; unset for units that don't report it
+	ChargingCurrent *float32 `json:"chargingCurrent,omitempty"`
+}
+
+// TripStatus represents the lifecycle of a trip
+type TripStatus string
+
+const (
+	TripStatusInProgress TripStatus = "in_progress"
+	TripStatusCompleted  TripStatus = "completed"
+)
+
+// Trip end reasons
+const (
+	TripEndIgnitionOff = "ignition_off"
+	TripEndIdle        = "idle"
+	TripEndSignalLost  = "signal_lost"
+)
+
+// Trip is a continuous journey of a vehicle, segmented from its telemetry
+type Trip struct {
+	ID              uuid.UUID   `json:"id"`
+	VehicleID       uuid.UUID   `json:"vehicleId"`
+	DriverID        *uuid.UUID  `json:"driverId,omitempty"`
+	Status          TripStatus  `json:"status"`
+	StartedAt       time.Time   `json:"startedAt"`
+	EndedAt         *time.Time  `json:"endedAt,omitempty"`
+	EndReason       string      `json:"endReason,omitempty"` // ignition_off, idle, signal_lost
+	StartLocation   Location    `json:"startLocation"`
+	EndLocation     Location    `json:"endLocation"`
+	DistanceKm      float64     `json:"distanceKm"`
+	DurationSeconds int64       `json:"durationSeconds"`
+	EnergyUsed      int         `json:"energyUsed"` // battery percentage points
+	FuelUsed        *int        `json:"fuelUsed,omitempty"`
+	MaxSpeed        float32     `json:"maxSpeed"` // km/h
+	AvgSpeed        float32     `json:"avgSpeed"` // km/h
+	Path            []TripPoint `json:"path,omitempty"`
+}
+
+// TripPoint is one position on a trip's polyline
+type TripPoint struct {
+	Lat       float64   `json:"lat"`
+	Lng       float64   `json:"lng"`
+	Speed     float32   `json:"speed"`
+	Timestamp time.Time `json:"timestamp"`
 }
 
 // FleetStats represents aggregated fleet statistics
 type FleetStats struct {
 	ActiveVehicles   int     `json:"activeVehicles"`
 	TotalVehicles    int     `json:"totalVehicles"`
-	CriticalAlerts   int     `json:"criticalAlerts"`
-	TotalDistanceKm  float64 `json:"totalDistanceKm"`
-	AvgEfficiency    float64 `json:"avgEfficiency"`
+	CriticalAlerts   int     `json:"criticalAlerts"`  // active, unacknowledged
+	TotalDistanceKm  float64 `json:"totalDistanceKm"` // sum of odometers
+	AvgEfficiency    float64 `json:"avgEfficiency"`   // kWh/100km
 	VehiclesCharging int     `json:"vehiclesCharging"`
 	Timestamp        time.Time `json:"timestamp"`
 }
 
+// MaintenanceStatus represents the lifecycle of a maintenance record
+type MaintenanceStatus string
+
+const (
+	MaintenanceStatusScheduled  MaintenanceStatus = "scheduled"
+	MaintenanceStatusInProgress MaintenanceStatus = "in_progress"
+	MaintenanceStatusCompleted  MaintenanceStatus = "completed"
+	MaintenanceStatusCancelled  MaintenanceStatus = "cancelled"
+)
+
 // MaintenanceRecord represents vehicle service history
 type MaintenanceRecord struct {
-	ID          uuid.UUID `json:"id"`
-	VehicleID   uuid.UUID `json:"vehicleId"`
-	Date        time.Time `json:"date"`
-	Type        string    `json:"type"`
-	Description string    `json:"description"`
-	Cost        float64   `json:"cost"`
-	Status      string    `json:"status"` // scheduled, completed, cancelled
-	Technician  string    `json:"technician,omitempty"`
-	Notes       string    `json:"notes,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID          uuid.UUID         `json:"id"`
+	VehicleID   uuid.UUID         `json:"vehicleId"`
+	PlanID      *uuid.UUID        `json:"planId,omitempty"` // plan the service fulfils
+	Date        time.Time         `json:"date"`
+	Type        string            `json:"type"`
+	Description string            `json:"description"`
+	Cost        float64           `json:"cost"`
+	Status      MaintenanceStatus `json:"status"`
+	Odometer    *int              `json:"odometer,omitempty"` // km at service
+	Technician  string            `json:"technician,omitempty"`
+	Notes       string            `json:"notes,omitempty"`
+	CompletedAt *time.Time        `json:"completedAt,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
 }
 
+// MaintenancePlan schedules a recurring service every IntervalKm of odometer
+// and/or every IntervalDays, whichever comes first
+type MaintenancePlan struct {
+	ID                  uuid.UUID  `json:"id"`
+	VehicleID           uuid.UUID  `json:"vehicleId"`
+	Type                string     `json:"type"`
+	Description         string     `json:"description,omitempty"`
+	IntervalKm          *int       `json:"intervalKm,omitempty"`
+	IntervalDays        *int       `json:"intervalDays,omitempty"`
+	LastServiceDate     time.Time  `json:"lastServiceDate"`
+	LastServiceOdometer int        `json:"lastServiceOdometer"` // km
+	DueSince            *time.Time `json:"dueSince,omitempty"`  // set once the due event is raised, cleared by the next service
+	IsActive            bool       `json:"isActive"`
+	CreatedAt           time.Time  `json:"createdAt"`
+}
+
+// NextDueDate returns when the plan falls due by time, if it has a period
+func (p *MaintenancePlan) NextDueDate() *time.Time {
+	if p.IntervalDays == nil {
+		return nil
+	}
+	due := p.LastServiceDate.AddDate(0, 0, *p.IntervalDays)
+	return &due
+}
+
+// NextDueOdometer returns the odometer reading at which the plan falls due,
+// if it has a distance interval
+func (p *MaintenancePlan) NextDueOdometer() *int {
+	if p.IntervalKm == nil {
+		return nil
+	}
+	due := p.LastServiceOdometer + *p.IntervalKm
+	return &due
+}
+
+// DueReason returns why the plan is due for a vehicle at odometer km on now,
+// distance or time, or "" while it is not
+func (p *MaintenancePlan) DueReason(odometer int, now time.Time) string {
+	if due := p.NextDueOdometer(); due != nil && odometer >= *due {
+		return "distance"
+	}
+	if due := p.NextDueDate(); due != nil && !now.Before(*due) {
+		return "time"
+	}
+	return ""
+}
+
+// Geofence types
+const (
+	GeofenceTypeCircle  = "circle"
+	GeofenceTypePolygon = "polygon"
+)
+
 // Geofence represents a geographic boundary
 type Geofence struct {
-	ID        uuid.UUID   `json:"id"`
-	Name      string      `json:"name"`
-	Type      string      `json:"type"` // circle, polygon
-	Center    *Location   `json:"center,omitempty"`
-	Radius    *float64    `json:"radius,omitempty"` // meters
-	Polygon   []Location  `json:"polygon,omitempty"`
-	IsActive  bool        `json:"isActive"`
-	CreatedAt time.Time   `json:"createdAt"`
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Type         string     `json:"type"` // circle, polygon
+	Center       *Location  `json:"center,omitempty"`
+	Radius       *float64   `json:"radius,omitempty"` // meters
+	Polygon      []Location `json:"polygon,omitempty"`
+	IsActive     bool       `json:"isActive"`
+	AlertOnEnter bool       `json:"alertOnEnter"`
+	AlertOnExit  bool       `json:"alertOnExit"`
+	CreatedAt    time.Time  `json:"createdAt"`
 }
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RetentionPolicy is how long each telemetry tier is kept: raw records and
+// every rollup resolution
+type RetentionPolicy struct {
+	Days map[Resolution]int `json:"days"` // 0 keeps a tier forever
+}
+
+// Cutoff returns the time before which a tier is pruned, and false when the
+// tier is kept forever
+func (p RetentionPolicy) Cutoff(tier Resolution, now time.Time) (time.Time, bool) {
+	days := p.Days[tier]
+	if days <= 0 {
+		return time.Time{}, false
+	}
+	return now.AddDate(0, 0, -days), true
+}
+
+// RetentionRun summarizes one pass of the pruning job
+type RetentionRun struct {
+	StartedAt  time.Time            `json:"startedAt"`
+	FinishedAt time.Time            `json:"finishedAt"`
+	Deleted    map[Resolution]int64 `json:"deleted"` // rows per tier
+	Error      string               `json:"error,omitempty"`
+}
+
+// TelemetryStorage is the telemetry stored for one vehicle
+type TelemetryStorage struct {
+	VehicleID      uuid.UUID            `json:"vehicleId"`
+	Records        map[Resolution]int64 `json:"records"` // rows per tier
+	OldestRecord   *time.Time           `json:"oldestRecord,omitempty"`
+	NewestRecord   *time.Time           `json:"newestRecord,omitempty"`
+	EstimatedBytes int64                `json:"estimatedBytes"`
+}
+
+// RetentionReport is the retention policy with the storage it governs
+type RetentionReport struct {
+	Policy     RetentionPolicy    `json:"policy"`
+	Interval   string             `json:"interval"` // between pruning passes
+	LastRun    *RetentionRun      `json:"lastRun,omitempty"`
+	TotalBytes int64              `json:"totalBytes"`
+	Vehicles   []TelemetryStorage `json:"vehicles"`
+}
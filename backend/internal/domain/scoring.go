@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DrivingEventType is a kind of risky driving detected from telemetry
+type DrivingEventType string
+
+const (
+	DrivingEventHarshAcceleration DrivingEventType = "harsh_acceleration"
+	DrivingEventHarshBraking      DrivingEventType = "harsh_braking"
+	DrivingEventSpeeding          DrivingEventType = "speeding"
+	DrivingEventIdling            DrivingEventType = "idling"
+	DrivingEventNightDriving      DrivingEventType = "night_driving"
+)
+
+// DrivingEventTypes lists every driving event type, in breakdown order
+var DrivingEventTypes = []DrivingEventType{
+	DrivingEventHarshAcceleration,
+	DrivingEventHarshBraking,
+	DrivingEventSpeeding,
+	DrivingEventIdling,
+	DrivingEventNightDriving,
+}
+
+// DrivingEvent is one occurrence of risky driving, attributed to the driver
+// assigned to the vehicle at the time
+type DrivingEvent struct {
+	ID         uuid.UUID        `json:"id"`
+	DriverID   uuid.UUID        `json:"driverId"`
+	VehicleID  uuid.UUID        `json:"vehicleId"`
+	Type       DrivingEventType `json:"type"`
+	OccurredAt time.Time        `json:"occurredAt"`
+	// km/h per second for harsh acceleration and braking, km/h for speeding
+	// and night driving, seconds stationary for idling
+	Value    float64  `json:"value"`
+	Location Location `json:"location"`
+}
+
+// DriverScore is a driver's safety score over a period: 100 without risky
+// driving, down to 0
+type DriverScore struct {
+	DriverID   uuid.UUID             `json:"driverId"`
+	From       time.Time             `json:"from"`
+	To         time.Time             `json:"to"`
+	Score      float64               `json:"score"`
+	Rating     float32               `json:"rating"` // score on the 0 to 5 scale of Driver.Rating
+	DistanceKm float64               `json:"distanceKm"`
+	Breakdown  []DrivingEventSummary `json:"breakdown"`
+}
+
+// DrivingEventSummary counts a driver's events of one type and the points
+// they cost
+type DrivingEventSummary struct {
+	Type     DrivingEventType `json:"type"`
+	Count    int              `json:"count"`
+	Per100Km float64          `json:"per100Km"`
+	Penalty  float64          `json:"penalty"`
+}
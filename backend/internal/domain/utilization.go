@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VehicleStatusChange records a vehicle moving from one status to another
+type VehicleStatusChange struct {
+	ID             uuid.UUID     `json:"id"`
+	VehicleID      uuid.UUID     `json:"vehicleId"`
+	PreviousStatus VehicleStatus `json:"previousStatus"`
+	Status         VehicleStatus `json:"status"`
+	Reason         string        `json:"reason,omitempty"`
+	ChangedAt      time.Time     `json:"changedAt"`
+}
+
+// VehicleStatusPeriod is a stretch of time a vehicle spent in one status
+type VehicleStatusPeriod struct {
+	Status VehicleStatus `json:"status"`
+	Start  time.Time     `json:"start"`
+	End    time.Time     `json:"end"`
+	Hours  float64       `json:"hours"`
+	Reason string        `json:"reason,omitempty"` // why the vehicle entered the status
+}
+
+// UtilizationReport breaks down the time vehicles spent in each status over
+// a period
+type UtilizationReport struct {
+	From     time.Time            `json:"from"`
+	To       time.Time            `json:"to"`
+	Fleet    []StatusUtilization  `json:"fleet"`
+	Vehicles []VehicleUtilization `json:"vehicles"`
+}
+
+// VehicleUtilization is one vehicle's share of time per status. Time before
+// the vehicle was added is not tracked.
+type VehicleUtilization struct {
+	VehicleID    uuid.UUID           `json:"vehicleId"`
+	Name         string              `json:"name"`
+	TrackedHours float64             `json:"trackedHours"`
+	Statuses     []StatusUtilization `json:"statuses"`
+}
+
+// StatusUtilization is the time spent in one status, as hours and as a
+// percentage of the tracked time
+type StatusUtilization struct {
+	Status  VehicleStatus `json:"status"`
+	Hours   float64       `json:"hours"`
+	Percent float64       `json:"percent"`
+}
@@ -0,0 +1,211 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// ValidationError reports a field that failed validation
+type ValidationError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// RejectReason classifies why a telemetry record was rejected
+type RejectReason string
+
+const (
+	RejectInvalid        RejectReason = "invalid"         // malformed or missing a required field
+	RejectOutOfRange     RejectReason = "out_of_range"    // a reading outside its valid range
+	RejectClockSkew      RejectReason = "clock_skew"      // timestamped in the future
+	RejectTooOld         RejectReason = "too_old"         // timestamped too far in the past
+	RejectJump           RejectReason = "jump"            // further from the last position than the vehicle could travel
+	RejectUnknownVehicle RejectReason = "unknown_vehicle" // no such vehicle
+	RejectForbidden      RejectReason = "forbidden"       // a device reporting for another vehicle
+)
+
+// TelemetryError is the ValidationError of a telemetry record, with the
+// reason it was rejected for
+type TelemetryError struct {
+	ValidationError
+	Reason RejectReason `json:"reason"`
+}
+
+func NewTelemetryError(reason RejectReason, field, message string) *TelemetryError {
+	return &TelemetryError{
+		ValidationError: ValidationError{Field: field, Message: message},
+		Reason:          reason,
+	}
+}
+
+// Unwrap lets errors.As find the ValidationError
+func (e *TelemetryError) Unwrap() error {
+	return &e.ValidationError
+}
+
+// RecordError is the error of one record of a batch
+type RecordError struct {
+	Index int
+	Err   error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Index, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// RejectReasonOf returns the reason a record was rejected with err, and
+// false for failures that are not the record's fault
+func RejectReasonOf(err error) (RejectReason, bool) {
+	var telemetryErr *TelemetryError
+	var validationErr *ValidationError
+	switch {
+	case errors.As(err, &telemetryErr):
+		return telemetryErr.Reason, true
+	case errors.As(err, &validationErr):
+		return RejectInvalid, true
+	case errors.Is(err, ErrNotFound):
+		return RejectUnknownVehicle, true
+	case errors.Is(err, ErrForbidden):
+		return RejectForbidden, true
+	}
+	return "", false
+}
+
+// Validate checks that a telemetry record is well formed and within the
+// ranges accepted by the CHECK constraints of the telemetry table. It
+// returns a *TelemetryError. The comparisons are written so that NaN, which
+// binary encodings can carry, is out of range.
+func (t *Telemetry) Validate() error {
+	outOfRange := func(field, message string) error {
+		return NewTelemetryError(RejectOutOfRange, field, message)
+	}
+	switch {
+	case t.VehicleID == uuid.Nil:
+		return NewTelemetryError(RejectInvalid, "vehicleId", "is required")
+	case !(t.Location.Lat >= -90 && t.Location.Lat <= 90):
+		return outOfRange("location.lat", "must be between -90 and 90")
+	case !(t.Location.Lng >= -180 && t.Location.Lng <= 180):
+		return outOfRange("location.lng", "must be between -180 and 180")
+	case !(t.Speed >= 0 && t.Speed <= 400):
+		return outOfRange("speed", "must be between 0 and 400")
+	case t.BatteryLevel < 0 || t.BatteryLevel > 100:
+		return outOfRange("batteryLevel", "must be between 0 and 100")
+	case t.FuelLevel != nil && (*t.FuelLevel < 0 || *t.FuelLevel > 100):
+		return outOfRange("fuelLevel", "must be between 0 and 100")
+	case !(t.EngineTemp >= -50 && t.EngineTemp <= 200):
+		return outOfRange("engineTemp", "must be between -50 and 200")
+	case t.EngineRPM < 0 || t.EngineRPM > 20000:
+		return outOfRange("engineRpm", "must be between 0 and 20000")
+	case !(t.Heading >= 0 && t.Heading <= 360):
+		return outOfRange("heading", "must be between 0 and 360")
+	case t.ChargingCurrent != nil && !(*t.ChargingCurrent >= 0 && *t.ChargingCurrent <= 1000):
+		return outOfRange("chargingCurrent", "must be between 0 and 1000")
+	}
+	return nil
+}
+
+// Validate checks that a driver has a name and a rating within the 0 to 5
+// stars accepted by the drivers table
+func (d *Driver) Validate() error {
+	switch {
+	case d.Name == "":
+		return &ValidationError{Field: "name", Message: "is required"}
+	case d.Email != "" && !strings.Contains(d.Email, "@"):
+		return &ValidationError{Field: "email", Message: "must be a valid email address"}
+	case d.Rating < 0 || d.Rating > 5:
+		return &ValidationError{Field: "rating", Message: "must be between 0 and 5"}
+	}
+	return nil
+}
+
+// Validate checks that a geofence has a name and a usable shape
+func (g *Geofence) Validate() error {
+	if g.Name == "" {
+		return &ValidationError{Field: "name", Message: "is required"}
+	}
+
+	switch g.Type {
+	case GeofenceTypeCircle:
+		if g.Center == nil {
+			return &ValidationError{Field: "center", Message: "is required for circle geofences"}
+		}
+		if g.Radius == nil || *g.Radius <= 0 {
+			return &ValidationError{Field: "radius", Message: "must be greater than 0"}
+		}
+	case GeofenceTypePolygon:
+		if len(g.Polygon) < 3 {
+			return &ValidationError{Field: "polygon", Message: "must have at least 3 points"}
+		}
+	default:
+		return &ValidationError{Field: "type", Message: "must be circle or polygon"}
+	}
+	return nil
+}
+
+// Validate checks that a user has an email, a name and a known role
+func (u *User) Validate() error {
+	switch {
+	case !strings.Contains(u.Email, "@"):
+		return &ValidationError{Field: "email", Message: "must be a valid email address"}
+	case u.Name == "":
+		return &ValidationError{Field: "name", Message: "is required"}
+	}
+
+	switch u.Role {
+	case UserRoleAdmin, UserRoleDispatcher, UserRoleViewer, UserRoleDevice:
+	default:
+		return &ValidationError{Field: "role", Message: "must be admin, dispatcher, viewer or device"}
+	}
+	return nil
+}
+
+// Validate checks that a maintenance record has a type, a date, a known
+// status and no negative amounts
+func (m *MaintenanceRecord) Validate() error {
+	switch {
+	case m.Type == "":
+		return &ValidationError{Field: "type", Message: "is required"}
+	case m.Date.IsZero():
+		return &ValidationError{Field: "date", Message: "is required"}
+	case m.Cost < 0:
+		return &ValidationError{Field: "cost", Message: "must not be negative"}
+	case m.Odometer != nil && *m.Odometer < 0:
+		return &ValidationError{Field: "odometer", Message: "must not be negative"}
+	}
+
+	switch m.Status {
+	case MaintenanceStatusScheduled, MaintenanceStatusInProgress, MaintenanceStatusCompleted, MaintenanceStatusCancelled:
+	default:
+		return &ValidationError{Field: "status", Message: "must be scheduled, in_progress, completed or cancelled"}
+	}
+	return nil
+}
+
+// Validate checks that a maintenance plan has a type and at least one
+// positive interval
+func (p *MaintenancePlan) Validate() error {
+	switch {
+	case p.Type == "":
+		return &ValidationError{Field: "type", Message: "is required"}
+	case p.IntervalKm == nil && p.IntervalDays == nil:
+		return &ValidationError{Field: "intervalKm", Message: "is required unless intervalDays is set"}
+	case p.IntervalKm != nil && *p.IntervalKm <= 0:
+		return &ValidationError{Field: "intervalKm", Message: "must be greater than 0"}
+	case p.IntervalDays != nil && *p.IntervalDays <= 0:
+		return &ValidationError{Field: "intervalDays", Message: "must be greater than 0"}
+	case p.LastServiceOdometer < 0:
+		return &ValidationError{Field: "lastServiceOdometer", Message: "must not be negative"}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// VehicleStatuses lists every vehicle status
+var VehicleStatuses = []VehicleStatus{
+	VehicleStatusIdle,
+	VehicleStatusActive,
+	VehicleStatusCharging,
+	VehicleStatusMaintenance,
+}
+
+// vehicleTransitions lists the statuses a vehicle may move to from each
+// status. A vehicle stops before it charges or drives off, and leaves
+// maintenance only as idle.
+var vehicleTransitions = map[VehicleStatus][]VehicleStatus{
+	VehicleStatusIdle:        {VehicleStatusActive, VehicleStatusCharging, VehicleStatusMaintenance},
+	VehicleStatusActive:      {VehicleStatusIdle, VehicleStatusMaintenance},
+	VehicleStatusCharging:    {VehicleStatusIdle, VehicleStatusMaintenance},
+	VehicleStatusMaintenance: {VehicleStatusIdle},
+}
+
+// Valid reports whether s is a known status
+func (s VehicleStatus) Valid() bool {
+	_, ok := vehicleTransitions[s]
+	return ok
+}
+
+// Validate returns a *ValidationError naming the known statuses when s is
+// not one of them
+func (s VehicleStatus) Validate() error {
+	if s.Valid() {
+		return nil
+	}
+	valid := make([]string, len(VehicleStatuses))
+	for i, status := range VehicleStatuses {
+		valid[i] = string(status)
+	}
+	return &ValidationError{Field: "status", Message: "must be one of " + strings.Join(valid, ", ")}
+}
+
+// CanTransitionTo reports whether a vehicle may move from s to next
+func (s VehicleStatus) CanTransitionTo(next VehicleStatus) bool {
+	for _, allowed := range vehicleTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo checks a move from s to next. It returns a *ValidationError
+// for an unknown status and wraps ErrConflict for a move the state machine
+// does not allow; keeping the same status is always allowed.
+func (s VehicleStatus) TransitionTo(next VehicleStatus) error {
+	if err := next.Validate(); err != nil {
+		return err
+	}
+	if s == next || s.CanTransitionTo(next) {
+		return nil
+	}
+	return fmt.Errorf("%w: vehicle cannot move from %s to %s", ErrConflict, s, next)
+}
+
+// PathTo returns the statuses a vehicle passes through to get from s to
+// next, going through idle when there is no direct transition. It is empty
+// when s is next or next can't be reached.
+func (s VehicleStatus) PathTo(next VehicleStatus) []VehicleStatus {
+	switch {
+	case s == next:
+		return nil
+	case s.CanTransitionTo(next):
+		return []VehicleStatus{next}
+	case s.CanTransitionTo(VehicleStatusIdle) && VehicleStatusIdle.CanTransitionTo(next):
+		return []VehicleStatus{VehicleStatusIdle, next}
+	}
+	return nil
+}
+
+// InferStatus infers a vehicle's status from its latest telemetry record and
+// how long it has been standing still, returning the status with the reason
+// for it. Vehicles in maintenance keep their status until it is released by
+// hand, and so do vehicles the record says nothing new about.
+func InferStatus(current VehicleStatus, t *Telemetry, stationary, idleTimeout time.Duration) (VehicleStatus, string) {
+	switch {
+	case current == VehicleStatusMaintenance:
+		return current, ""
+	case t.ChargingCurrent != nil && *t.ChargingCurrent > 0:
+		return VehicleStatusCharging, fmt.Sprintf("charging at %.1f A", *t.ChargingCurrent)
+	case t.Speed > 0:
+		return VehicleStatusActive, fmt.Sprintf("moving at %.1f km/h", t.Speed)
+	case current == VehicleStatusCharging && t.ChargingCurrent != nil:
+		return VehicleStatusIdle, "charger disconnected"
+	case current == VehicleStatusActive && t.Ignition != nil && !*t.Ignition:
+		return VehicleStatusIdle, "ignition off"
+	case current == VehicleStatusActive && stationary >= idleTimeout:
+		return VehicleStatusIdle, "stationary for " + stationary.Round(time.Second).String()
+	}
+	return current, ""
+}
@@ -0,0 +1,154 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestVehicleStatusTransitionTo(t *testing.T) {
+	tests := []struct {
+		from, to VehicleStatus
+		wantErr  error // nil, ErrConflict or a *ValidationError
+	}{
+		{VehicleStatusIdle, VehicleStatusActive, nil},
+		{VehicleStatusIdle, VehicleStatusCharging, nil},
+		{VehicleStatusIdle, VehicleStatusMaintenance, nil},
+		{VehicleStatusActive, VehicleStatusIdle, nil},
+		{VehicleStatusActive, VehicleStatusMaintenance, nil},
+		{VehicleStatusCharging, VehicleStatusIdle, nil},
+		{VehicleStatusMaintenance, VehicleStatusIdle, nil},
+		{VehicleStatusActive, VehicleStatusActive, nil},
+		{VehicleStatusMaintenance, VehicleStatusMaintenance, nil},
+		{VehicleStatusActive, VehicleStatusCharging, ErrConflict},
+		{VehicleStatusCharging, VehicleStatusActive, ErrConflict},
+		{VehicleStatusMaintenance, VehicleStatusActive, ErrConflict},
+		{VehicleStatusMaintenance, VehicleStatusCharging, ErrConflict},
+		{VehicleStatusIdle, VehicleStatus("parked"), &ValidationError{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			err := tt.from.TransitionTo(tt.to)
+			var validationErr *ValidationError
+			switch {
+			case tt.wantErr == nil:
+				if err != nil {
+					t.Errorf("TransitionTo() = %v, want nil", err)
+				}
+			case errors.As(tt.wantErr, &validationErr):
+				if !errors.As(err, &validationErr) || validationErr.Field != "status" {
+					t.Errorf("TransitionTo() = %v, want a status *ValidationError", err)
+				}
+			default:
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("TransitionTo() = %v, want %v", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestVehicleStatusPathTo(t *testing.T) {
+	tests := []struct {
+		from, to VehicleStatus
+		want     []VehicleStatus
+	}{
+		{VehicleStatusIdle, VehicleStatusIdle, nil},
+		{VehicleStatusIdle, VehicleStatusActive, []VehicleStatus{VehicleStatusActive}},
+		{VehicleStatusActive, VehicleStatusCharging, []VehicleStatus{VehicleStatusIdle, VehicleStatusCharging}},
+		{VehicleStatusCharging, VehicleStatusActive, []VehicleStatus{VehicleStatusIdle, VehicleStatusActive}},
+		{VehicleStatusMaintenance, VehicleStatusIdle, []VehicleStatus{VehicleStatusIdle}},
+		{VehicleStatusMaintenance, VehicleStatusActive, []VehicleStatus{VehicleStatusIdle, VehicleStatusActive}},
+		{VehicleStatusIdle, VehicleStatus("parked"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			if got := tt.from.PathTo(tt.to); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PathTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInferStatus(t *testing.T) {
+	amps := func(a float32) *float32 { return &a }
+	ignition := func(on bool) *bool { return &on }
+	const idleTimeout = 5 * time.Minute
+
+	tests := []struct {
+		name       string
+		current    VehicleStatus
+		telemetry  Telemetry
+		stationary time.Duration
+		want       VehicleStatus
+		reason     string
+	}{
+		{
+			name:      "moving",
+			current:   VehicleStatusIdle,
+			telemetry: Telemetry{Speed: 42},
+			want:      VehicleStatusActive,
+			reason:    "moving at 42.0 km/h",
+		},
+		{
+			name:      "charging",
+			current:   VehicleStatusIdle,
+			telemetry: Telemetry{ChargingCurrent: amps(32)},
+			want:      VehicleStatusCharging,
+			reason:    "charging at 32.0 A",
+		},
+		{
+			name:      "charger disconnected",
+			current:   VehicleStatusCharging,
+			telemetry: Telemetry{ChargingCurrent: amps(0)},
+			want:      VehicleStatusIdle,
+			reason:    "charger disconnected",
+		},
+		{
+			name:      "charging without a current reading",
+			current:   VehicleStatusCharging,
+			telemetry: Telemetry{},
+			want:      VehicleStatusCharging,
+		},
+		{
+			name:      "ignition off",
+			current:   VehicleStatusActive,
+			telemetry: Telemetry{Ignition: ignition(false)},
+			want:      VehicleStatusIdle,
+			reason:    "ignition off",
+		},
+		{
+			name:       "stationary for the idle timeout",
+			current:    VehicleStatusActive,
+			telemetry:  Telemetry{},
+			stationary: idleTimeout,
+			want:       VehicleStatusIdle,
+			reason:     "stationary for 5m0s",
+		},
+		{
+			name:       "briefly stationary",
+			current:    VehicleStatusActive,
+			telemetry:  Telemetry{Ignition: ignition(true)},
+			stationary: time.Minute,
+			want:       VehicleStatusActive,
+		},
+		{
+			name:      "maintenance kept while moving",
+			current:   VehicleStatusMaintenance,
+			telemetry: Telemetry{Speed: 42, ChargingCurrent: amps(32)},
+			want:      VehicleStatusMaintenance,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, reason := InferStatus(tt.current, &tt.telemetry, tt.stationary, idleTimeout)
+			if status != tt.want || reason != tt.reason {
+				t.Errorf("InferStatus() = %q, %q, want %q, %q", status, reason, tt.want, tt.reason)
+			}
+		})
+	}
+}
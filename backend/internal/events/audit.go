@@ -0,0 +1,41 @@
+package events
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// AuditedEventTypes are the state changes recorded by the audit log.
+// High-frequency telemetry is deliberately left out.
+var AuditedEventTypes = []domain.EventType{
+	domain.EventTypeVehicleStatusChange,
+	domain.EventTypeAlertCreated,
+	domain.EventTypeAlertAcknowledged,
+	domain.EventTypeAlertResolved,
+	domain.EventTypeGeofenceEnter,
+	domain.EventTypeGeofenceExit,
+	domain.EventTypeSpeedExceeded,
+	domain.EventTypeBatteryLow,
+	domain.EventTypeMaintenanceDue,
+	domain.EventTypeTripCompleted,
+	domain.EventTypeDriverAssigned,
+	domain.EventTypeDriverUnassigned,
+}
+
+// NewAuditLog returns a handler that writes each event to the structured log
+// so it can be retained and searched in Loki
+func NewAuditLog(logger zerolog.Logger) Handler {
+	logger = logger.With().Str("component", "audit").Logger()
+
+	return func(ctx context.Context, event *domain.Event) {
+		logger.Info().
+			Str("eventId", event.ID.String()).
+			Str("eventType", string(event.Type)).
+			Str("source", event.Source).
+			Time("occurredAt", event.Timestamp).
+			RawJSON("data", event.Data).
+			Msg("Domain event")
+	}
+}
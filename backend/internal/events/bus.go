@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// Handler consumes events delivered to a subscription. Handlers of one
+// subscription run sequentially, in publish order.
+type Handler func(ctx context.Context, event *domain.Event)
+
+// Bus is an in-process publish/subscribe bus for domain events. Every
+// subscriber has its own bounded queue and goroutine, so a slow consumer
+// never blocks producers or other consumers; when a queue is full the event
+// is dropped for that subscriber only.
+type Bus struct {
+	byType map[domain.EventType][]*subscription
+	all    []*subscription
+	ctx    context.Context
+	cancel context.CancelFunc
+	logger zerolog.Logger
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+}
+
+type subscription struct {
+	name    string
+	queue   chan *domain.Event
+	handler Handler
+	dropped atomic.Uint64
+}
+
+// NewBus creates a new event bus
+func NewBus(logger zerolog.Logger) *Bus {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Bus{
+		byType: make(map[domain.EventType][]*subscription),
+		ctx:    ctx,
+		cancel: cancel,
+		logger: logger,
+	}
+}
+
+// Subscribe registers handler under name for the given event types, or for
+// every event when no types are given. queueSize bounds the number of events
+// waiting for the handler.
+func (b *Bus) Subscribe(name string, handler Handler, queueSize int, types ...domain.EventType) {
+	sub := &subscription{
+		name:    name,
+		queue:   make(chan *domain.Event, queueSize),
+		handler: handler,
+	}
+
+	b.mu.Lock()
+	if len(types) == 0 {
+		b.all = append(b.all, sub)
+	}
+	for _, t := range types {
+		b.byType[t] = append(b.byType[t], sub)
+	}
+	b.mu.Unlock()
+
+	b.wg.Add(1)
+	go b.consume(sub)
+}
+
+// Publish delivers event to every matching subscriber without blocking
+func (b *Bus) Publish(ctx context.Context, event *domain.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, sub := range b.byType[event.Type] {
+		b.enqueue(sub, event)
+	}
+	for _, sub := range b.all {
+		b.enqueue(sub, event)
+	}
+}
+
+// Run blocks until ctx is cancelled, then stops all subscribers after they
+// have drained their queues
+func (b *Bus) Run(ctx context.Context) {
+	<-ctx.Done()
+	b.logger.Info().Msg("Event bus shutting down")
+
+	b.mu.Lock()
+	closed := make(map[*subscription]bool)
+	for _, subs := range b.byType {
+		for _, sub := range subs {
+			if !closed[sub] {
+				close(sub.queue)
+				closed[sub] = true
+			}
+		}
+	}
+	for _, sub := range b.all {
+		close(sub.queue)
+	}
+	b.byType = make(map[domain.EventType][]*subscription)
+	b.all = nil
+	b.mu.Unlock()
+
+	b.wg.Wait()
+	b.cancel()
+}
+
+func (b *Bus) enqueue(sub *subscription, event *domain.Event) {
+	select {
+	case sub.queue <- event:
+	default:
+		dropped := sub.dropped.Add(1)
+		// Log the first drop and then every 100th to avoid flooding
+		if dropped%100 == 1 {
+			b.logger.Warn().
+				Str("subscriber", sub.name).
+				Str("eventType", string(event.Type)).
+				Uint64("dropped", dropped).
+				Msg("Event queue full, dropping event")
+		}
+	}
+}
+
+func (b *Bus) consume(sub *subscription) {
+	defer b.wg.Done()
+
+	for event := range sub.queue {
+		b.handle(sub, event)
+	}
+}
+
+// handle runs a handler, isolating the bus from handler panics
+func (b *Bus) handle(sub *subscription, event *domain.Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Error().
+				Interface("panic", r).
+				Str("subscriber", sub.name).
+				Str("eventType", string(event.Type)).
+				Msg("Event handler panicked")
+		}
+	}()
+
+	sub.handler(b.ctx, event)
+}
@@ -0,0 +1,115 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// RedisRelay shares domain events between API replicas through a Redis
+// stream. Every replica appends the events published on its local bus and
+// reads the events appended by the others, so consumers such as the
+// WebSocket hub see the whole fleet whichever replica ingested the data.
+// Only events are shared: state a consumer derives from telemetry, such as
+// alert hysteresis, open trips or geofence membership, stays with the
+// replica that ingested the records.
+type RedisRelay struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+	origin string
+	logger zerolog.Logger
+}
+
+// NewRedisRelay creates a relay on stream, trimmed to roughly maxLen entries
+func NewRedisRelay(client *redis.Client, stream string, maxLen int64, logger zerolog.Logger) *RedisRelay {
+	return &RedisRelay{
+		client: client,
+		stream: stream,
+		maxLen: maxLen,
+		origin: uuid.NewString(),
+		logger: logger,
+	}
+}
+
+// Forward appends a locally published event to the stream. It is meant to
+// be subscribed on the local bus.
+func (r *RedisRelay) Forward(ctx context.Context, event *domain.Event) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		r.logger.Error().Err(err).Str("eventType", string(event.Type)).Msg("Failed to encode event for relay")
+		return
+	}
+
+	err = r.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: r.stream,
+		MaxLen: r.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"origin": r.origin,
+			"event":  payload,
+		},
+	}).Err()
+	if err != nil {
+		r.logger.Error().Err(err).Str("eventType", string(event.Type)).Msg("Failed to relay event")
+	}
+}
+
+// Run delivers events appended by other replicas to handler until ctx is
+// cancelled. Reading starts at the end of the stream; after a Redis error it
+// resumes from the last delivered entry so short outages lose nothing.
+func (r *RedisRelay) Run(ctx context.Context, handler Handler) {
+	lastID := "$"
+
+	for {
+		streams, err := r.client.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{r.stream, lastID},
+			Count:   100,
+			Block:   5 * time.Second,
+		}).Result()
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, redis.Nil) {
+				continue // block timed out with nothing new
+			}
+
+			r.logger.Warn().Err(err).Str("stream", r.stream).Msg("Failed to read relayed events, retrying")
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				lastID = msg.ID
+				r.deliver(ctx, msg, handler)
+			}
+		}
+	}
+}
+
+func (r *RedisRelay) deliver(ctx context.Context, msg redis.XMessage, handler Handler) {
+	if origin, _ := msg.Values["origin"].(string); origin == r.origin {
+		return // already delivered by the local bus
+	}
+
+	payload, _ := msg.Values["event"].(string)
+	var event domain.Event
+	if err := json.Unmarshal([]byte(payload), &event); err != nil {
+		r.logger.Error().Err(err).Str("id", msg.ID).Msg("Failed to decode relayed event")
+		return
+	}
+
+	handler(ctx, &event)
+}
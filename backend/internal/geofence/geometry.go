@@ -0,0 +1,37 @@
+package geofence
+
+import "github.com/sid-romero/fleetpulse/internal/domain"
+
+// Contains reports whether loc lies inside the geofence boundary
+func Contains(g *domain.Geofence, loc domain.Location) bool {
+	switch g.Type {
+	case domain.GeofenceTypeCircle:
+		if g.Center == nil || g.Radius == nil {
+			return false
+		}
+		return domain.DistanceMeters(*g.Center, loc) <= *g.Radius
+	case domain.GeofenceTypePolygon:
+		return inPolygon(g.Polygon, loc)
+	}
+	return false
+}
+
+// inPolygon uses ray casting on the lat/lng plane, which is accurate enough
+// for city-scale polygons that don't cross the antimeridian
+func inPolygon(polygon []domain.Location, loc domain.Location) bool {
+	if len(polygon) < 3 {
+		return false
+	}
+
+	inside := false
+	j := len(polygon) - 1
+	for i := range polygon {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > loc.Lat) != (b.Lat > loc.Lat) &&
+			loc.Lng < (b.Lng-a.Lng)*(loc.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+		j = i
+	}
+	return inside
+}
@@ -0,0 +1,50 @@
+package geofence
+
+import (
+	"testing"
+
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+func TestContains(t *testing.T) {
+	radius := 1000.0
+	circle := domain.Geofence{
+		Type:   domain.GeofenceTypeCircle,
+		Center: &domain.Location{Lat: 40, Lng: -3.7},
+		Radius: &radius,
+	}
+	// an L-shaped depot, so the notch tests a concave corner
+	polygon := domain.Geofence{
+		Type: domain.GeofenceTypePolygon,
+		Polygon: []domain.Location{
+			{Lat: 40, Lng: -3.7}, {Lat: 40, Lng: -3.68}, {Lat: 40.01, Lng: -3.68},
+			{Lat: 40.01, Lng: -3.69}, {Lat: 40.02, Lng: -3.69}, {Lat: 40.02, Lng: -3.7},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		geofence domain.Geofence
+		loc      domain.Location
+		want     bool
+	}{
+		{"circle center", circle, domain.Location{Lat: 40, Lng: -3.7}, true},
+		{"within the radius", circle, domain.Location{Lat: 40.008, Lng: -3.7}, true},
+		{"beyond the radius", circle, domain.Location{Lat: 40.01, Lng: -3.7}, false},
+		{"circle without a radius", domain.Geofence{Type: domain.GeofenceTypeCircle, Center: circle.Center}, domain.Location{Lat: 40, Lng: -3.7}, false},
+		{"inside the polygon", polygon, domain.Location{Lat: 40.005, Lng: -3.685}, true},
+		{"inside the polygon arm", polygon, domain.Location{Lat: 40.015, Lng: -3.695}, true},
+		{"in the polygon notch", polygon, domain.Location{Lat: 40.015, Lng: -3.685}, false},
+		{"outside the polygon", polygon, domain.Location{Lat: 39.99, Lng: -3.69}, false},
+		{"degenerate polygon", domain.Geofence{Type: domain.GeofenceTypePolygon, Polygon: polygon.Polygon[:2]}, domain.Location{Lat: 40, Lng: -3.69}, false},
+		{"unknown type", domain.Geofence{Type: "corridor", Center: circle.Center, Radius: &radius}, domain.Location{Lat: 40, Lng: -3.7}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Contains(&tt.geofence, tt.loc); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package geofence
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// Transition is a vehicle crossing a geofence boundary
+type Transition struct {
+	Geofence domain.Geofence
+	Entered  bool // false means exited
+}
+
+// Tracker remembers which geofences each vehicle is inside so that only
+// boundary crossings are reported
+type Tracker struct {
+	inside map[uuid.UUID]map[uuid.UUID]bool // vehicle -> geofence -> inside
+	mu     sync.Mutex
+}
+
+// NewTracker creates an empty Tracker
+func NewTracker() *Tracker {
+	return &Tracker{inside: make(map[uuid.UUID]map[uuid.UUID]bool)}
+}
+
+// Update evaluates a vehicle position against the geofences and returns the
+// transitions since the previous position. The first position seen for a
+// vehicle/geofence pair only records membership, so restarts and newly
+// created geofences don't report vehicles already inside as entering.
+func (t *Tracker) Update(vehicleID uuid.UUID, loc domain.Location, geofences []domain.Geofence) []Transition {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	membership, ok := t.inside[vehicleID]
+	if !ok {
+		membership = make(map[uuid.UUID]bool)
+		t.inside[vehicleID] = membership
+	}
+
+	var transitions []Transition
+	for i := range geofences {
+		g := &geofences[i]
+		now := Contains(g, loc)
+		was, known := membership[g.ID]
+		membership[g.ID] = now
+
+		if known && now != was {
+			transitions = append(transitions, Transition{Geofence: *g, Entered: now})
+		}
+	}
+	return transitions
+}
+
+// Forget drops all membership for a geofence, e.g. after it was reshaped,
+// deactivated or deleted
+func (t *Tracker) Forget(geofenceID uuid.UUID) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, membership := range t.inside {
+		delete(membership, geofenceID)
+	}
+}
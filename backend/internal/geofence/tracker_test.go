@@ -0,0 +1,89 @@
+package geofence
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+func TestTrackerUpdate(t *testing.T) {
+	radius := 1000.0
+	depot := domain.Geofence{
+		ID:     uuid.New(),
+		Type:   domain.GeofenceTypeCircle,
+		Center: &domain.Location{Lat: 40, Lng: -3.7},
+		Radius: &radius,
+	}
+	inside := domain.Location{Lat: 40, Lng: -3.7}
+	outside := domain.Location{Lat: 40.1, Lng: -3.7}
+
+	// transitions holds the crossing expected at each position: 0 for none,
+	// 1 for entering and -1 for exiting
+	tests := []struct {
+		name        string
+		positions   []domain.Location
+		transitions []int
+	}{
+		{"first position inside", []domain.Location{inside}, []int{0}},
+		{"first position outside", []domain.Location{outside}, []int{0}},
+		{"entering", []domain.Location{outside, inside}, []int{0, 1}},
+		{"exiting", []domain.Location{inside, outside}, []int{0, -1}},
+		{"staying inside", []domain.Location{inside, inside, inside}, []int{0, 0, 0}},
+		{"round trip", []domain.Location{inside, outside, outside, inside}, []int{0, -1, 0, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := NewTracker()
+			vehicleID := uuid.New()
+
+			for i, loc := range tt.positions {
+				transitions := tracker.Update(vehicleID, loc, []domain.Geofence{depot})
+				if got := crossing(t, transitions); got != tt.transitions[i] {
+					t.Errorf("position %d: transition %d, want %d", i, got, tt.transitions[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTrackerForget(t *testing.T) {
+	radius := 1000.0
+	depot := domain.Geofence{
+		ID:     uuid.New(),
+		Type:   domain.GeofenceTypeCircle,
+		Center: &domain.Location{Lat: 40, Lng: -3.7},
+		Radius: &radius,
+	}
+	vehicleID := uuid.New()
+	tracker := NewTracker()
+	tracker.Update(vehicleID, domain.Location{Lat: 40, Lng: -3.7}, []domain.Geofence{depot})
+
+	// Moving the depot away would read as an exit unless the tracker forgets
+	// what it knew about the old shape
+	moved := depot
+	moved.Center = &domain.Location{Lat: 41, Lng: -3.7}
+	tracker.Forget(depot.ID)
+	if transitions := tracker.Update(vehicleID, domain.Location{Lat: 40, Lng: -3.7}, []domain.Geofence{moved}); len(transitions) != 0 {
+		t.Errorf("transitions after Forget = %+v, want none", transitions)
+	}
+	if transitions := tracker.Update(vehicleID, domain.Location{Lat: 41, Lng: -3.7}, []domain.Geofence{moved}); crossing(t, transitions) != 1 {
+		t.Errorf("transitions = %+v, want an entry", transitions)
+	}
+}
+
+// crossing returns 0 for no transitions, 1 for a single entry and -1 for a
+// single exit
+func crossing(t *testing.T, transitions []Transition) int {
+	t.Helper()
+	switch {
+	case len(transitions) == 0:
+		return 0
+	case len(transitions) > 1:
+		t.Fatalf("got %d transitions, want at most 1", len(transitions))
+	case transitions[0].Entered:
+		return 1
+	}
+	return -1
+}
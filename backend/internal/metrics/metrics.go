@@ -0,0 +1,28 @@
+// Package metrics holds the process metrics published on /metrics in the
+// expvar JSON format
+package metrics
+
+import (
+	"expvar"
+	"net/http"
+)
+
+var (
+	// RetentionRuns counts pruning passes of the retention job
+	RetentionRuns = expvar.NewInt("retention_runs_total")
+	// RetentionFailures counts pruning passes that stopped on an error
+	RetentionFailures = expvar.NewInt("retention_failures_total")
+	// RetentionRowsDeleted counts pruned telemetry rows per tier
+	RetentionRowsDeleted = expvar.NewMap("retention_rows_deleted_total")
+	// MQTTMessages counts MQTT telemetry messages per outcome: accepted,
+	// rejected, failed or ignored
+	MQTTMessages = expvar.NewMap("mqtt_messages_total")
+	// TelemetryRejected counts telemetry records rejected by ingestion per
+	// domain.RejectReason
+	TelemetryRejected = expvar.NewMap("telemetry_rejected_total")
+)
+
+// Handler serves every published metric
+func Handler() http.Handler {
+	return expvar.Handler()
+}
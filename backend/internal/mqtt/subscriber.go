@@ -0,0 +1,224 @@
+// Package mqtt ingests telemetry that devices publish to an MQTT broker.
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	paho "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/metrics"
+)
+
+// maxBatchSize matches the record limit of HTTP batch ingestion
+const maxBatchSize = 1000
+
+// subscribeTimeout bounds how long a subscription may take after connecting
+const subscribeTimeout = 10 * time.Second
+
+// Ingester is the telemetry pipeline messages are fed into
+type Ingester interface {
+	Ingest(ctx context.Context, telemetry *domain.Telemetry) error
+	BatchIngest(ctx context.Context, telemetry []domain.Telemetry) error
+}
+
+// Subscriber feeds telemetry published to <prefix>/<vehicleId>/telemetry
+// and <prefix>/<vehicleId>/telemetry/batch into an Ingester. A record's
+// vehicle comes from its topic; records naming another vehicle are rejected.
+//
+// The session is persistent and messages are acknowledged only once
+// handled, so QoS 1 and 2 messages that could not be stored are redelivered
+// by the broker after a reconnect. Malformed or rejected messages are
+// acknowledged and dropped, since redelivering them would not help.
+type Subscriber struct {
+	cfg      config.MQTTConfig
+	ingester Ingester
+	logger   zerolog.Logger
+}
+
+// NewSubscriber creates a Subscriber, failing when a QoS is out of range
+func NewSubscriber(cfg config.MQTTConfig, ingester Ingester, logger zerolog.Logger) (*Subscriber, error) {
+	if cfg.TelemetryQoS > 2 || cfg.BatchQoS > 2 {
+		return nil, fmt.Errorf("QoS must be 0, 1 or 2")
+	}
+	if cfg.TopicPrefix == "" {
+		return nil, fmt.Errorf("topic prefix is required")
+	}
+	return &Subscriber{
+		cfg:      cfg,
+		ingester: ingester,
+		logger:   logger.With().Str("component", "mqtt").Logger(),
+	}, nil
+}
+
+// Run connects to the broker and ingests messages until ctx is cancelled.
+// The connection is retried while the broker is unreachable.
+func (s *Subscriber) Run(ctx context.Context) {
+	opts := paho.NewClientOptions().
+		AddBroker(s.cfg.BrokerURL).
+		SetClientID(s.cfg.ClientID).
+		SetUsername(s.cfg.Username).
+		SetPassword(s.cfg.Password).
+		SetCleanSession(false).
+		SetAutoAckDisabled(true).
+		SetConnectRetry(true).
+		SetAutoReconnect(true).
+		SetOnConnectHandler(func(client paho.Client) { s.subscribe(ctx, client) }).
+		SetConnectionLostHandler(func(_ paho.Client, err error) {
+			s.logger.Warn().Err(err).Msg("Lost connection to MQTT broker")
+		})
+
+	client := paho.NewClient(opts)
+	token := client.Connect()
+	select {
+	case <-ctx.Done():
+	case <-token.Done():
+		if err := token.Error(); err != nil {
+			s.logger.Error().Err(err).Str("broker", s.cfg.BrokerURL).Msg("Failed to connect to MQTT broker")
+			return
+		}
+		<-ctx.Done()
+	}
+	client.Disconnect(250)
+}
+
+// subscribe (re)subscribes to the telemetry topics after each connect
+func (s *Subscriber) subscribe(ctx context.Context, client paho.Client) {
+	filters := map[string]byte{
+		s.filter("telemetry"):       s.cfg.TelemetryQoS,
+		s.filter("telemetry/batch"): s.cfg.BatchQoS,
+	}
+	token := client.SubscribeMultiple(filters, func(_ paho.Client, msg paho.Message) {
+		s.handle(ctx, msg)
+	})
+	if !token.WaitTimeout(subscribeTimeout) {
+		s.logger.Error().Str("broker", s.cfg.BrokerURL).Msg("Timed out subscribing to telemetry topics")
+		return
+	}
+	if err := token.Error(); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to subscribe to telemetry topics")
+		return
+	}
+	s.logger.Info().Str("broker", s.cfg.BrokerURL).Str("prefix", s.cfg.TopicPrefix).Msg("Subscribed to telemetry topics")
+}
+
+// filter returns the subscription filter of a per-vehicle topic suffix,
+// shared between replicas when a group is configured
+func (s *Subscriber) filter(suffix string) string {
+	filter := s.cfg.TopicPrefix + "/+/" + suffix
+	if s.cfg.SharedGroup != "" {
+		filter = "$share/" + s.cfg.SharedGroup + "/" + filter
+	}
+	return filter
+}
+
+// handle ingests one message and acknowledges it unless it failed in a way
+// a redelivery could fix
+func (s *Subscriber) handle(ctx context.Context, msg paho.Message) {
+	logger := s.logger.With().Str("topic", msg.Topic()).Uint8("qos", msg.Qos()).Logger()
+
+	// Retained messages would be ingested again on every reconnect
+	if msg.Retained() {
+		metrics.MQTTMessages.Add("ignored", 1)
+		logger.Debug().Msg("Ignored retained telemetry message")
+		msg.Ack()
+		return
+	}
+
+	err := s.ingest(ctx, msg.Topic(), msg.Payload())
+	switch {
+	case err == nil:
+		metrics.MQTTMessages.Add("accepted", 1)
+		msg.Ack()
+	case rejected(err):
+		metrics.MQTTMessages.Add("rejected", 1)
+		logger.Warn().Err(err).Msg("Rejected telemetry message")
+		msg.Ack()
+	default:
+		metrics.MQTTMessages.Add("failed", 1)
+		logger.Error().Err(err).Msg("Failed to ingest telemetry message")
+	}
+}
+
+// ingest decodes a message published to topic and feeds it to the ingester
+func (s *Subscriber) ingest(ctx context.Context, topic string, payload []byte) error {
+	vehicleID, batch, err := s.parseTopic(topic)
+	if err != nil {
+		return err
+	}
+
+	if !batch {
+		var telemetry domain.Telemetry
+		if err := json.Unmarshal(payload, &telemetry); err != nil {
+			return &domain.ValidationError{Field: "payload", Message: "must be a JSON telemetry record"}
+		}
+		if err := bindVehicle(&telemetry, vehicleID); err != nil {
+			return err
+		}
+		return s.ingester.Ingest(ctx, &telemetry)
+	}
+
+	var records []domain.Telemetry
+	if err := json.Unmarshal(payload, &records); err != nil {
+		return &domain.ValidationError{Field: "payload", Message: "must be a JSON array of telemetry records"}
+	}
+	if len(records) > maxBatchSize {
+		return &domain.ValidationError{Field: "payload", Message: fmt.Sprintf("must hold at most %d records", maxBatchSize)}
+	}
+	for i := range records {
+		if err := bindVehicle(&records[i], vehicleID); err != nil {
+			return fmt.Errorf("record %d: %w", i, err)
+		}
+	}
+	return s.ingester.BatchIngest(ctx, records)
+}
+
+// parseTopic extracts the vehicle ID of a telemetry topic and whether it
+// carries a batch
+func (s *Subscriber) parseTopic(topic string) (uuid.UUID, bool, error) {
+	rest, ok := strings.CutPrefix(topic, s.cfg.TopicPrefix+"/")
+	if !ok {
+		return uuid.Nil, false, &domain.ValidationError{Field: "topic", Message: "is not a telemetry topic"}
+	}
+
+	vehicle, suffix, _ := strings.Cut(rest, "/")
+	id, err := uuid.Parse(vehicle)
+	if err != nil {
+		return uuid.Nil, false, &domain.ValidationError{Field: "topic", Message: "must name a vehicle ID"}
+	}
+	switch suffix {
+	case "telemetry":
+		return id, false, nil
+	case "telemetry/batch":
+		return id, true, nil
+	}
+	return uuid.Nil, false, &domain.ValidationError{Field: "topic", Message: "is not a telemetry topic"}
+}
+
+// bindVehicle fills in a record's vehicle from its topic and rejects
+// records naming another vehicle
+func bindVehicle(telemetry *domain.Telemetry, vehicleID uuid.UUID) error {
+	if telemetry.VehicleID == uuid.Nil {
+		telemetry.VehicleID = vehicleID
+	}
+	if telemetry.VehicleID != vehicleID {
+		return fmt.Errorf("%w: record for vehicle %s published on the topic of %s", domain.ErrForbidden, telemetry.VehicleID, vehicleID)
+	}
+	return nil
+}
+
+// rejected reports whether err is the message's fault rather than a
+// storage failure
+func rejected(err error) bool {
+	var validationErr *domain.ValidationError
+	return errors.As(err, &validationErr) ||
+		errors.Is(err, domain.ErrNotFound) ||
+		errors.Is(err, domain.ErrForbidden)
+}
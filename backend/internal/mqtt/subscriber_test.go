@@ -0,0 +1,315 @@
+package mqtt
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	mochi "github.com/mochi-mqtt/server/v2"
+	"github.com/mochi-mqtt/server/v2/hooks/auth"
+	"github.com/mochi-mqtt/server/v2/listeners"
+	"github.com/mochi-mqtt/server/v2/packets"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+const (
+	testClientID = "fleetpulse-test"
+	// noAckWait is how long a message is given to be acknowledged when it
+	// should not be
+	noAckWait = 300 * time.Millisecond
+)
+
+// brokerHook reports the subscriber's subscriptions and acknowledgements
+type brokerHook struct {
+	mochi.HookBase
+	subscribed chan struct{}
+	acked      chan struct{}
+}
+
+func (h *brokerHook) ID() string { return "test" }
+
+func (h *brokerHook) Provides(b byte) bool {
+	return b == mochi.OnSubscribed || b == mochi.OnQosComplete
+}
+
+func (h *brokerHook) OnSubscribed(cl *mochi.Client, pk packets.Packet, reasonCodes []byte) {
+	if cl.ID == testClientID {
+		h.subscribed <- struct{}{}
+	}
+}
+
+func (h *brokerHook) OnQosComplete(cl *mochi.Client, pk packets.Packet) {
+	if cl.ID == testClientID {
+		h.acked <- struct{}{}
+	}
+}
+
+// startBroker runs an in-process broker and returns it with its URL
+func startBroker(t *testing.T) (*mochi.Server, *brokerHook, string) {
+	t.Helper()
+	server := mochi.New(&mochi.Options{
+		InlineClient: true,
+		Logger:       slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	hook := &brokerHook{subscribed: make(chan struct{}, 10), acked: make(chan struct{}, 10)}
+	if err := server.AddHook(new(auth.AllowHook), nil); err != nil {
+		t.Fatalf("add auth hook: %v", err)
+	}
+	if err := server.AddHook(hook, nil); err != nil {
+		t.Fatalf("add test hook: %v", err)
+	}
+	tcp := listeners.NewTCP(listeners.Config{ID: "tcp", Address: "127.0.0.1:0"})
+	if err := server.AddListener(tcp); err != nil {
+		t.Fatalf("add listener: %v", err)
+	}
+	if err := server.Serve(); err != nil {
+		t.Fatalf("serve: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return server, hook, "tcp://" + tcp.Address()
+}
+
+// startSubscriber runs a Subscriber until it has subscribed; the returned
+// function stops it
+func startSubscriber(t *testing.T, brokerURL string, hook *brokerHook, ingester Ingester) func() {
+	t.Helper()
+	subscriber, err := NewSubscriber(config.MQTTConfig{
+		BrokerURL:    brokerURL,
+		ClientID:     testClientID,
+		TopicPrefix:  "fleet",
+		TelemetryQoS: 1,
+		BatchQoS:     1,
+	}, ingester, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("NewSubscriber: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		subscriber.Run(ctx)
+		close(done)
+	}()
+	stop := func() {
+		cancel()
+		<-done
+	}
+	t.Cleanup(stop)
+
+	select {
+	case <-hook.subscribed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscriber did not subscribe")
+	}
+	return stop
+}
+
+// fakeIngester records what it is fed and fails with err
+type fakeIngester struct {
+	records chan domain.Telemetry
+	err     error
+}
+
+func newFakeIngester(err error) *fakeIngester {
+	return &fakeIngester{records: make(chan domain.Telemetry, 10), err: err}
+}
+
+func (f *fakeIngester) Ingest(ctx context.Context, telemetry *domain.Telemetry) error {
+	f.records <- *telemetry
+	return f.err
+}
+
+func (f *fakeIngester) BatchIngest(ctx context.Context, telemetry []domain.Telemetry) error {
+	for _, t := range telemetry {
+		f.records <- t
+	}
+	return f.err
+}
+
+// received returns the records fed to f within a short wait
+func (f *fakeIngester) received(want int) []domain.Telemetry {
+	var records []domain.Telemetry
+	timeout := time.After(noAckWait)
+	for {
+		select {
+		case record := <-f.records:
+			records = append(records, record)
+			if want > 0 && len(records) == want {
+				return records
+			}
+		case <-timeout:
+			return records
+		}
+	}
+}
+
+// waitAck reports whether the subscriber acknowledged a message within wait
+func waitAck(hook *brokerHook, wait time.Duration) bool {
+	select {
+	case <-hook.acked:
+		return true
+	case <-time.After(wait):
+		return false
+	}
+}
+
+func TestSubscriberHandlesMessages(t *testing.T) {
+	vehicleID := uuid.New()
+	other := uuid.New()
+	storageErr := errors.New("connection refused")
+
+	tests := []struct {
+		name     string
+		topic    string
+		payload  string
+		retained bool
+		err      error // returned by the ingester
+		ingested int   // records fed to the ingester, all bound to vehicleID
+		acked    bool
+	}{
+		{
+			name:     "record bound to its topic",
+			topic:    "fleet/" + vehicleID.String() + "/telemetry",
+			payload:  `{"speed": 42, "batteryLevel": 80}`,
+			ingested: 1,
+			acked:    true,
+		},
+		{
+			name:     "batch bound to its topic",
+			topic:    "fleet/" + vehicleID.String() + "/telemetry/batch",
+			payload:  `[{"speed": 42}, {"vehicleId": "` + vehicleID.String() + `", "speed": 43}]`,
+			ingested: 2,
+			acked:    true,
+		},
+		{
+			name:    "record naming another vehicle",
+			topic:   "fleet/" + vehicleID.String() + "/telemetry",
+			payload: `{"vehicleId": "` + other.String() + `", "speed": 42}`,
+			acked:   true,
+		},
+		{
+			name:    "batch with a record naming another vehicle",
+			topic:   "fleet/" + vehicleID.String() + "/telemetry/batch",
+			payload: `[{"speed": 42}, {"vehicleId": "` + other.String() + `"}]`,
+			acked:   true,
+		},
+		{
+			name:    "topic without a vehicle ID",
+			topic:   "fleet/truck-7/telemetry",
+			payload: `{"speed": 42}`,
+			acked:   true,
+		},
+		{
+			name:    "malformed payload",
+			topic:   "fleet/" + vehicleID.String() + "/telemetry",
+			payload: `{"speed":`,
+			acked:   true,
+		},
+		{
+			name:     "record rejected by the pipeline",
+			topic:    "fleet/" + vehicleID.String() + "/telemetry",
+			payload:  `{"speed": 900}`,
+			err:      &domain.ValidationError{Field: "speed", Message: "must be between 0 and 400"},
+			ingested: 1,
+			acked:    true,
+		},
+		{
+			name:     "unknown vehicle",
+			topic:    "fleet/" + vehicleID.String() + "/telemetry",
+			payload:  `{"speed": 42}`,
+			err:      domain.ErrNotFound,
+			ingested: 1,
+			acked:    true,
+		},
+		{
+			name:     "storage failure",
+			topic:    "fleet/" + vehicleID.String() + "/telemetry",
+			payload:  `{"speed": 42}`,
+			err:      storageErr,
+			ingested: 1,
+			acked:    false,
+		},
+		{
+			name:     "retained message",
+			topic:    "fleet/" + vehicleID.String() + "/telemetry",
+			payload:  `{"speed": 42}`,
+			retained: true,
+			acked:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, hook, brokerURL := startBroker(t)
+			ingester := newFakeIngester(tt.err)
+
+			// A retained message reaches the subscriber, flagged as
+			// retained, when it subscribes
+			if tt.retained {
+				if err := server.Publish(tt.topic, []byte(tt.payload), true, 1); err != nil {
+					t.Fatalf("publish: %v", err)
+				}
+				startSubscriber(t, brokerURL, hook, ingester)
+			} else {
+				startSubscriber(t, brokerURL, hook, ingester)
+				if err := server.Publish(tt.topic, []byte(tt.payload), false, 1); err != nil {
+					t.Fatalf("publish: %v", err)
+				}
+			}
+
+			wait := 5 * time.Second
+			if !tt.acked {
+				wait = noAckWait
+			}
+			if acked := waitAck(hook, wait); acked != tt.acked {
+				t.Errorf("acked = %v, want %v", acked, tt.acked)
+			}
+
+			records := ingester.received(tt.ingested)
+			if len(records) != tt.ingested {
+				t.Fatalf("ingested %d records, want %d", len(records), tt.ingested)
+			}
+			for i, record := range records {
+				if record.VehicleID != vehicleID {
+					t.Errorf("record %d vehicle = %s, want %s", i, record.VehicleID, vehicleID)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscriberRedeliversAfterStorageFailure(t *testing.T) {
+	server, hook, brokerURL := startBroker(t)
+	vehicleID := uuid.New()
+	topic := "fleet/" + vehicleID.String() + "/telemetry"
+
+	failing := newFakeIngester(errors.New("connection refused"))
+	stop := startSubscriber(t, brokerURL, hook, failing)
+	if err := server.Publish(topic, []byte(`{"speed": 42}`), false, 1); err != nil {
+		t.Fatalf("publish: %v", err)
+	}
+	if records := failing.received(1); len(records) != 1 {
+		t.Fatalf("ingested %d records, want 1", len(records))
+	}
+	if waitAck(hook, noAckWait) {
+		t.Fatal("message that failed to store was acknowledged")
+	}
+	stop()
+
+	// The persistent session holds the message for the next connection
+	working := newFakeIngester(nil)
+	startSubscriber(t, brokerURL, hook, working)
+	records := working.received(1)
+	if len(records) != 1 || records[0].VehicleID != vehicleID {
+		t.Fatalf("redelivered %+v, want the record of %s", records, vehicleID)
+	}
+	if !waitAck(hook, 5*time.Second) {
+		t.Error("redelivered message was not acknowledged")
+	}
+}
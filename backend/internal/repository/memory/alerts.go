@@ -0,0 +1,87 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// AlertRepository is an in-memory repository.AlertRepository
+type AlertRepository struct {
+	alerts map[uuid.UUID]domain.Alert
+	mu     sync.RWMutex
+}
+
+// NewAlertRepository creates an AlertRepository pre-populated with seed
+func NewAlertRepository(seed ...domain.Alert) *AlertRepository {
+	r := &AlertRepository{alerts: make(map[uuid.UUID]domain.Alert, len(seed))}
+	for _, a := range seed {
+		r.alerts[a.ID] = a
+	}
+	return r
+}
+
+func (r *AlertRepository) List(ctx context.Context, filter repository.AlertFilter) ([]domain.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	alerts := []domain.Alert{}
+	for _, a := range r.alerts {
+		if filter.Status != nil && a.Status != *filter.Status {
+			continue
+		}
+		if filter.Severity != nil && a.Severity != *filter.Severity {
+			continue
+		}
+		if filter.VehicleID != nil && (a.VehicleID == nil || *a.VehicleID != *filter.VehicleID) {
+			continue
+		}
+		alerts = append(alerts, a)
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].CreatedAt.After(alerts[j].CreatedAt) })
+
+	if filter.Offset > 0 {
+		if filter.Offset >= len(alerts) {
+			return []domain.Alert{}, nil
+		}
+		alerts = alerts[filter.Offset:]
+	}
+	if filter.Limit > 0 && filter.Limit < len(alerts) {
+		alerts = alerts[:filter.Limit]
+	}
+	return alerts, nil
+}
+
+func (r *AlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Alert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	a, ok := r.alerts[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &a, nil
+}
+
+func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.alerts[alert.ID] = *alert
+	return nil
+}
+
+func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.alerts[alert.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.alerts[alert.ID] = *alert
+	return nil
+}
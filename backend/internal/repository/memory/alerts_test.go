@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+func TestAlertRepositoryList(t *testing.T) {
+	vehicleID := uuid.New()
+	start := time.Now().Add(-time.Hour)
+	// alert returns the ith alert, created i minutes into the test
+	alert := func(i int, status domain.AlertStatus, severity domain.AlertSeverity, vehicleID *uuid.UUID) domain.Alert {
+		return domain.Alert{
+			ID:        uuid.New(),
+			VehicleID: vehicleID,
+			Type:      "test",
+			Severity:  severity,
+			Status:    status,
+			Message:   string(rune('a' + i)),
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		}
+	}
+	repo := NewAlertRepository(
+		alert(0, domain.AlertStatusActive, domain.AlertSeverityWarning, &vehicleID),
+		alert(1, domain.AlertStatusActive, domain.AlertSeverityCritical, nil),
+		alert(2, domain.AlertStatusResolved, domain.AlertSeverityWarning, &vehicleID),
+		alert(3, domain.AlertStatusActive, domain.AlertSeverityCritical, &vehicleID),
+	)
+
+	active := domain.AlertStatusActive
+	critical := domain.AlertSeverityCritical
+	other := uuid.New()
+
+	tests := []struct {
+		name   string
+		filter repository.AlertFilter
+		want   string // alert messages, newest first
+	}{
+		{name: "all", want: "dcba"},
+		{name: "by status", filter: repository.AlertFilter{Status: &active}, want: "dba"},
+		{name: "by severity", filter: repository.AlertFilter{Severity: &critical}, want: "db"},
+		{name: "by vehicle", filter: repository.AlertFilter{VehicleID: &vehicleID}, want: "dca"},
+		{name: "by another vehicle", filter: repository.AlertFilter{VehicleID: &other}, want: ""},
+		{name: "combined", filter: repository.AlertFilter{Status: &active, VehicleID: &vehicleID}, want: "da"},
+		{name: "limit", filter: repository.AlertFilter{Limit: 2}, want: "dc"},
+		{name: "offset", filter: repository.AlertFilter{Offset: 1, Limit: 2}, want: "cb"},
+		{name: "offset past the end", filter: repository.AlertFilter{Offset: 4}, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			alerts, err := repo.List(context.Background(), tt.filter)
+			if err != nil {
+				t.Fatalf("List: %v", err)
+			}
+			if alerts == nil {
+				t.Fatal("List returned nil, want an empty slice")
+			}
+			var got string
+			for _, a := range alerts {
+				got += a.Message
+			}
+			if got != tt.want {
+				t.Errorf("listed %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlertRepositoryUpdate(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAlertRepository()
+	alert := &domain.Alert{ID: uuid.New(), Status: domain.AlertStatusActive}
+
+	if err := repo.Update(ctx, alert); !errors.Is(err, domain.ErrNotFound) {
+		t.Fatalf("Update() of a missing alert = %v, want domain.ErrNotFound", err)
+	}
+	if err := repo.Create(ctx, alert); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	alert.Status = domain.AlertStatusResolved
+	if err := repo.Update(ctx, alert); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	stored, err := repo.GetByID(ctx, alert.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if stored.Status != domain.AlertStatusResolved {
+		t.Errorf("status = %s, want %s", stored.Status, domain.AlertStatusResolved)
+	}
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// DeviceKeyRepository is an in-memory repository.DeviceKeyRepository
+type DeviceKeyRepository struct {
+	keys map[uuid.UUID]domain.DeviceKey
+	mu   sync.RWMutex
+}
+
+// NewDeviceKeyRepository creates an empty DeviceKeyRepository
+func NewDeviceKeyRepository() *DeviceKeyRepository {
+	return &DeviceKeyRepository{keys: make(map[uuid.UUID]domain.DeviceKey)}
+}
+
+func (r *DeviceKeyRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.DeviceKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	keys := []domain.DeviceKey{}
+	for _, k := range r.keys {
+		if k.VehicleID == vehicleID {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys, nil
+}
+
+func (r *DeviceKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DeviceKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	k, ok := r.keys[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &k, nil
+}
+
+func (r *DeviceKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.DeviceKey, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, k := range r.keys {
+		if k.KeyHash == hash {
+			return &k, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *DeviceKeyRepository) Create(ctx context.Context, key *domain.DeviceKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.keys[key.ID] = *key
+	return nil
+}
+
+func (r *DeviceKeyRepository) Update(ctx context.Context, key *domain.DeviceKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.keys[key.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.keys[key.ID] = *key
+	return nil
+}
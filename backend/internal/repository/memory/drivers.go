@@ -0,0 +1,187 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// DriverRepository is an in-memory repository.DriverRepository
+type DriverRepository struct {
+	drivers map[uuid.UUID]domain.Driver
+	mu      sync.RWMutex
+}
+
+// NewDriverRepository creates a DriverRepository pre-populated with seed
+func NewDriverRepository(seed ...domain.Driver) *DriverRepository {
+	r := &DriverRepository{drivers: make(map[uuid.UUID]domain.Driver, len(seed))}
+	for _, d := range seed {
+		r.drivers[d.ID] = d
+	}
+	return r
+}
+
+func (r *DriverRepository) List(ctx context.Context) ([]domain.Driver, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	drivers := make([]domain.Driver, 0, len(r.drivers))
+	for _, d := range r.drivers {
+		drivers = append(drivers, d)
+	}
+	sort.Slice(drivers, func(i, j int) bool { return drivers[i].Name < drivers[j].Name })
+	return drivers, nil
+}
+
+func (r *DriverRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Driver, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	d, ok := r.drivers[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &d, nil
+}
+
+func (r *DriverRepository) Create(ctx context.Context, driver *domain.Driver) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(driver) {
+		return domain.ErrConflict
+	}
+	r.drivers[driver.ID] = *driver
+	return nil
+}
+
+func (r *DriverRepository) Update(ctx context.Context, driver *domain.Driver) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.drivers[driver.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	if r.emailTaken(driver) {
+		return domain.ErrConflict
+	}
+	r.drivers[driver.ID] = *driver
+	return nil
+}
+
+func (r *DriverRepository) UpdateRating(ctx context.Context, id uuid.UUID, rating float32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	d, ok := r.drivers[id]
+	if !ok {
+		return domain.ErrNotFound
+	}
+	d.Rating = rating
+	d.UpdatedAt = time.Now()
+	r.drivers[id] = d
+	return nil
+}
+
+func (r *DriverRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.drivers[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.drivers, id)
+	return nil
+}
+
+// emailTaken reports whether another driver already has driver's email;
+// drivers without an email never clash
+func (r *DriverRepository) emailTaken(driver *domain.Driver) bool {
+	if driver.Email == "" {
+		return false
+	}
+	for _, d := range r.drivers {
+		if d.ID != driver.ID && strings.EqualFold(d.Email, driver.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// DriverAssignmentRepository is an in-memory
+// repository.DriverAssignmentRepository
+type DriverAssignmentRepository struct {
+	assignments map[uuid.UUID]domain.DriverAssignment
+	mu          sync.RWMutex
+}
+
+// NewDriverAssignmentRepository creates a DriverAssignmentRepository
+// pre-populated with seed
+func NewDriverAssignmentRepository(seed ...domain.DriverAssignment) *DriverAssignmentRepository {
+	r := &DriverAssignmentRepository{assignments: make(map[uuid.UUID]domain.DriverAssignment, len(seed))}
+	for _, a := range seed {
+		r.assignments[a.ID] = a
+	}
+	return r
+}
+
+func (r *DriverAssignmentRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.DriverAssignment, error) {
+	return r.filter(func(a domain.DriverAssignment) bool { return a.VehicleID == vehicleID }), nil
+}
+
+func (r *DriverAssignmentRepository) ListByDriver(ctx context.Context, driverID uuid.UUID) ([]domain.DriverAssignment, error) {
+	return r.filter(func(a domain.DriverAssignment) bool { return a.DriverID == driverID }), nil
+}
+
+func (r *DriverAssignmentRepository) ActiveAt(ctx context.Context, vehicleID uuid.UUID, at time.Time) (*domain.DriverAssignment, error) {
+	matches := r.filter(func(a domain.DriverAssignment) bool { return a.VehicleID == vehicleID && a.Covers(at) })
+	if len(matches) == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &matches[0], nil
+}
+
+func (r *DriverAssignmentRepository) Create(ctx context.Context, assignment *domain.DriverAssignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if assignment.UnassignedAt == nil {
+		for _, a := range r.assignments {
+			if a.UnassignedAt == nil && (a.VehicleID == assignment.VehicleID || a.DriverID == assignment.DriverID) {
+				return domain.ErrConflict
+			}
+		}
+	}
+	r.assignments[assignment.ID] = *assignment
+	return nil
+}
+
+func (r *DriverAssignmentRepository) Update(ctx context.Context, assignment *domain.DriverAssignment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.assignments[assignment.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.assignments[assignment.ID] = *assignment
+	return nil
+}
+
+func (r *DriverAssignmentRepository) filter(match func(domain.DriverAssignment) bool) []domain.DriverAssignment {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	assignments := []domain.DriverAssignment{}
+	for _, a := range r.assignments {
+		if match(a) {
+			assignments = append(assignments, a)
+		}
+	}
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].AssignedAt.After(assignments[j].AssignedAt) })
+	return assignments
+}
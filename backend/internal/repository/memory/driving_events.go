@@ -0,0 +1,44 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// DrivingEventRepository is an in-memory repository.DrivingEventRepository
+type DrivingEventRepository struct {
+	events []domain.DrivingEvent
+	mu     sync.RWMutex
+}
+
+// NewDrivingEventRepository creates an empty DrivingEventRepository
+func NewDrivingEventRepository() *DrivingEventRepository {
+	return &DrivingEventRepository{}
+}
+
+func (r *DrivingEventRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]domain.DrivingEvent, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	events := []domain.DrivingEvent{}
+	for _, e := range r.events {
+		if e.DriverID == driverID && !e.OccurredAt.Before(from) && e.OccurredAt.Before(to) {
+			events = append(events, e)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].OccurredAt.After(events[j].OccurredAt) })
+	return events, nil
+}
+
+func (r *DrivingEventRepository) Create(ctx context.Context, event *domain.DrivingEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, *event)
+	return nil
+}
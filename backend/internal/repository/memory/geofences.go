@@ -0,0 +1,74 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// GeofenceRepository is an in-memory repository.GeofenceRepository
+type GeofenceRepository struct {
+	geofences map[uuid.UUID]domain.Geofence
+	mu        sync.RWMutex
+}
+
+// NewGeofenceRepository creates an empty GeofenceRepository
+func NewGeofenceRepository() *GeofenceRepository {
+	return &GeofenceRepository{geofences: make(map[uuid.UUID]domain.Geofence)}
+}
+
+func (r *GeofenceRepository) List(ctx context.Context) ([]domain.Geofence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	geofences := make([]domain.Geofence, 0, len(r.geofences))
+	for _, g := range r.geofences {
+		geofences = append(geofences, g)
+	}
+	sort.Slice(geofences, func(i, j int) bool { return geofences[i].Name < geofences[j].Name })
+	return geofences, nil
+}
+
+func (r *GeofenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Geofence, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	g, ok := r.geofences[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &g, nil
+}
+
+func (r *GeofenceRepository) Create(ctx context.Context, geofence *domain.Geofence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.geofences[geofence.ID] = *geofence
+	return nil
+}
+
+func (r *GeofenceRepository) Update(ctx context.Context, geofence *domain.Geofence) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.geofences[geofence.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.geofences[geofence.ID] = *geofence
+	return nil
+}
+
+func (r *GeofenceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.geofences[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.geofences, id)
+	return nil
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// MaintenanceRepository is an in-memory repository.MaintenanceRepository
+type MaintenanceRepository struct {
+	records map[uuid.UUID]domain.MaintenanceRecord
+	mu      sync.RWMutex
+}
+
+// NewMaintenanceRepository creates an empty MaintenanceRepository
+func NewMaintenanceRepository() *MaintenanceRepository {
+	return &MaintenanceRepository{records: make(map[uuid.UUID]domain.MaintenanceRecord)}
+}
+
+func (r *MaintenanceRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.MaintenanceRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	records := []domain.MaintenanceRecord{}
+	for _, m := range r.records {
+		if m.VehicleID == vehicleID {
+			records = append(records, m)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].Date.After(records[j].Date) })
+	return records, nil
+}
+
+func (r *MaintenanceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenanceRecord, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.records[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &m, nil
+}
+
+func (r *MaintenanceRepository) Create(ctx context.Context, record *domain.MaintenanceRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[record.ID] = *record
+	return nil
+}
+
+func (r *MaintenanceRepository) Update(ctx context.Context, record *domain.MaintenanceRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.records[record.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.records[record.ID] = *record
+	return nil
+}
+
+func (r *MaintenanceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.records[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.records, id)
+	return nil
+}
+
+// MaintenancePlanRepository is an in-memory repository.MaintenancePlanRepository
+type MaintenancePlanRepository struct {
+	plans map[uuid.UUID]domain.MaintenancePlan
+	mu    sync.RWMutex
+}
+
+// NewMaintenancePlanRepository creates an empty MaintenancePlanRepository
+func NewMaintenancePlanRepository() *MaintenancePlanRepository {
+	return &MaintenancePlanRepository{plans: make(map[uuid.UUID]domain.MaintenancePlan)}
+}
+
+func (r *MaintenancePlanRepository) List(ctx context.Context) ([]domain.MaintenancePlan, error) {
+	return r.list(func(*domain.MaintenancePlan) bool { return true }), nil
+}
+
+func (r *MaintenancePlanRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.MaintenancePlan, error) {
+	return r.list(func(p *domain.MaintenancePlan) bool { return p.VehicleID == vehicleID }), nil
+}
+
+func (r *MaintenancePlanRepository) list(match func(*domain.MaintenancePlan) bool) []domain.MaintenancePlan {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	plans := []domain.MaintenancePlan{}
+	for _, p := range r.plans {
+		if match(&p) {
+			plans = append(plans, p)
+		}
+	}
+	sort.Slice(plans, func(i, j int) bool { return plans[i].CreatedAt.Before(plans[j].CreatedAt) })
+	return plans
+}
+
+func (r *MaintenancePlanRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenancePlan, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	p, ok := r.plans[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &p, nil
+}
+
+func (r *MaintenancePlanRepository) Create(ctx context.Context, plan *domain.MaintenancePlan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.plans[plan.ID] = *plan
+	return nil
+}
+
+func (r *MaintenancePlanRepository) Update(ctx context.Context, plan *domain.MaintenancePlan) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.plans[plan.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.plans[plan.ID] = *plan
+	return nil
+}
+
+func (r *MaintenancePlanRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.plans[id]; !ok {
+		return domain.ErrNotFound
+	}
+	delete(r.plans, id)
+	return nil
+}
@@ -0,0 +1,256 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// SeedVehicles returns the development fleet, matching the seed rows in
+// migrations/init.sql
+func SeedVehicles() []domain.Vehicle {
+	d1 := uuid.MustParse("d1111111-1111-1111-1111-111111111111")
+	d2 := uuid.MustParse("d2222222-2222-2222-2222-222222222222")
+	d3 := uuid.MustParse("d3333333-3333-3333-3333-333333333333")
+	d4 := uuid.MustParse("d4444444-4444-4444-4444-444444444444")
+
+	return []domain.Vehicle{
+		{
+			ID:           uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+			VIN:          "TSLA-S-99283",
+			Name:         "Logistics Unit A1",
+			Model:        "Tesla Semi",
+			Brand:        "Tesla",
+			Image:        "https://images.unsplash.com/photo-1617788138017-80ad40651399?auto=format&fit=crop&q=80&w=800",
+			Status:       domain.VehicleStatusActive,
+			BatteryLevel: 78,
+			Range:        420,
+			Location:     domain.Location{Lat: 40.7128, Lng: -74.0060, Address: "Broadway, New York"},
+			Speed:        65,
+			Temperature:  21,
+			Odometer:     12450,
+			Efficiency:   "1.8 kWh/km",
+			DriverID:     &d1,
+			Driver: &domain.Driver{
+				ID:     d1,
+				Name:   "Alex M.",
+				Avatar: "https://i.pravatar.cc/150?u=a042581f4e29026024d",
+				Rating: 4.9,
+			},
+			CreatedAt: time.Now().AddDate(0, -6, 0),
+			UpdatedAt: time.Now(),
+		},
+		{
+			ID:           uuid.MustParse("22222222-2222-2222-2222-222222222222"),
+			VIN:          "MB-SPR-1102",
+			Name:         "Rapid Delivery 04",
+			Model:        "eSprinter Van",
+			Brand:        "Mercedes-Benz",
+			Image:        "https://images.unsplash.com/photo-1591293836371-9231f827255f?auto=format&fit=crop&q=80&w=800",
+			Status:       domain.VehicleStatusCharging,
+			BatteryLevel: 24,
+			Range:        45,
+			Location:     domain.Location{Lat: 40.7580, Lng: -73.9855, Address: "Charging Station 4"},
+			Speed:        0,
+			Temperature:  19,
+			Odometer:     45200,
+			Efficiency:   "22 kWh/100km",
+			DriverID:     &d2,
+			Driver: &domain.Driver{
+				ID:     d2,
+				Name:   "Sarah J.",
+				Avatar: "https://i.pravatar.cc/150?u=a042581f4e29026704d",
+				Rating: 4.7,
+			},
+			CreatedAt: time.Now().AddDate(0, -4, 0),
+			UpdatedAt: time.Now(),
+		},
+		{
+			ID:           uuid.MustParse("33333333-3333-3333-3333-333333333333"),
+			VIN:          "RIV-EDV-552",
+			Name:         "Urban Hauler X",
+			Model:        "Rivian EDV",
+			Brand:        "Rivian",
+			Image:        "https://images.unsplash.com/photo-1675258364539-780c74996459?auto=format&fit=crop&q=80&w=800",
+			Status:       domain.VehicleStatusIdle,
+			BatteryLevel: 92,
+			Range:        200,
+			Location:     domain.Location{Lat: 40.7829, Lng: -73.9654, Address: "Central Depot"},
+			Speed:        0,
+			Temperature:  22,
+			Odometer:     8900,
+			Efficiency:   "19 kWh/100km",
+			DriverID:     &d3,
+			Driver: &domain.Driver{
+				ID:     d3,
+				Name:   "Mike T.",
+				Avatar: "https://i.pravatar.cc/150?u=a04258114e29026302d",
+				Rating: 4.8,
+			},
+			CreatedAt: time.Now().AddDate(0, -2, 0),
+			UpdatedAt: time.Now(),
+		},
+		{
+			ID:           uuid.MustParse("44444444-4444-4444-4444-444444444444"),
+			VIN:          "VOL-FH-883",
+			Name:         "Heavy Freight 02",
+			Model:        "Volvo FH Electric",
+			Brand:        "Volvo",
+			Image:        "https://images.unsplash.com/photo-1601584115197-04ecc0da31d7?auto=format&fit=crop&q=80&w=800",
+			Status:       domain.VehicleStatusActive,
+			BatteryLevel: 45,
+			Range:        180,
+			Location:     domain.Location{Lat: 40.7484, Lng: -73.9857, Address: "Empire State Delivery"},
+			Speed:        42,
+			Temperature:  20,
+			Odometer:     85430,
+			Efficiency:   "1.2 kWh/km",
+			DriverID:     &d4,
+			Driver: &domain.Driver{
+				ID:     d4,
+				Name:   "David L.",
+				Avatar: "https://i.pravatar.cc/150?u=a04258114e29026708c",
+				Rating: 5.0,
+			},
+			CreatedAt: time.Now().AddDate(-1, 0, 0),
+			UpdatedAt: time.Now(),
+		},
+	}
+}
+
+// SeedDrivers returns the development drivers, matching the seed rows in
+// migrations/init.sql
+func SeedDrivers() []domain.Driver {
+	now := time.Now().UTC()
+	drivers := []domain.Driver{
+		{
+			ID:     uuid.MustParse("d1111111-1111-1111-1111-111111111111"),
+			Name:   "Alex M.",
+			Email:  "alex@fleetpulse.dev",
+			Avatar: "https://i.pravatar.cc/150?u=a042581f4e29026024d",
+			Rating: 4.9,
+		},
+		{
+			ID:     uuid.MustParse("d2222222-2222-2222-2222-222222222222"),
+			Name:   "Sarah J.",
+			Email:  "sarah@fleetpulse.dev",
+			Avatar: "https://i.pravatar.cc/150?u=a042581f4e29026704d",
+			Rating: 4.7,
+		},
+		{
+			ID:     uuid.MustParse("d3333333-3333-3333-3333-333333333333"),
+			Name:   "Mike T.",
+			Email:  "mike@fleetpulse.dev",
+			Avatar: "https://i.pravatar.cc/150?u=a04258114e29026302d",
+			Rating: 4.8,
+		},
+		{
+			ID:     uuid.MustParse("d4444444-4444-4444-4444-444444444444"),
+			Name:   "David L.",
+			Email:  "david@fleetpulse.dev",
+			Avatar: "https://i.pravatar.cc/150?u=a04258114e29026708c",
+			Rating: 5.0,
+		},
+	}
+	for i := range drivers {
+		drivers[i].CreatedAt = now
+		drivers[i].UpdatedAt = now
+	}
+	return drivers
+}
+
+// SeedDriverAssignments returns an open assignment, since the vehicle was
+// created, for each of vehicles that has a driver, matching the seed rows in
+// migrations/init.sql
+func SeedDriverAssignments(vehicles []domain.Vehicle) []domain.DriverAssignment {
+	assignments := []domain.DriverAssignment{}
+	for _, v := range vehicles {
+		if v.DriverID == nil {
+			continue
+		}
+		assignments = append(assignments, domain.DriverAssignment{
+			ID:         uuid.New(),
+			DriverID:   *v.DriverID,
+			VehicleID:  v.ID,
+			AssignedAt: v.CreatedAt,
+		})
+	}
+	return assignments
+}
+
+// SeedAlerts returns the development alerts, matching the seed rows in
+// migrations/init.sql
+func SeedAlerts() []domain.Alert {
+	v1 := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	v2 := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+	v4 := uuid.MustParse("44444444-4444-4444-4444-444444444444")
+
+	return []domain.Alert{
+		{
+			ID:        uuid.MustParse("a1111111-1111-1111-1111-111111111111"),
+			VehicleID: &v1,
+			Type:      "tire_pressure",
+			Severity:  domain.AlertSeverityCritical,
+			Status:    domain.AlertStatusActive,
+			Message:   "Tire pressure low - Vehicle v1",
+			CreatedAt: time.Now().Add(-2 * time.Minute),
+		},
+		{
+			ID:        uuid.MustParse("a2222222-2222-2222-2222-222222222222"),
+			VehicleID: &v4,
+			Type:      "unexpected_stop",
+			Severity:  domain.AlertSeverityWarning,
+			Status:    domain.AlertStatusActive,
+			Message:   "Unexpected stop detected",
+			CreatedAt: time.Now().Add(-15 * time.Minute),
+		},
+		{
+			ID:        uuid.MustParse("a3333333-3333-3333-3333-333333333333"),
+			VehicleID: &v2,
+			Type:      "charging_started",
+			Severity:  domain.AlertSeverityInfo,
+			Status:    domain.AlertStatusActive,
+			Message:   "Vehicle v2 started charging",
+			CreatedAt: time.Now().Add(-1 * time.Hour),
+		},
+	}
+}
+
+// SeedUsers returns the development operators, matching the seed rows in
+// migrations/init.sql
+func SeedUsers() []domain.User {
+	now := time.Now().UTC()
+	users := []domain.User{
+		{
+			ID:    uuid.MustParse("e1111111-1111-1111-1111-111111111111"),
+			Email: "admin@fleetpulse.dev",
+			Name:  "Fleet Admin",
+			Role:  domain.UserRoleAdmin,
+		},
+		{
+			ID:    uuid.MustParse("e2222222-2222-2222-2222-222222222222"),
+			Email: "dispatch@fleetpulse.dev",
+			Name:  "Dispatch Desk",
+			Role:  domain.UserRoleDispatcher,
+		},
+		{
+			ID:    uuid.MustParse("e3333333-3333-3333-3333-333333333333"),
+			Email: "viewer@fleetpulse.dev",
+			Name:  "Read Only",
+			Role:  domain.UserRoleViewer,
+		},
+		{
+			ID:    uuid.MustParse("e4444444-4444-4444-4444-444444444444"),
+			Email: "simulator@fleetpulse.dev",
+			Name:  "Vehicle Simulator",
+			Role:  domain.UserRoleDevice,
+		},
+	}
+	for i := range users {
+		users[i].IsActive = true
+		users[i].CreatedAt = now
+		users[i].UpdatedAt = now
+	}
+	return users
+}
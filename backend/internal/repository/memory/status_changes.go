@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// VehicleStatusChangeRepository is an in-memory
+// repository.VehicleStatusChangeRepository
+type VehicleStatusChangeRepository struct {
+	changes []domain.VehicleStatusChange
+	mu      sync.RWMutex
+}
+
+// NewVehicleStatusChangeRepository creates an empty
+// VehicleStatusChangeRepository
+func NewVehicleStatusChangeRepository() *VehicleStatusChangeRepository {
+	return &VehicleStatusChangeRepository{}
+}
+
+func (r *VehicleStatusChangeRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.VehicleStatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	changes := []domain.VehicleStatusChange{}
+	for _, c := range r.changes {
+		if c.VehicleID == vehicleID && !c.ChangedAt.Before(from) && c.ChangedAt.Before(to) {
+			changes = append(changes, c)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].ChangedAt.Before(changes[j].ChangedAt) })
+	return changes, nil
+}
+
+func (r *VehicleStatusChangeRepository) LastBefore(ctx context.Context, vehicleID uuid.UUID, at time.Time) (*domain.VehicleStatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var last *domain.VehicleStatusChange
+	for i := range r.changes {
+		c := &r.changes[i]
+		if c.VehicleID != vehicleID || !c.ChangedAt.Before(at) {
+			continue
+		}
+		if last == nil || !c.ChangedAt.Before(last.ChangedAt) {
+			last = c
+		}
+	}
+	if last == nil {
+		return nil, domain.ErrNotFound
+	}
+	change := *last
+	return &change, nil
+}
+
+func (r *VehicleStatusChangeRepository) Create(ctx context.Context, change *domain.VehicleStatusChange) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.changes = append(r.changes, *change)
+	return nil
+}
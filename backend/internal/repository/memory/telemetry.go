@@ -0,0 +1,303 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"unsafe"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// TelemetryRepository is an in-memory repository.TelemetryRepository.
+// Points and rollups are kept per vehicle, ordered by timestamp.
+type TelemetryRepository struct {
+	points  map[uuid.UUID][]domain.Telemetry
+	rollups map[domain.Resolution]map[uuid.UUID][]domain.TelemetryRollup
+	mu      sync.RWMutex
+}
+
+// NewTelemetryRepository creates an empty TelemetryRepository
+func NewTelemetryRepository() *TelemetryRepository {
+	return &TelemetryRepository{
+		points:  make(map[uuid.UUID][]domain.Telemetry),
+		rollups: make(map[domain.Resolution]map[uuid.UUID][]domain.TelemetryRollup),
+	}
+}
+
+func (r *TelemetryRepository) Insert(ctx context.Context, t *domain.Telemetry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.insert(*t)
+	return nil
+}
+
+func (r *TelemetryRepository) InsertBatch(ctx context.Context, telemetry []domain.Telemetry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range telemetry {
+		r.insert(t)
+	}
+	return nil
+}
+
+func (r *TelemetryRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.Telemetry, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	points := r.points[vehicleID]
+	start := sort.Search(len(points), func(i int) bool { return !points[i].Timestamp.Before(from) })
+	end := sort.Search(len(points), func(i int) bool { return !points[i].Timestamp.Before(to) })
+
+	telemetry := []domain.Telemetry{}
+	if start < end {
+		telemetry = append(telemetry, points[start:end]...)
+	}
+	return telemetry, nil
+}
+
+func (r *TelemetryRepository) Aggregate(ctx context.Context, query repository.TelemetryAggregate) ([]domain.TelemetryBucket, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vehicleIDs := query.VehicleIDs
+	if len(vehicleIDs) == 0 {
+		for id := range r.points {
+			vehicleIDs = append(vehicleIDs, id)
+		}
+	}
+
+	buckets := []domain.TelemetryBucket{}
+	for _, id := range vehicleIDs {
+		points := r.points[id]
+		start := sort.Search(len(points), func(i int) bool { return !points[i].Timestamp.Before(query.From) })
+		end := sort.Search(len(points), func(i int) bool { return !points[i].Timestamp.Before(query.To) })
+
+		var current *domain.TelemetryBucket
+		for i := start; i < end; i++ {
+			p := &points[i]
+			bucketStart := query.Bucket.Truncate(p.Timestamp, query.Location)
+			if current == nil || !current.Start.Equal(bucketStart) {
+				buckets = append(buckets, domain.TelemetryBucket{VehicleID: id, Start: bucketStart})
+				current = &buckets[len(buckets)-1]
+			}
+
+			current.Samples++
+			if i == start {
+				continue
+			}
+			prev := &points[i-1]
+			current.DistanceKm += domain.DistanceMeters(prev.Location, p.Location) / 1000
+			if drained := prev.BatteryLevel - p.BatteryLevel; drained > 0 {
+				current.EnergyUsed += float64(drained)
+			}
+		}
+	}
+
+	sort.SliceStable(buckets, func(i, j int) bool { return buckets[i].Start.Before(buckets[j].Start) })
+	return buckets, nil
+}
+
+func (r *TelemetryRepository) Rollup(ctx context.Context, resolution domain.Resolution, from, to time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interval := resolution.Interval()
+	from, to = from.Truncate(interval), to.Truncate(interval)
+
+	byVehicle, ok := r.rollups[resolution]
+	if !ok {
+		byVehicle = make(map[uuid.UUID][]domain.TelemetryRollup)
+		r.rollups[resolution] = byVehicle
+	}
+
+	written := 0
+	for id, points := range r.points {
+		start := sort.Search(len(points), func(i int) bool { return !points[i].Timestamp.Before(from) })
+		end := sort.Search(len(points), func(i int) bool { return !points[i].Timestamp.Before(to) })
+
+		var computed []domain.TelemetryRollup
+		for i := start; i < end; i++ {
+			p := &points[i]
+			bucketStart := p.Timestamp.Truncate(interval)
+			if n := len(computed); n == 0 || !computed[n-1].Timestamp.Equal(bucketStart) {
+				computed = append(computed, domain.TelemetryRollup{VehicleID: id, Resolution: resolution, Timestamp: bucketStart})
+			}
+
+			var prev *domain.Telemetry
+			if i > 0 && p.Timestamp.Sub(points[i-1].Timestamp) <= domain.RollupMaxGap {
+				prev = &points[i-1]
+			}
+			addToRollup(&computed[len(computed)-1], p, prev)
+		}
+
+		// Replace the stored rollups in range with the recomputed ones
+		stored := byVehicle[id]
+		lo := sort.Search(len(stored), func(i int) bool { return !stored[i].Timestamp.Before(from) })
+		hi := sort.Search(len(stored), func(i int) bool { return !stored[i].Timestamp.Before(to) })
+		merged := make([]domain.TelemetryRollup, 0, len(stored)-(hi-lo)+len(computed))
+		merged = append(merged, stored[:lo]...)
+		merged = append(merged, computed...)
+		merged = append(merged, stored[hi:]...)
+		byVehicle[id] = merged
+		written += len(computed)
+	}
+	return written, nil
+}
+
+func (r *TelemetryRepository) ListRollups(ctx context.Context, vehicleID uuid.UUID, resolution domain.Resolution, from, to time.Time) ([]domain.TelemetryRollup, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.rollups[resolution][vehicleID]
+	start := sort.Search(len(stored), func(i int) bool { return !stored[i].Timestamp.Before(from) })
+	end := sort.Search(len(stored), func(i int) bool { return !stored[i].Timestamp.Before(to) })
+
+	rollups := []domain.TelemetryRollup{}
+	if start < end {
+		rollups = append(rollups, stored[start:end]...)
+	}
+	return rollups, nil
+}
+
+func (r *TelemetryRepository) RollupWatermark(ctx context.Context, resolution domain.Resolution) (time.Time, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var watermark time.Time
+	for _, stored := range r.rollups[resolution] {
+		if n := len(stored); n > 0 && stored[n-1].Timestamp.After(watermark) {
+			watermark = stored[n-1].Timestamp
+		}
+	}
+	if !watermark.IsZero() {
+		return watermark, nil
+	}
+
+	for _, points := range r.points {
+		if len(points) > 0 && (watermark.IsZero() || points[0].Timestamp.Before(watermark)) {
+			watermark = points[0].Timestamp
+		}
+	}
+	return watermark, nil
+}
+
+func (r *TelemetryRepository) Prune(ctx context.Context, tier domain.Resolution, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	if tier == domain.ResolutionRaw {
+		for id, points := range r.points {
+			n := sort.Search(len(points), func(i int) bool { return !points[i].Timestamp.Before(before) })
+			deleted += int64(n)
+			if n == len(points) {
+				delete(r.points, id)
+			} else if n > 0 {
+				r.points[id] = append([]domain.Telemetry(nil), points[n:]...)
+			}
+		}
+		return deleted, nil
+	}
+
+	byVehicle := r.rollups[tier]
+	for id, stored := range byVehicle {
+		n := sort.Search(len(stored), func(i int) bool { return !stored[i].Timestamp.Before(before) })
+		deleted += int64(n)
+		if n == len(stored) {
+			delete(byVehicle, id)
+		} else if n > 0 {
+			byVehicle[id] = append([]domain.TelemetryRollup(nil), stored[n:]...)
+		}
+	}
+	return deleted, nil
+}
+
+func (r *TelemetryRepository) Storage(ctx context.Context) ([]domain.TelemetryStorage, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	const (
+		recordBytes = int64(unsafe.Sizeof(domain.Telemetry{}))
+		rollupBytes = int64(unsafe.Sizeof(domain.TelemetryRollup{}))
+	)
+
+	byVehicle := make(map[uuid.UUID]*domain.TelemetryStorage)
+	storageOf := func(id uuid.UUID) *domain.TelemetryStorage {
+		s, ok := byVehicle[id]
+		if !ok {
+			s = &domain.TelemetryStorage{VehicleID: id, Records: make(map[domain.Resolution]int64)}
+			byVehicle[id] = s
+		}
+		return s
+	}
+
+	for id, points := range r.points {
+		if len(points) == 0 {
+			continue
+		}
+		s := storageOf(id)
+		oldest, newest := points[0].Timestamp, points[len(points)-1].Timestamp
+		s.OldestRecord, s.NewestRecord = &oldest, &newest
+		s.Records[domain.ResolutionRaw] = int64(len(points))
+		s.EstimatedBytes += int64(len(points)) * recordBytes
+	}
+	for resolution, rollups := range r.rollups {
+		for id, stored := range rollups {
+			if len(stored) == 0 {
+				continue
+			}
+			s := storageOf(id)
+			s.Records[resolution] = int64(len(stored))
+			s.EstimatedBytes += int64(len(stored)) * rollupBytes
+		}
+	}
+
+	storage := make([]domain.TelemetryStorage, 0, len(byVehicle))
+	for _, s := range byVehicle {
+		storage = append(storage, *s)
+	}
+	sort.Slice(storage, func(i, j int) bool { return storage[i].EstimatedBytes > storage[j].EstimatedBytes })
+	return storage, nil
+}
+
+// addToRollup folds a record into its bucket's rollup. prev is the record
+// before it, when close enough for the movement between them to count.
+func addToRollup(rollup *domain.TelemetryRollup, t, prev *domain.Telemetry) {
+	n := float32(rollup.Samples)
+	if rollup.Samples == 0 {
+		rollup.SpeedMin, rollup.SpeedMax = t.Speed, t.Speed
+		rollup.BatteryMin, rollup.BatteryMax = t.BatteryLevel, t.BatteryLevel
+		rollup.EngineTempMin, rollup.EngineTempMax = t.EngineTemp, t.EngineTemp
+	}
+
+	rollup.Samples++
+	rollup.Location = t.Location
+	rollup.Speed = (rollup.Speed*n + t.Speed) / (n + 1)
+	rollup.SpeedMin = min(rollup.SpeedMin, t.Speed)
+	rollup.SpeedMax = max(rollup.SpeedMax, t.Speed)
+	rollup.BatteryLevel = (rollup.BatteryLevel*n + float32(t.BatteryLevel)) / (n + 1)
+	rollup.BatteryMin = min(rollup.BatteryMin, t.BatteryLevel)
+	rollup.BatteryMax = max(rollup.BatteryMax, t.BatteryLevel)
+	rollup.EngineTemp = (rollup.EngineTemp*n + t.EngineTemp) / (n + 1)
+	rollup.EngineTempMin = min(rollup.EngineTempMin, t.EngineTemp)
+	rollup.EngineTempMax = max(rollup.EngineTempMax, t.EngineTemp)
+	if prev != nil {
+		rollup.DistanceKm += domain.DistanceMeters(prev.Location, t.Location) / 1000
+	}
+}
+
+// insert keeps the per-vehicle slice sorted; callers must hold the write lock
+func (r *TelemetryRepository) insert(t domain.Telemetry) {
+	points := r.points[t.VehicleID]
+	i := sort.Search(len(points), func(i int) bool { return points[i].Timestamp.After(t.Timestamp) })
+	points = append(points, domain.Telemetry{})
+	copy(points[i+1:], points[i:])
+	points[i] = t
+	r.points[t.VehicleID] = points
+}
@@ -0,0 +1,123 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+func TestTelemetryRepositoryListByVehicle(t *testing.T) {
+	ctx := context.Background()
+	vehicleID := uuid.New()
+	start := time.Now().Truncate(time.Hour).Add(-time.Hour)
+	at := func(minute int) time.Time { return start.Add(time.Duration(minute) * time.Minute) }
+
+	repo := NewTelemetryRepository()
+	// Inserted out of order, singly and in a batch, alongside another vehicle
+	for _, minute := range []int{3, 0} {
+		if err := repo.Insert(ctx, &domain.Telemetry{VehicleID: vehicleID, Timestamp: at(minute)}); err != nil {
+			t.Fatalf("Insert: %v", err)
+		}
+	}
+	err := repo.InsertBatch(ctx, []domain.Telemetry{
+		{VehicleID: vehicleID, Timestamp: at(4)},
+		{VehicleID: vehicleID, Timestamp: at(1)},
+		{VehicleID: uuid.New(), Timestamp: at(2)},
+		{VehicleID: vehicleID, Timestamp: at(2)},
+	})
+	if err != nil {
+		t.Fatalf("InsertBatch: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		from, to int
+		want     []int // minutes of the records listed
+	}{
+		{name: "all", from: 0, to: 5, want: []int{0, 1, 2, 3, 4}},
+		{name: "from inclusive, to exclusive", from: 1, to: 3, want: []int{1, 2}},
+		{name: "between records", from: 5, to: 10, want: []int{}},
+		{name: "empty range", from: 3, to: 3, want: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			telemetry, err := repo.ListByVehicle(ctx, vehicleID, at(tt.from), at(tt.to))
+			if err != nil {
+				t.Fatalf("ListByVehicle: %v", err)
+			}
+			if telemetry == nil {
+				t.Fatal("ListByVehicle returned nil, want an empty slice")
+			}
+			minutes := make([]int, len(telemetry))
+			for i, record := range telemetry {
+				minutes[i] = int(record.Timestamp.Sub(start) / time.Minute)
+			}
+			if !equalInts(minutes, tt.want) {
+				t.Errorf("listed minutes %v, want %v", minutes, tt.want)
+			}
+		})
+	}
+}
+
+func TestTelemetryRepositoryPruneRaw(t *testing.T) {
+	ctx := context.Background()
+	start := time.Now().Truncate(time.Hour).Add(-time.Hour)
+	at := func(minute int) time.Time { return start.Add(time.Duration(minute) * time.Minute) }
+
+	tests := []struct {
+		name    string
+		before  int
+		deleted int64
+		left    int
+	}{
+		{name: "nothing old enough", before: 0, deleted: 0, left: 3},
+		{name: "some records", before: 2, deleted: 2, left: 1},
+		{name: "every record", before: 10, deleted: 3, left: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vehicleID := uuid.New()
+			repo := NewTelemetryRepository()
+			err := repo.InsertBatch(ctx, []domain.Telemetry{
+				{VehicleID: vehicleID, Timestamp: at(0)},
+				{VehicleID: vehicleID, Timestamp: at(1)},
+				{VehicleID: vehicleID, Timestamp: at(2)},
+			})
+			if err != nil {
+				t.Fatalf("InsertBatch: %v", err)
+			}
+
+			deleted, err := repo.Prune(ctx, domain.ResolutionRaw, at(tt.before))
+			if err != nil {
+				t.Fatalf("Prune: %v", err)
+			}
+			if deleted != tt.deleted {
+				t.Errorf("deleted %d records, want %d", deleted, tt.deleted)
+			}
+			left, err := repo.ListByVehicle(ctx, vehicleID, at(0), at(10))
+			if err != nil {
+				t.Fatalf("ListByVehicle: %v", err)
+			}
+			if len(left) != tt.left {
+				t.Errorf("%d records left, want %d", len(left), tt.left)
+			}
+		})
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// TripRepository is an in-memory repository.TripRepository
+type TripRepository struct {
+	trips map[uuid.UUID]domain.Trip
+	mu    sync.RWMutex
+}
+
+// NewTripRepository creates an empty TripRepository
+func NewTripRepository() *TripRepository {
+	return &TripRepository{trips: make(map[uuid.UUID]domain.Trip)}
+}
+
+func (r *TripRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.Trip, error) {
+	return r.list(from, to, func(t domain.Trip) bool { return t.VehicleID == vehicleID }), nil
+}
+
+func (r *TripRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]domain.Trip, error) {
+	return r.list(from, to, func(t domain.Trip) bool { return t.DriverID != nil && *t.DriverID == driverID }), nil
+}
+
+// list returns the matching trips started within [from, to), newest first
+// and without their paths
+func (r *TripRepository) list(from, to time.Time, match func(domain.Trip) bool) []domain.Trip {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	trips := []domain.Trip{}
+	for _, t := range r.trips {
+		if !match(t) || t.StartedAt.Before(from) || !t.StartedAt.Before(to) {
+			continue
+		}
+		t.Path = nil
+		trips = append(trips, t)
+	}
+	sort.Slice(trips, func(i, j int) bool { return trips[i].StartedAt.After(trips[j].StartedAt) })
+	return trips
+}
+
+func (r *TripRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Trip, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.trips[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &t, nil
+}
+
+func (r *TripRepository) Create(ctx context.Context, trip *domain.Trip) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.trips[trip.ID] = *trip
+	return nil
+}
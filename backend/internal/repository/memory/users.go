@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// UserRepository is an in-memory repository.UserRepository
+type UserRepository struct {
+	users map[uuid.UUID]domain.User
+	mu    sync.RWMutex
+}
+
+// NewUserRepository creates a UserRepository pre-populated with seed
+func NewUserRepository(seed ...domain.User) *UserRepository {
+	r := &UserRepository{users: make(map[uuid.UUID]domain.User, len(seed))}
+	for _, u := range seed {
+		r.users[u.ID] = u
+	}
+	return r
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]domain.User, 0, len(r.users))
+	for _, u := range r.users {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Email < users[j].Email })
+	return users, nil
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	u, ok := r.users[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return &u, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.emailTaken(user) {
+		return domain.ErrConflict
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[user.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	if r.emailTaken(user) {
+		return domain.ErrConflict
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+// emailTaken reports whether another user already has user's email
+func (r *UserRepository) emailTaken(user *domain.User) bool {
+	for _, u := range r.users {
+		if u.ID != user.ID && strings.EqualFold(u.Email, user.Email) {
+			return true
+		}
+	}
+	return false
+}
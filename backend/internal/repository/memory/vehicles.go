@@ -0,0 +1,158 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// VehicleRepository is an in-memory repository.VehicleRepository
+type VehicleRepository struct {
+	vehicles map[uuid.UUID]domain.Vehicle
+	drivers  *DriverRepository // embedded drivers are read from, when set
+	mu       sync.RWMutex
+}
+
+// NewVehicleRepository creates a VehicleRepository pre-populated with seed
+func NewVehicleRepository(seed ...domain.Vehicle) *VehicleRepository {
+	r := &VehicleRepository{vehicles: make(map[uuid.UUID]domain.Vehicle, len(seed))}
+	for _, v := range seed {
+		r.vehicles[v.ID] = v
+	}
+	return r
+}
+
+// JoinDrivers makes the repository embed each vehicle's driver as stored in
+// drivers when it is read, as the PostgreSQL repository joins them, rather
+// than the copy saved with the vehicle
+func (r *VehicleRepository) JoinDrivers(drivers *DriverRepository) *VehicleRepository {
+	r.drivers = drivers
+	return r
+}
+
+func (r *VehicleRepository) List(ctx context.Context) ([]domain.Vehicle, error) {
+	return r.filter(func(domain.Vehicle) bool { return true }), nil
+}
+
+func (r *VehicleRepository) ListByStatus(ctx context.Context, status domain.VehicleStatus) ([]domain.Vehicle, error) {
+	return r.filter(func(v domain.Vehicle) bool { return v.Status == status }), nil
+}
+
+func (r *VehicleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	v, ok := r.vehicles[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	return r.joined(v), nil
+}
+
+func (r *VehicleRepository) Create(ctx context.Context, vehicle *domain.Vehicle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.vehicles[vehicle.ID] = *vehicle
+	return nil
+}
+
+func (r *VehicleRepository) Update(ctx context.Context, vehicle *domain.Vehicle) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.vehicles[vehicle.ID]; !ok {
+		return domain.ErrNotFound
+	}
+	r.vehicles[vehicle.ID] = *vehicle
+	return nil
+}
+
+func (r *VehicleRepository) UpdateLiveState(ctx context.Context, id uuid.UUID, state repository.VehicleLiveState) (*domain.Vehicle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.vehicles[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if v.Status == state.From {
+		v.Status = state.Status
+	}
+	v.Location = state.Location
+	v.Speed = state.Speed
+	v.BatteryLevel = state.BatteryLevel
+	if state.FuelLevel != nil {
+		v.FuelLevel = state.FuelLevel
+	}
+	v.Temperature = state.Temperature
+	v.Odometer += state.DistanceKm
+	v.UpdatedAt = time.Now()
+	r.vehicles[id] = v
+	return r.joined(v), nil
+}
+
+func (r *VehicleRepository) SetStatus(ctx context.Context, id uuid.UUID, from, to domain.VehicleStatus) (*domain.Vehicle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.vehicles[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	if v.Status != from {
+		return nil, domain.ErrConflict
+	}
+	v.Status = to
+	v.UpdatedAt = time.Now()
+	r.vehicles[id] = v
+	return r.joined(v), nil
+}
+
+func (r *VehicleRepository) SetDriver(ctx context.Context, id uuid.UUID, driverID *uuid.UUID) (*domain.Vehicle, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.vehicles[id]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	v.DriverID = driverID
+	v.Driver = nil
+	v.UpdatedAt = time.Now()
+	r.vehicles[id] = v
+	return r.joined(v), nil
+}
+
+func (r *VehicleRepository) filter(match func(domain.Vehicle) bool) []domain.Vehicle {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	vehicles := []domain.Vehicle{}
+	for _, v := range r.vehicles {
+		if match(v) {
+			vehicles = append(vehicles, *r.joined(v))
+		}
+	}
+	sort.Slice(vehicles, func(i, j int) bool { return vehicles[i].Name < vehicles[j].Name })
+	return vehicles
+}
+
+// joined returns v with its driver embedded from r.drivers, when set
+func (r *VehicleRepository) joined(v domain.Vehicle) *domain.Vehicle {
+	if r.drivers == nil {
+		return &v
+	}
+	v.Driver = nil
+	if v.DriverID != nil {
+		if d, err := r.drivers.GetByID(context.Background(), *v.DriverID); err == nil {
+			v.Driver = d
+		}
+	}
+	return &v
+}
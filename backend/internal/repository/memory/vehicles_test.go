@@ -0,0 +1,217 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+func TestVehicleRepositoryList(t *testing.T) {
+	ctx := context.Background()
+	repo := NewVehicleRepository(
+		domain.Vehicle{ID: uuid.New(), Name: "Van 3", Status: domain.VehicleStatusIdle},
+		domain.Vehicle{ID: uuid.New(), Name: "Van 1", Status: domain.VehicleStatusActive},
+		domain.Vehicle{ID: uuid.New(), Name: "Van 2", Status: domain.VehicleStatusIdle},
+	)
+
+	tests := []struct {
+		name   string
+		status domain.VehicleStatus // empty lists all
+		want   []string
+	}{
+		{name: "all", want: []string{"Van 1", "Van 2", "Van 3"}},
+		{name: "idle", status: domain.VehicleStatusIdle, want: []string{"Van 2", "Van 3"}},
+		{name: "charging", status: domain.VehicleStatusCharging, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var vehicles []domain.Vehicle
+			var err error
+			if tt.status == "" {
+				vehicles, err = repo.List(ctx)
+			} else {
+				vehicles, err = repo.ListByStatus(ctx, tt.status)
+			}
+			if err != nil {
+				t.Fatalf("list: %v", err)
+			}
+			if vehicles == nil {
+				t.Fatal("list returned nil, want an empty slice")
+			}
+			names := make([]string, len(vehicles))
+			for i, v := range vehicles {
+				names[i] = v.Name
+			}
+			if !equalStrings(names, tt.want) {
+				t.Errorf("listed %v, want %v", names, tt.want)
+			}
+		})
+	}
+}
+
+func TestVehicleRepositoryNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := NewVehicleRepository()
+	missing := uuid.New()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"GetByID", func() error { _, err := repo.GetByID(ctx, missing); return err }},
+		{"Update", func() error { return repo.Update(ctx, &domain.Vehicle{ID: missing}) }},
+		{"UpdateLiveState", func() error {
+			_, err := repo.UpdateLiveState(ctx, missing, repository.VehicleLiveState{})
+			return err
+		}},
+		{"SetDriver", func() error {
+			_, err := repo.SetDriver(ctx, missing, nil)
+			return err
+		}},
+		{"SetStatus", func() error {
+			_, err := repo.SetStatus(ctx, missing, domain.VehicleStatusIdle, domain.VehicleStatusActive)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, domain.ErrNotFound) {
+				t.Errorf("%s() = %v, want domain.ErrNotFound", tt.name, err)
+			}
+		})
+	}
+}
+
+func TestVehicleRepositoryUpdateLiveState(t *testing.T) {
+	fuel := func(level int) *int { return &level }
+
+	tests := []struct {
+		name       string
+		state      repository.VehicleLiveState
+		wantStatus domain.VehicleStatus
+		wantFuel   int
+		odometer   int
+	}{
+		{
+			name:       "status changed from the expected one",
+			state:      repository.VehicleLiveState{From: domain.VehicleStatusIdle, Status: domain.VehicleStatusActive, FuelLevel: fuel(40)},
+			wantStatus: domain.VehicleStatusActive,
+			wantFuel:   40,
+			odometer:   1000,
+		},
+		{
+			name:       "status changed meanwhile",
+			state:      repository.VehicleLiveState{From: domain.VehicleStatusActive, Status: domain.VehicleStatusIdle, FuelLevel: fuel(40)},
+			wantStatus: domain.VehicleStatusIdle,
+			wantFuel:   40,
+			odometer:   1000,
+		},
+		{
+			name:       "fuel level kept when unreported",
+			state:      repository.VehicleLiveState{From: domain.VehicleStatusIdle, Status: domain.VehicleStatusIdle},
+			wantStatus: domain.VehicleStatusIdle,
+			wantFuel:   60,
+			odometer:   1000,
+		},
+		{
+			name:       "distance added to the odometer",
+			state:      repository.VehicleLiveState{From: domain.VehicleStatusIdle, Status: domain.VehicleStatusActive, DistanceKm: 3},
+			wantStatus: domain.VehicleStatusActive,
+			wantFuel:   60,
+			odometer:   1003,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			id := uuid.New()
+			repo := NewVehicleRepository(domain.Vehicle{
+				ID:        id,
+				Name:      "Van 1",
+				Status:    domain.VehicleStatusIdle,
+				FuelLevel: fuel(60),
+				Odometer:  1000,
+			})
+
+			tt.state.Location = domain.Location{Lat: 40.4, Lng: -3.7}
+			tt.state.Speed = 42
+			tt.state.BatteryLevel = 75
+			saved, err := repo.UpdateLiveState(ctx, id, tt.state)
+			if err != nil {
+				t.Fatalf("UpdateLiveState: %v", err)
+			}
+			stored, err := repo.GetByID(ctx, id)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+
+			for _, v := range []*domain.Vehicle{saved, stored} {
+				if v.Status != tt.wantStatus {
+					t.Errorf("status = %s, want %s", v.Status, tt.wantStatus)
+				}
+				if v.FuelLevel == nil || *v.FuelLevel != tt.wantFuel {
+					t.Errorf("fuel = %v, want %d", v.FuelLevel, tt.wantFuel)
+				}
+				if v.Odometer != tt.odometer {
+					t.Errorf("odometer = %d, want %d", v.Odometer, tt.odometer)
+				}
+				if v.Location != tt.state.Location || v.Speed != 42 || v.BatteryLevel != 75 {
+					t.Errorf("live state = %+v, %v, %d, want the reported one", v.Location, v.Speed, v.BatteryLevel)
+				}
+				if v.Name != "Van 1" || v.UpdatedAt.IsZero() {
+					t.Errorf("vehicle = %+v, want its name kept and UpdatedAt set", v)
+				}
+			}
+		})
+	}
+}
+
+func TestVehicleRepositorySetStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    domain.VehicleStatus
+		want    domain.VehicleStatus
+		wantErr error
+	}{
+		{name: "from the stored status", from: domain.VehicleStatusIdle, want: domain.VehicleStatusMaintenance},
+		{name: "from another status", from: domain.VehicleStatusActive, want: domain.VehicleStatusIdle, wantErr: domain.ErrConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			id := uuid.New()
+			repo := NewVehicleRepository(domain.Vehicle{ID: id, Name: "Van 1", Status: domain.VehicleStatusIdle, Odometer: 1000})
+
+			if _, err := repo.SetStatus(ctx, id, tt.from, domain.VehicleStatusMaintenance); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetStatus() = %v, want %v", err, tt.wantErr)
+			}
+			stored, err := repo.GetByID(ctx, id)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if stored.Status != tt.want || stored.Odometer != 1000 || stored.Name != "Van 1" {
+				t.Errorf("vehicle = %+v, want status %s with the rest kept", stored, tt.want)
+			}
+		})
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
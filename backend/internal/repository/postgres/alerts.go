@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+const alertSelect = `
+	SELECT
+		id, vehicle_id, type, severity, COALESCE(status, 'active'), message, created_at,
+		acknowledged_at, acknowledged_by, resolved_at, resolved_by
+	FROM alerts`
+
+// AlertRepository is a PostgreSQL-backed repository.AlertRepository
+type AlertRepository struct {
+	db *sql.DB
+}
+
+// NewAlertRepository creates a new AlertRepository
+func NewAlertRepository(db *sql.DB) *AlertRepository {
+	return &AlertRepository{db: db}
+}
+
+func (r *AlertRepository) List(ctx context.Context, filter repository.AlertFilter) ([]domain.Alert, error) {
+	query := alertSelect + ` WHERE TRUE`
+	var args []interface{}
+
+	if filter.Status != nil {
+		args = append(args, string(*filter.Status))
+		query += fmt.Sprintf(` AND status = $%d`, len(args))
+	}
+	if filter.Severity != nil {
+		args = append(args, string(*filter.Severity))
+		query += fmt.Sprintf(` AND severity = $%d`, len(args))
+	}
+	if filter.VehicleID != nil {
+		args = append(args, *filter.VehicleID)
+		query += fmt.Sprintf(` AND vehicle_id = $%d`, len(args))
+	}
+
+	query += ` ORDER BY created_at DESC`
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += fmt.Sprintf(` LIMIT $%d`, len(args))
+	}
+	if filter.Offset > 0 {
+		args = append(args, filter.Offset)
+		query += fmt.Sprintf(` OFFSET $%d`, len(args))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query alerts: %w", err)
+	}
+	defer rows.Close()
+
+	alerts := []domain.Alert{}
+	for rows.Next() {
+		alert, err := scanAlert(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan alert: %w", err)
+		}
+		alerts = append(alerts, *alert)
+	}
+	return alerts, rows.Err()
+}
+
+func (r *AlertRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Alert, error) {
+	row := r.db.QueryRowContext(ctx, alertSelect+` WHERE id = $1`, id)
+	alert, err := scanAlert(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get alert: %w", err)
+	}
+	return alert, nil
+}
+
+func (r *AlertRepository) Create(ctx context.Context, alert *domain.Alert) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO alerts (
+			id, vehicle_id, type, severity, status, message, created_at,
+			acknowledged_at, acknowledged_by, resolved_at, resolved_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		alert.ID, alert.VehicleID, alert.Type, string(alert.Severity), string(alert.Status),
+		alert.Message, alert.CreatedAt, alert.AcknowledgedAt, alert.AcknowledgedBy,
+		alert.ResolvedAt, alert.ResolvedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("insert alert: %w", err)
+	}
+	return nil
+}
+
+func (r *AlertRepository) Update(ctx context.Context, alert *domain.Alert) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE alerts SET
+			type = $2, severity = $3, status = $4, message = $5,
+			acknowledged_at = $6, acknowledged_by = $7, resolved_at = $8, resolved_by = $9
+		WHERE id = $1`,
+		alert.ID, alert.Type, string(alert.Severity), string(alert.Status), alert.Message,
+		alert.AcknowledgedAt, alert.AcknowledgedBy, alert.ResolvedAt, alert.ResolvedBy,
+	)
+	if err != nil {
+		return fmt.Errorf("update alert: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanAlert(row rowScanner) (*domain.Alert, error) {
+	var (
+		a                          domain.Alert
+		vehicleID                  uuid.NullUUID
+		severity, status           string
+		acknowledgedAt, resolvedAt sql.NullTime
+		acknowledgedBy, resolvedBy uuid.NullUUID
+	)
+
+	err := row.Scan(
+		&a.ID, &vehicleID, &a.Type, &severity, &status, &a.Message, &a.CreatedAt,
+		&acknowledgedAt, &acknowledgedBy, &resolvedAt, &resolvedBy,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	a.VehicleID = uuidPtr(vehicleID)
+	a.Severity = domain.AlertSeverity(severity)
+	a.Status = domain.AlertStatus(status)
+	a.AcknowledgedAt = timePtr(acknowledgedAt)
+	a.AcknowledgedBy = uuidPtr(acknowledgedBy)
+	a.ResolvedAt = timePtr(resolvedAt)
+	a.ResolvedBy = uuidPtr(resolvedBy)
+
+	return &a, nil
+}
@@ -0,0 +1,103 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+const deviceKeySelect = `
+	SELECT id, vehicle_id, name, prefix, key_hash, created_at, revoked_at
+	FROM device_keys`
+
+// DeviceKeyRepository is a PostgreSQL-backed repository.DeviceKeyRepository
+type DeviceKeyRepository struct {
+	db *sql.DB
+}
+
+// NewDeviceKeyRepository creates a new DeviceKeyRepository
+func NewDeviceKeyRepository(db *sql.DB) *DeviceKeyRepository {
+	return &DeviceKeyRepository{db: db}
+}
+
+func (r *DeviceKeyRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.DeviceKey, error) {
+	rows, err := r.db.QueryContext(ctx, deviceKeySelect+` WHERE vehicle_id = $1 ORDER BY created_at`, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("query device keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []domain.DeviceKey{}
+	for rows.Next() {
+		key, err := scanDeviceKey(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan device key: %w", err)
+		}
+		keys = append(keys, *key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *DeviceKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.DeviceKey, error) {
+	return r.get(ctx, `WHERE id = $1`, id)
+}
+
+func (r *DeviceKeyRepository) GetByHash(ctx context.Context, hash string) (*domain.DeviceKey, error) {
+	return r.get(ctx, `WHERE key_hash = $1`, hash)
+}
+
+func (r *DeviceKeyRepository) get(ctx context.Context, where string, arg interface{}) (*domain.DeviceKey, error) {
+	row := r.db.QueryRowContext(ctx, deviceKeySelect+` `+where, arg)
+	key, err := scanDeviceKey(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get device key: %w", err)
+	}
+	return key, nil
+}
+
+func (r *DeviceKeyRepository) Create(ctx context.Context, k *domain.DeviceKey) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO device_keys (id, vehicle_id, name, prefix, key_hash, created_at, revoked_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		k.ID, k.VehicleID, k.Name, k.Prefix, k.KeyHash, k.CreatedAt, k.RevokedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert device key: %w", err)
+	}
+	return nil
+}
+
+func (r *DeviceKeyRepository) Update(ctx context.Context, k *domain.DeviceKey) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE device_keys SET name = $2, revoked_at = $3
+		WHERE id = $1`,
+		k.ID, k.Name, k.RevokedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update device key: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanDeviceKey(row rowScanner) (*domain.DeviceKey, error) {
+	var (
+		k         domain.DeviceKey
+		revokedAt sql.NullTime
+	)
+	err := row.Scan(&k.ID, &k.VehicleID, &k.Name, &k.Prefix, &k.KeyHash, &k.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	k.RevokedAt = timePtr(revokedAt)
+	return &k, nil
+}
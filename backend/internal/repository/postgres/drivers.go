@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+const driverSelect = `
+	SELECT
+		id, name, COALESCE(email, ''), COALESCE(phone, ''), COALESCE(avatar, ''),
+		COALESCE(rating, 0)::float8, created_at, updated_at
+	FROM drivers`
+
+// DriverRepository is a PostgreSQL-backed repository.DriverRepository
+type DriverRepository struct {
+	db *sql.DB
+}
+
+// NewDriverRepository creates a new DriverRepository
+func NewDriverRepository(db *sql.DB) *DriverRepository {
+	return &DriverRepository{db: db}
+}
+
+func (r *DriverRepository) List(ctx context.Context) ([]domain.Driver, error) {
+	rows, err := r.db.QueryContext(ctx, driverSelect+` ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("query drivers: %w", err)
+	}
+	defer rows.Close()
+
+	drivers := []domain.Driver{}
+	for rows.Next() {
+		driver, err := scanDriver(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan driver: %w", err)
+		}
+		drivers = append(drivers, *driver)
+	}
+	return drivers, rows.Err()
+}
+
+func (r *DriverRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Driver, error) {
+	row := r.db.QueryRowContext(ctx, driverSelect+` WHERE id = $1`, id)
+	driver, err := scanDriver(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get driver: %w", err)
+	}
+	return driver, nil
+}
+
+func (r *DriverRepository) Create(ctx context.Context, d *domain.Driver) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO drivers (id, name, email, phone, avatar, rating, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		d.ID, d.Name, nullString(d.Email), d.Phone, d.Avatar, d.Rating, d.CreatedAt, d.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("insert driver: %w", err)
+	}
+	return nil
+}
+
+func (r *DriverRepository) Update(ctx context.Context, d *domain.Driver) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE drivers SET name = $2, email = $3, phone = $4, avatar = $5, rating = $6
+		WHERE id = $1
+		RETURNING updated_at`,
+		d.ID, d.Name, nullString(d.Email), d.Phone, d.Avatar, d.Rating,
+	).Scan(&d.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("update driver: %w", err)
+	}
+	return nil
+}
+
+func (r *DriverRepository) UpdateRating(ctx context.Context, id uuid.UUID, rating float32) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE drivers SET rating = $2 WHERE id = $1`, id, rating)
+	if err != nil {
+		return fmt.Errorf("update driver rating: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *DriverRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM drivers WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete driver: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanDriver(row rowScanner) (*domain.Driver, error) {
+	var (
+		d      domain.Driver
+		rating float64
+	)
+
+	err := row.Scan(&d.ID, &d.Name, &d.Email, &d.Phone, &d.Avatar, &rating, &d.CreatedAt, &d.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Rating = float32(rating)
+	return &d, nil
+}
+
+const driverAssignmentSelect = `
+	SELECT id, driver_id, vehicle_id, assigned_at, unassigned_at, assigned_by
+	FROM driver_assignments`
+
+// DriverAssignmentRepository is a PostgreSQL-backed
+// repository.DriverAssignmentRepository
+type DriverAssignmentRepository struct {
+	db *sql.DB
+}
+
+// NewDriverAssignmentRepository creates a new DriverAssignmentRepository
+func NewDriverAssignmentRepository(db *sql.DB) *DriverAssignmentRepository {
+	return &DriverAssignmentRepository{db: db}
+}
+
+func (r *DriverAssignmentRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.DriverAssignment, error) {
+	return r.list(ctx, driverAssignmentSelect+` WHERE vehicle_id = $1 ORDER BY assigned_at DESC`, vehicleID)
+}
+
+func (r *DriverAssignmentRepository) ListByDriver(ctx context.Context, driverID uuid.UUID) ([]domain.DriverAssignment, error) {
+	return r.list(ctx, driverAssignmentSelect+` WHERE driver_id = $1 ORDER BY assigned_at DESC`, driverID)
+}
+
+func (r *DriverAssignmentRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.DriverAssignment, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query driver assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []domain.DriverAssignment{}
+	for rows.Next() {
+		assignment, err := scanDriverAssignment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan driver assignment: %w", err)
+		}
+		assignments = append(assignments, *assignment)
+	}
+	return assignments, rows.Err()
+}
+
+func (r *DriverAssignmentRepository) ActiveAt(ctx context.Context, vehicleID uuid.UUID, at time.Time) (*domain.DriverAssignment, error) {
+	row := r.db.QueryRowContext(ctx, driverAssignmentSelect+`
+		WHERE vehicle_id = $1 AND assigned_at <= $2 AND (unassigned_at IS NULL OR unassigned_at > $2)
+		ORDER BY assigned_at DESC
+		LIMIT 1`,
+		vehicleID, at,
+	)
+	assignment, err := scanDriverAssignment(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get driver assignment: %w", err)
+	}
+	return assignment, nil
+}
+
+func (r *DriverAssignmentRepository) Create(ctx context.Context, a *domain.DriverAssignment) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO driver_assignments (id, driver_id, vehicle_id, assigned_at, unassigned_at, assigned_by)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		a.ID, a.DriverID, a.VehicleID, a.AssignedAt, a.UnassignedAt, a.AssignedBy,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("insert driver assignment: %w", err)
+	}
+	return nil
+}
+
+func (r *DriverAssignmentRepository) Update(ctx context.Context, a *domain.DriverAssignment) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE driver_assignments SET assigned_at = $2, unassigned_at = $3
+		WHERE id = $1`,
+		a.ID, a.AssignedAt, a.UnassignedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update driver assignment: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanDriverAssignment(row rowScanner) (*domain.DriverAssignment, error) {
+	var (
+		a            domain.DriverAssignment
+		unassignedAt sql.NullTime
+		assignedBy   uuid.NullUUID
+	)
+
+	err := row.Scan(&a.ID, &a.DriverID, &a.VehicleID, &a.AssignedAt, &unassignedAt, &assignedBy)
+	if err != nil {
+		return nil, err
+	}
+	a.UnassignedAt = timePtr(unassignedAt)
+	a.AssignedBy = uuidPtr(assignedBy)
+	return &a, nil
+}
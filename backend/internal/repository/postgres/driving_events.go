@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// DrivingEventRepository is a PostgreSQL-backed
+// repository.DrivingEventRepository
+type DrivingEventRepository struct {
+	db *sql.DB
+}
+
+// NewDrivingEventRepository creates a new DrivingEventRepository
+func NewDrivingEventRepository(db *sql.DB) *DrivingEventRepository {
+	return &DrivingEventRepository{db: db}
+}
+
+func (r *DrivingEventRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]domain.DrivingEvent, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, driver_id, vehicle_id, type, occurred_at, value::float8, latitude::float8, longitude::float8
+		FROM driving_events
+		WHERE driver_id = $1 AND occurred_at >= $2 AND occurred_at < $3
+		ORDER BY occurred_at DESC`,
+		driverID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query driving events: %w", err)
+	}
+	defer rows.Close()
+
+	events := []domain.DrivingEvent{}
+	for rows.Next() {
+		var e domain.DrivingEvent
+		err := rows.Scan(
+			&e.ID, &e.DriverID, &e.VehicleID, &e.Type, &e.OccurredAt, &e.Value,
+			&e.Location.Lat, &e.Location.Lng,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan driving event: %w", err)
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *DrivingEventRepository) Create(ctx context.Context, e *domain.DrivingEvent) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO driving_events (id, driver_id, vehicle_id, type, occurred_at, value, latitude, longitude)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		e.ID, e.DriverID, e.VehicleID, string(e.Type), e.OccurredAt, e.Value,
+		e.Location.Lat, e.Location.Lng,
+	)
+	if err != nil {
+		return fmt.Errorf("insert driving event: %w", err)
+	}
+	return nil
+}
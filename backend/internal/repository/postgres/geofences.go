@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+const geofenceSelect = `
+	SELECT
+		id, name, type, center_lat::float8, center_lng::float8, radius_meters::float8, polygon,
+		COALESCE(is_active, true), alert_on_enter, alert_on_exit, created_at
+	FROM geofences`
+
+// GeofenceRepository is a PostgreSQL-backed repository.GeofenceRepository
+type GeofenceRepository struct {
+	db *sql.DB
+}
+
+// NewGeofenceRepository creates a new GeofenceRepository
+func NewGeofenceRepository(db *sql.DB) *GeofenceRepository {
+	return &GeofenceRepository{db: db}
+}
+
+func (r *GeofenceRepository) List(ctx context.Context) ([]domain.Geofence, error) {
+	rows, err := r.db.QueryContext(ctx, geofenceSelect+` ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("query geofences: %w", err)
+	}
+	defer rows.Close()
+
+	geofences := []domain.Geofence{}
+	for rows.Next() {
+		geofence, err := scanGeofence(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan geofence: %w", err)
+		}
+		geofences = append(geofences, *geofence)
+	}
+	return geofences, rows.Err()
+}
+
+func (r *GeofenceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Geofence, error) {
+	row := r.db.QueryRowContext(ctx, geofenceSelect+` WHERE id = $1`, id)
+	geofence, err := scanGeofence(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get geofence: %w", err)
+	}
+	return geofence, nil
+}
+
+func (r *GeofenceRepository) Create(ctx context.Context, g *domain.Geofence) error {
+	args, err := geofenceArgs(g)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO geofences (
+			id, name, type, center_lat, center_lng, radius_meters, polygon,
+			is_active, alert_on_enter, alert_on_exit, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		append(args, g.CreatedAt)...,
+	)
+	if err != nil {
+		return fmt.Errorf("insert geofence: %w", err)
+	}
+	return nil
+}
+
+func (r *GeofenceRepository) Update(ctx context.Context, g *domain.Geofence) error {
+	args, err := geofenceArgs(g)
+	if err != nil {
+		return err
+	}
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE geofences SET
+			name = $2, type = $3, center_lat = $4, center_lng = $5, radius_meters = $6,
+			polygon = $7, is_active = $8, alert_on_enter = $9, alert_on_exit = $10
+		WHERE id = $1`,
+		args...,
+	)
+	if err != nil {
+		return fmt.Errorf("update geofence: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *GeofenceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM geofences WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete geofence: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// geofenceArgs returns the column values shared by insert and update, in
+// table order starting with id
+func geofenceArgs(g *domain.Geofence) ([]interface{}, error) {
+	var centerLat, centerLng *float64
+	if g.Center != nil {
+		centerLat, centerLng = &g.Center.Lat, &g.Center.Lng
+	}
+
+	var polygon []byte
+	if len(g.Polygon) > 0 {
+		var err error
+		if polygon, err = json.Marshal(g.Polygon); err != nil {
+			return nil, fmt.Errorf("encode geofence polygon: %w", err)
+		}
+	}
+
+	return []interface{}{
+		g.ID, g.Name, g.Type, centerLat, centerLng, g.Radius, polygon,
+		g.IsActive, g.AlertOnEnter, g.AlertOnExit,
+	}, nil
+}
+
+func scanGeofence(row rowScanner) (*domain.Geofence, error) {
+	var (
+		g                    domain.Geofence
+		centerLat, centerLng sql.NullFloat64
+		radius               sql.NullFloat64
+		polygon              []byte
+	)
+
+	err := row.Scan(
+		&g.ID, &g.Name, &g.Type, &centerLat, &centerLng, &radius, &polygon,
+		&g.IsActive, &g.AlertOnEnter, &g.AlertOnExit, &g.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if centerLat.Valid && centerLng.Valid {
+		g.Center = &domain.Location{Lat: centerLat.Float64, Lng: centerLng.Float64}
+	}
+	if radius.Valid {
+		g.Radius = &radius.Float64
+	}
+	if len(polygon) > 0 {
+		if err := json.Unmarshal(polygon, &g.Polygon); err != nil {
+			return nil, fmt.Errorf("decode geofence polygon: %w", err)
+		}
+	}
+
+	return &g, nil
+}
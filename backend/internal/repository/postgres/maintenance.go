@@ -0,0 +1,259 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+const maintenanceSelect = `
+	SELECT
+		id, vehicle_id, plan_id, date, type, COALESCE(description, ''), COALESCE(cost, 0)::float8,
+		COALESCE(status, 'scheduled'), odometer, COALESCE(technician, ''), COALESCE(notes, ''),
+		completed_at, created_at
+	FROM maintenance_records`
+
+// MaintenanceRepository is a PostgreSQL-backed repository.MaintenanceRepository
+type MaintenanceRepository struct {
+	db *sql.DB
+}
+
+// NewMaintenanceRepository creates a new MaintenanceRepository
+func NewMaintenanceRepository(db *sql.DB) *MaintenanceRepository {
+	return &MaintenanceRepository{db: db}
+}
+
+func (r *MaintenanceRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.MaintenanceRecord, error) {
+	rows, err := r.db.QueryContext(ctx, maintenanceSelect+` WHERE vehicle_id = $1 ORDER BY date DESC`, vehicleID)
+	if err != nil {
+		return nil, fmt.Errorf("query maintenance records: %w", err)
+	}
+	defer rows.Close()
+
+	records := []domain.MaintenanceRecord{}
+	for rows.Next() {
+		record, err := scanMaintenanceRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan maintenance record: %w", err)
+		}
+		records = append(records, *record)
+	}
+	return records, rows.Err()
+}
+
+func (r *MaintenanceRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenanceRecord, error) {
+	row := r.db.QueryRowContext(ctx, maintenanceSelect+` WHERE id = $1`, id)
+	record, err := scanMaintenanceRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get maintenance record: %w", err)
+	}
+	return record, nil
+}
+
+func (r *MaintenanceRepository) Create(ctx context.Context, m *domain.MaintenanceRecord) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO maintenance_records (
+			id, vehicle_id, plan_id, date, type, description, cost,
+			status, odometer, technician, notes, completed_at, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`,
+		append(maintenanceArgs(m), m.CreatedAt)...,
+	)
+	if err != nil {
+		return fmt.Errorf("insert maintenance record: %w", err)
+	}
+	return nil
+}
+
+func (r *MaintenanceRepository) Update(ctx context.Context, m *domain.MaintenanceRecord) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE maintenance_records SET
+			vehicle_id = $2, plan_id = $3, date = $4, type = $5, description = $6, cost = $7,
+			status = $8, odometer = $9, technician = $10, notes = $11, completed_at = $12
+		WHERE id = $1`,
+		maintenanceArgs(m)...,
+	)
+	if err != nil {
+		return fmt.Errorf("update maintenance record: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MaintenanceRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM maintenance_records WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete maintenance record: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// maintenanceArgs returns the column values shared by insert and update, in
+// table order starting with id
+func maintenanceArgs(m *domain.MaintenanceRecord) []interface{} {
+	return []interface{}{
+		m.ID, m.VehicleID, m.PlanID, m.Date, m.Type, m.Description, m.Cost,
+		string(m.Status), m.Odometer, m.Technician, m.Notes, m.CompletedAt,
+	}
+}
+
+func scanMaintenanceRecord(row rowScanner) (*domain.MaintenanceRecord, error) {
+	var (
+		m           domain.MaintenanceRecord
+		planID      uuid.NullUUID
+		status      string
+		odometer    sql.NullInt64
+		completedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&m.ID, &m.VehicleID, &planID, &m.Date, &m.Type, &m.Description, &m.Cost,
+		&status, &odometer, &m.Technician, &m.Notes, &completedAt, &m.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	m.PlanID = uuidPtr(planID)
+	m.Status = domain.MaintenanceStatus(status)
+	m.Odometer = intPtr(odometer)
+	m.CompletedAt = timePtr(completedAt)
+	return &m, nil
+}
+
+const maintenancePlanSelect = `
+	SELECT
+		id, vehicle_id, type, COALESCE(description, ''), interval_km, interval_days,
+		last_service_date, last_service_odometer, due_since, is_active, created_at
+	FROM maintenance_plans`
+
+// MaintenancePlanRepository is a PostgreSQL-backed repository.MaintenancePlanRepository
+type MaintenancePlanRepository struct {
+	db *sql.DB
+}
+
+// NewMaintenancePlanRepository creates a new MaintenancePlanRepository
+func NewMaintenancePlanRepository(db *sql.DB) *MaintenancePlanRepository {
+	return &MaintenancePlanRepository{db: db}
+}
+
+func (r *MaintenancePlanRepository) List(ctx context.Context) ([]domain.MaintenancePlan, error) {
+	return r.list(ctx, maintenancePlanSelect+` ORDER BY created_at`)
+}
+
+func (r *MaintenancePlanRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.MaintenancePlan, error) {
+	return r.list(ctx, maintenancePlanSelect+` WHERE vehicle_id = $1 ORDER BY created_at`, vehicleID)
+}
+
+func (r *MaintenancePlanRepository) list(ctx context.Context, query string, args ...interface{}) ([]domain.MaintenancePlan, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query maintenance plans: %w", err)
+	}
+	defer rows.Close()
+
+	plans := []domain.MaintenancePlan{}
+	for rows.Next() {
+		plan, err := scanMaintenancePlan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan maintenance plan: %w", err)
+		}
+		plans = append(plans, *plan)
+	}
+	return plans, rows.Err()
+}
+
+func (r *MaintenancePlanRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenancePlan, error) {
+	row := r.db.QueryRowContext(ctx, maintenancePlanSelect+` WHERE id = $1`, id)
+	plan, err := scanMaintenancePlan(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get maintenance plan: %w", err)
+	}
+	return plan, nil
+}
+
+func (r *MaintenancePlanRepository) Create(ctx context.Context, p *domain.MaintenancePlan) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO maintenance_plans (
+			id, vehicle_id, type, description, interval_km, interval_days,
+			last_service_date, last_service_odometer, due_since, is_active, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+		append(maintenancePlanArgs(p), p.CreatedAt)...,
+	)
+	if err != nil {
+		return fmt.Errorf("insert maintenance plan: %w", err)
+	}
+	return nil
+}
+
+func (r *MaintenancePlanRepository) Update(ctx context.Context, p *domain.MaintenancePlan) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE maintenance_plans SET
+			vehicle_id = $2, type = $3, description = $4, interval_km = $5, interval_days = $6,
+			last_service_date = $7, last_service_odometer = $8, due_since = $9, is_active = $10
+		WHERE id = $1`,
+		maintenancePlanArgs(p)...,
+	)
+	if err != nil {
+		return fmt.Errorf("update maintenance plan: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func (r *MaintenancePlanRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM maintenance_plans WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete maintenance plan: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+// maintenancePlanArgs returns the column values shared by insert and update,
+// in table order starting with id
+func maintenancePlanArgs(p *domain.MaintenancePlan) []interface{} {
+	return []interface{}{
+		p.ID, p.VehicleID, p.Type, p.Description, p.IntervalKm, p.IntervalDays,
+		p.LastServiceDate, p.LastServiceOdometer, p.DueSince, p.IsActive,
+	}
+}
+
+func scanMaintenancePlan(row rowScanner) (*domain.MaintenancePlan, error) {
+	var (
+		p                        domain.MaintenancePlan
+		intervalKm, intervalDays sql.NullInt64
+		dueSince                 sql.NullTime
+	)
+
+	err := row.Scan(
+		&p.ID, &p.VehicleID, &p.Type, &p.Description, &intervalKm, &intervalDays,
+		&p.LastServiceDate, &p.LastServiceOdometer, &dueSince, &p.IsActive, &p.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	p.IntervalKm = intPtr(intervalKm)
+	p.IntervalDays = intPtr(intervalDays)
+	p.DueSince = timePtr(dueSince)
+	return &p, nil
+}
@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// VehicleStatusChangeRepository is a PostgreSQL-backed
+// repository.VehicleStatusChangeRepository
+type VehicleStatusChangeRepository struct {
+	db *sql.DB
+}
+
+// NewVehicleStatusChangeRepository creates a new
+// VehicleStatusChangeRepository
+func NewVehicleStatusChangeRepository(db *sql.DB) *VehicleStatusChangeRepository {
+	return &VehicleStatusChangeRepository{db: db}
+}
+
+const statusChangeColumns = `id, vehicle_id, previous_status, status, reason, changed_at`
+
+func (r *VehicleStatusChangeRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.VehicleStatusChange, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+statusChangeColumns+`
+		FROM vehicle_status_changes
+		WHERE vehicle_id = $1 AND changed_at >= $2 AND changed_at < $3
+		ORDER BY changed_at, recorded_at`,
+		vehicleID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query vehicle status changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []domain.VehicleStatusChange{}
+	for rows.Next() {
+		c, err := scanStatusChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan vehicle status change: %w", err)
+		}
+		changes = append(changes, *c)
+	}
+	return changes, rows.Err()
+}
+
+func (r *VehicleStatusChangeRepository) LastBefore(ctx context.Context, vehicleID uuid.UUID, at time.Time) (*domain.VehicleStatusChange, error) {
+	row := r.db.QueryRowContext(ctx, `
+		SELECT `+statusChangeColumns+`
+		FROM vehicle_status_changes
+		WHERE vehicle_id = $1 AND changed_at < $2
+		ORDER BY changed_at DESC, recorded_at DESC
+		LIMIT 1`,
+		vehicleID, at,
+	)
+	c, err := scanStatusChange(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get vehicle status change: %w", err)
+	}
+	return c, nil
+}
+
+func (r *VehicleStatusChangeRepository) Create(ctx context.Context, c *domain.VehicleStatusChange) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO vehicle_status_changes (`+statusChangeColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		c.ID, c.VehicleID, string(c.PreviousStatus), string(c.Status), nullString(c.Reason), c.ChangedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert vehicle status change: %w", err)
+	}
+	return nil
+}
+
+func scanStatusChange(row rowScanner) (*domain.VehicleStatusChange, error) {
+	var (
+		c              domain.VehicleStatusChange
+		previous, next string
+		reason         sql.NullString
+	)
+
+	err := row.Scan(&c.ID, &c.VehicleID, &previous, &next, &reason, &c.ChangedAt)
+	if err != nil {
+		return nil, err
+	}
+	c.PreviousStatus = domain.VehicleStatus(previous)
+	c.Status = domain.VehicleStatus(next)
+	c.Reason = reason.String
+	return &c, nil
+}
@@ -0,0 +1,421 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+const telemetryInsert = `
+	INSERT INTO telemetry (
+		id, vehicle_id, timestamp, latitude, longitude, speed,
+		battery_level, fuel_level, engine_temp, engine_rpm, heading, ignition, charging_current
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+// TelemetryRepository is a PostgreSQL-backed repository.TelemetryRepository
+type TelemetryRepository struct {
+	db *sql.DB
+}
+
+// NewTelemetryRepository creates a new TelemetryRepository
+func NewTelemetryRepository(db *sql.DB) *TelemetryRepository {
+	return &TelemetryRepository{db: db}
+}
+
+func (r *TelemetryRepository) Insert(ctx context.Context, t *domain.Telemetry) error {
+	if _, err := r.db.ExecContext(ctx, telemetryInsert, telemetryArgs(t)...); err != nil {
+		return fmt.Errorf("insert telemetry: %w", err)
+	}
+	return nil
+}
+
+func (r *TelemetryRepository) InsertBatch(ctx context.Context, telemetry []domain.Telemetry) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin telemetry batch: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, telemetryInsert)
+	if err != nil {
+		return fmt.Errorf("prepare telemetry batch: %w", err)
+	}
+	defer stmt.Close()
+
+	for i := range telemetry {
+		if _, err := stmt.ExecContext(ctx, telemetryArgs(&telemetry[i])...); err != nil {
+			return fmt.Errorf("insert telemetry batch: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *TelemetryRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.Telemetry, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			id, vehicle_id, timestamp, COALESCE(latitude, 0)::float8, COALESCE(longitude, 0)::float8,
+			COALESCE(speed, 0)::float8, COALESCE(battery_level, 0), fuel_level,
+			COALESCE(engine_temp, 0)::float8, COALESCE(engine_rpm, 0), COALESCE(heading, 0)::float8,
+			ignition, charging_current::float8
+		FROM telemetry
+		WHERE vehicle_id = $1 AND timestamp >= $2 AND timestamp < $3
+		ORDER BY timestamp`,
+		vehicleID, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query telemetry: %w", err)
+	}
+	defer rows.Close()
+
+	telemetry := []domain.Telemetry{}
+	for rows.Next() {
+		var (
+			t                          domain.Telemetry
+			speed, engineTemp, heading float64
+			fuelLevel                  sql.NullInt64
+			ignition                   sql.NullBool
+			chargingCurrent            sql.NullFloat64
+		)
+		err := rows.Scan(
+			&t.ID, &t.VehicleID, &t.Timestamp, &t.Location.Lat, &t.Location.Lng,
+			&speed, &t.BatteryLevel, &fuelLevel, &engineTemp, &t.EngineRPM, &heading,
+			&ignition, &chargingCurrent,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan telemetry: %w", err)
+		}
+		t.Speed = float32(speed)
+		t.EngineTemp = float32(engineTemp)
+		t.Heading = float32(heading)
+		t.FuelLevel = intPtr(fuelLevel)
+		if ignition.Valid {
+			t.Ignition = &ignition.Bool
+		}
+		if chargingCurrent.Valid {
+			current := float32(chargingCurrent.Float64)
+			t.ChargingCurrent = &current
+		}
+		telemetry = append(telemetry, t)
+	}
+	return telemetry, rows.Err()
+}
+
+// telemetryAggregate buckets the movement between consecutive records of
+// each vehicle, using the haversine distance as domain.DistanceMeters does
+const telemetryAggregate = `
+	WITH points AS (
+		SELECT
+			vehicle_id, timestamp, battery_level,
+			latitude::float8 AS lat, longitude::float8 AS lng,
+			LAG(latitude::float8) OVER w AS prev_lat,
+			LAG(longitude::float8) OVER w AS prev_lng,
+			LAG(battery_level) OVER w AS prev_battery
+		FROM telemetry
+		WHERE timestamp >= $1 AND timestamp < $2
+			AND ($3::uuid[] IS NULL OR vehicle_id = ANY($3::uuid[]))
+		WINDOW w AS (PARTITION BY vehicle_id ORDER BY timestamp)
+	)
+	SELECT
+		vehicle_id,
+		date_trunc($4, timestamp, $5) AS bucket,
+		COALESCE(SUM(2 * 6371.0 * asin(sqrt(
+			power(sin(radians(lat - prev_lat) / 2), 2) +
+			cos(radians(prev_lat)) * cos(radians(lat)) * power(sin(radians(lng - prev_lng) / 2), 2)
+		))), 0) AS distance_km,
+		COALESCE(SUM(GREATEST(prev_battery - battery_level, 0)), 0)::float8 AS energy_used,
+		COUNT(*) AS samples
+	FROM points
+	GROUP BY vehicle_id, bucket
+	ORDER BY bucket, vehicle_id`
+
+func (r *TelemetryRepository) Aggregate(ctx context.Context, query repository.TelemetryAggregate) ([]domain.TelemetryBucket, error) {
+	var vehicleIDs []string
+	for _, id := range query.VehicleIDs {
+		vehicleIDs = append(vehicleIDs, id.String())
+	}
+
+	rows, err := r.db.QueryContext(ctx, telemetryAggregate,
+		query.From, query.To, vehicleIDs, string(query.Bucket), query.Location.String(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate telemetry: %w", err)
+	}
+	defer rows.Close()
+
+	buckets := []domain.TelemetryBucket{}
+	for rows.Next() {
+		var b domain.TelemetryBucket
+		if err := rows.Scan(&b.VehicleID, &b.Start, &b.DistanceKm, &b.EnergyUsed, &b.Samples); err != nil {
+			return nil, fmt.Errorf("scan telemetry bucket: %w", err)
+		}
+		b.Start = b.Start.In(query.Location)
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// telemetryRollup recomputes the rollups of one resolution over a range of
+// buckets. Buckets are aligned on the hour, as time.Truncate aligns them for
+// every rollup resolution, and movement across gaps longer than
+// domain.RollupMaxGap is ignored. Intervals are passed in seconds.
+const telemetryRollup = `
+	WITH points AS (
+		SELECT
+			vehicle_id, timestamp, latitude::float8 AS lat, longitude::float8 AS lng,
+			speed::float8 AS speed, battery_level, engine_temp::float8 AS engine_temp,
+			LAG(timestamp) OVER w AS prev_timestamp,
+			LAG(latitude::float8) OVER w AS prev_lat,
+			LAG(longitude::float8) OVER w AS prev_lng
+		FROM telemetry
+		WHERE timestamp >= $2::timestamptz - $4 * interval '1 second' AND timestamp < $3
+		WINDOW w AS (PARTITION BY vehicle_id ORDER BY timestamp)
+	)
+	INSERT INTO telemetry_rollups (
+		vehicle_id, resolution, bucket_start, samples, latitude, longitude,
+		speed_min, speed_max, speed_avg, battery_min, battery_max, battery_avg,
+		engine_temp_min, engine_temp_max, engine_temp_avg, distance_km
+	)
+	SELECT
+		vehicle_id, $1, date_bin($5 * interval '1 second', timestamp, TIMESTAMPTZ 'epoch') AS bucket, COUNT(*),
+		(array_agg(lat ORDER BY timestamp DESC))[1], (array_agg(lng ORDER BY timestamp DESC))[1],
+		MIN(speed), MAX(speed), AVG(speed),
+		MIN(battery_level), MAX(battery_level), AVG(battery_level),
+		MIN(engine_temp), MAX(engine_temp), AVG(engine_temp),
+		COALESCE(SUM(2 * 6371.0 * asin(sqrt(
+			power(sin(radians(lat - prev_lat) / 2), 2) +
+			cos(radians(prev_lat)) * cos(radians(lat)) * power(sin(radians(lng - prev_lng) / 2), 2)
+		))) FILTER (WHERE timestamp - prev_timestamp <= $4 * interval '1 second'), 0)
+	FROM points
+	WHERE timestamp >= $2
+	GROUP BY vehicle_id, bucket
+	ON CONFLICT (vehicle_id, resolution, bucket_start) DO UPDATE SET
+		samples = EXCLUDED.samples,
+		latitude = EXCLUDED.latitude,
+		longitude = EXCLUDED.longitude,
+		speed_min = EXCLUDED.speed_min,
+		speed_max = EXCLUDED.speed_max,
+		speed_avg = EXCLUDED.speed_avg,
+		battery_min = EXCLUDED.battery_min,
+		battery_max = EXCLUDED.battery_max,
+		battery_avg = EXCLUDED.battery_avg,
+		engine_temp_min = EXCLUDED.engine_temp_min,
+		engine_temp_max = EXCLUDED.engine_temp_max,
+		engine_temp_avg = EXCLUDED.engine_temp_avg,
+		distance_km = EXCLUDED.distance_km`
+
+func (r *TelemetryRepository) Rollup(ctx context.Context, resolution domain.Resolution, from, to time.Time) (int, error) {
+	interval := resolution.Interval()
+	result, err := r.db.ExecContext(ctx, telemetryRollup,
+		string(resolution), from.Truncate(interval), to.Truncate(interval),
+		int(domain.RollupMaxGap.Seconds()), int(interval.Seconds()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("roll up telemetry: %w", err)
+	}
+	n, err := result.RowsAffected()
+	return int(n), err
+}
+
+func (r *TelemetryRepository) ListRollups(ctx context.Context, vehicleID uuid.UUID, resolution domain.Resolution, from, to time.Time) ([]domain.TelemetryRollup, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			bucket_start, samples, COALESCE(latitude, 0)::float8, COALESCE(longitude, 0)::float8,
+			COALESCE(speed_avg, 0)::float8, COALESCE(speed_min, 0)::float8, COALESCE(speed_max, 0)::float8,
+			COALESCE(battery_avg, 0)::float8, COALESCE(battery_min, 0), COALESCE(battery_max, 0),
+			COALESCE(engine_temp_avg, 0)::float8, COALESCE(engine_temp_min, 0)::float8, COALESCE(engine_temp_max, 0)::float8,
+			distance_km::float8
+		FROM telemetry_rollups
+		WHERE vehicle_id = $1 AND resolution = $2 AND bucket_start >= $3 AND bucket_start < $4
+		ORDER BY bucket_start`,
+		vehicleID, string(resolution), from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query telemetry rollups: %w", err)
+	}
+	defer rows.Close()
+
+	rollups := []domain.TelemetryRollup{}
+	for rows.Next() {
+		var (
+			rollup                                   domain.TelemetryRollup
+			speed, speedMin, speedMax, battery       float64
+			engineTemp, engineTempMin, engineTempMax float64
+		)
+		err := rows.Scan(
+			&rollup.Timestamp, &rollup.Samples, &rollup.Location.Lat, &rollup.Location.Lng,
+			&speed, &speedMin, &speedMax,
+			&battery, &rollup.BatteryMin, &rollup.BatteryMax,
+			&engineTemp, &engineTempMin, &engineTempMax,
+			&rollup.DistanceKm,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scan telemetry rollup: %w", err)
+		}
+		rollup.VehicleID = vehicleID
+		rollup.Resolution = resolution
+		rollup.Speed, rollup.SpeedMin, rollup.SpeedMax = float32(speed), float32(speedMin), float32(speedMax)
+		rollup.BatteryLevel = float32(battery)
+		rollup.EngineTemp, rollup.EngineTempMin, rollup.EngineTempMax = float32(engineTemp), float32(engineTempMin), float32(engineTempMax)
+		rollups = append(rollups, rollup)
+	}
+	return rollups, rows.Err()
+}
+
+func (r *TelemetryRepository) RollupWatermark(ctx context.Context, resolution domain.Resolution) (time.Time, error) {
+	var watermark sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(
+			(SELECT MAX(bucket_start) FROM telemetry_rollups WHERE resolution = $1),
+			(SELECT MIN(timestamp) FROM telemetry)
+		)`,
+		string(resolution),
+	).Scan(&watermark)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("query rollup watermark: %w", err)
+	}
+	return watermark.Time, nil
+}
+
+// pruneBatch bounds the rows deleted by one statement so pruning a backlog
+// doesn't hold locks on the table for long
+const pruneBatch = 10000
+
+func (r *TelemetryRepository) Prune(ctx context.Context, tier domain.Resolution, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM telemetry WHERE ctid = ANY(ARRAY(
+			SELECT ctid FROM telemetry WHERE timestamp < $1 LIMIT $2
+		))`
+	args := []interface{}{before, pruneBatch}
+	if tier != domain.ResolutionRaw {
+		query = `
+			DELETE FROM telemetry_rollups WHERE ctid = ANY(ARRAY(
+				SELECT ctid FROM telemetry_rollups WHERE bucket_start < $1 AND resolution = $3 LIMIT $2
+			))`
+		args = append(args, string(tier))
+	}
+
+	var deleted int64
+	for {
+		result, err := r.db.ExecContext(ctx, query, args...)
+		if err != nil {
+			return deleted, fmt.Errorf("prune %s telemetry: %w", tier, err)
+		}
+		n, err := result.RowsAffected()
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n
+		if n < pruneBatch {
+			return deleted, nil
+		}
+	}
+}
+
+// Storage estimates each vehicle's share of the on-disk size of the telemetry
+// tables, indexes included, from its share of their rows
+func (r *TelemetryRepository) Storage(ctx context.Context) ([]domain.TelemetryStorage, error) {
+	var recordBytes, rollupBytes int64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT pg_total_relation_size('telemetry'), pg_total_relation_size('telemetry_rollups')`,
+	).Scan(&recordBytes, &rollupBytes)
+	if err != nil {
+		return nil, fmt.Errorf("query telemetry size: %w", err)
+	}
+
+	byVehicle := make(map[uuid.UUID]*domain.TelemetryStorage)
+	var vehicleIDs []uuid.UUID
+	storageOf := func(id uuid.UUID) *domain.TelemetryStorage {
+		s, ok := byVehicle[id]
+		if !ok {
+			s = &domain.TelemetryStorage{VehicleID: id, Records: make(map[domain.Resolution]int64)}
+			byVehicle[id] = s
+			vehicleIDs = append(vehicleIDs, id)
+		}
+		return s
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT vehicle_id, COUNT(*), MIN(timestamp), MAX(timestamp)
+		FROM telemetry
+		GROUP BY vehicle_id`)
+	if err != nil {
+		return nil, fmt.Errorf("query telemetry storage: %w", err)
+	}
+	defer rows.Close()
+
+	var totalRecords int64
+	for rows.Next() {
+		var (
+			id             uuid.UUID
+			n              int64
+			oldest, newest time.Time
+		)
+		if err := rows.Scan(&id, &n, &oldest, &newest); err != nil {
+			return nil, fmt.Errorf("scan telemetry storage: %w", err)
+		}
+		s := storageOf(id)
+		s.Records[domain.ResolutionRaw] = n
+		s.OldestRecord, s.NewestRecord = &oldest, &newest
+		totalRecords += n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	rollupRows, err := r.db.QueryContext(ctx, `
+		SELECT vehicle_id, resolution, COUNT(*)
+		FROM telemetry_rollups
+		GROUP BY vehicle_id, resolution`)
+	if err != nil {
+		return nil, fmt.Errorf("query rollup storage: %w", err)
+	}
+	defer rollupRows.Close()
+
+	var totalRollups int64
+	rollupsOf := make(map[uuid.UUID]int64)
+	for rollupRows.Next() {
+		var (
+			id         uuid.UUID
+			resolution string
+			n          int64
+		)
+		if err := rollupRows.Scan(&id, &resolution, &n); err != nil {
+			return nil, fmt.Errorf("scan rollup storage: %w", err)
+		}
+		storageOf(id).Records[domain.Resolution(resolution)] = n
+		rollupsOf[id] += n
+		totalRollups += n
+	}
+	if err := rollupRows.Err(); err != nil {
+		return nil, err
+	}
+
+	storage := make([]domain.TelemetryStorage, 0, len(vehicleIDs))
+	for _, id := range vehicleIDs {
+		s := byVehicle[id]
+		if totalRecords > 0 {
+			s.EstimatedBytes += int64(float64(recordBytes) * float64(s.Records[domain.ResolutionRaw]) / float64(totalRecords))
+		}
+		if totalRollups > 0 {
+			s.EstimatedBytes += int64(float64(rollupBytes) * float64(rollupsOf[id]) / float64(totalRollups))
+		}
+		storage = append(storage, *s)
+	}
+	sort.Slice(storage, func(i, j int) bool { return storage[i].EstimatedBytes > storage[j].EstimatedBytes })
+	return storage, nil
+}
+
+func telemetryArgs(t *domain.Telemetry) []interface{} {
+	return []interface{}{
+		t.ID, t.VehicleID, t.Timestamp, t.Location.Lat, t.Location.Lng, t.Speed,
+		t.BatteryLevel, t.FuelLevel, t.EngineTemp, t.EngineRPM, t.Heading, t.Ignition,
+		t.ChargingCurrent,
+	}
+}
@@ -0,0 +1,129 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+const tripColumns = `
+		id, vehicle_id, driver_id, started_at, ended_at, end_reason,
+		start_latitude::float8, start_longitude::float8, end_latitude::float8, end_longitude::float8,
+		distance_km::float8, duration_seconds, energy_used, fuel_used, max_speed::float8, avg_speed::float8`
+
+// TripRepository is a PostgreSQL-backed repository.TripRepository
+type TripRepository struct {
+	db *sql.DB
+}
+
+// NewTripRepository creates a new TripRepository
+func NewTripRepository(db *sql.DB) *TripRepository {
+	return &TripRepository{db: db}
+}
+
+func (r *TripRepository) ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.Trip, error) {
+	return r.list(ctx, `vehicle_id = $1`, vehicleID, from, to)
+}
+
+func (r *TripRepository) ListByDriver(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]domain.Trip, error) {
+	return r.list(ctx, `driver_id = $1`, driverID, from, to)
+}
+
+// list returns the trips matching where, with id bound to $1, started
+// within [from, to)
+func (r *TripRepository) list(ctx context.Context, where string, id uuid.UUID, from, to time.Time) ([]domain.Trip, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT`+tripColumns+`
+		FROM trips
+		WHERE `+where+` AND started_at >= $2 AND started_at < $3
+		ORDER BY started_at DESC`,
+		id, from, to,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query trips: %w", err)
+	}
+	defer rows.Close()
+
+	trips := []domain.Trip{}
+	for rows.Next() {
+		trip, err := scanTrip(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan trip: %w", err)
+		}
+		trips = append(trips, *trip)
+	}
+	return trips, rows.Err()
+}
+
+func (r *TripRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Trip, error) {
+	var path []byte
+	row := r.db.QueryRowContext(ctx, `SELECT`+tripColumns+`, path FROM trips WHERE id = $1`, id)
+	trip, err := scanTrip(row, &path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get trip: %w", err)
+	}
+	if err := json.Unmarshal(path, &trip.Path); err != nil {
+		return nil, fmt.Errorf("decode trip path: %w", err)
+	}
+	return trip, nil
+}
+
+func (r *TripRepository) Create(ctx context.Context, t *domain.Trip) error {
+	path, err := json.Marshal(t.Path)
+	if err != nil {
+		return fmt.Errorf("encode trip path: %w", err)
+	}
+
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO trips (
+			id, vehicle_id, driver_id, started_at, ended_at, end_reason,
+			start_latitude, start_longitude, end_latitude, end_longitude,
+			distance_km, duration_seconds, energy_used, fuel_used, max_speed, avg_speed, path
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)`,
+		t.ID, t.VehicleID, t.DriverID, t.StartedAt, t.EndedAt, t.EndReason,
+		t.StartLocation.Lat, t.StartLocation.Lng, t.EndLocation.Lat, t.EndLocation.Lng,
+		t.DistanceKm, t.DurationSeconds, t.EnergyUsed, t.FuelUsed, t.MaxSpeed, t.AvgSpeed, path,
+	)
+	if err != nil {
+		return fmt.Errorf("insert trip: %w", err)
+	}
+	return nil
+}
+
+// scanTrip scans tripColumns followed by any extra destinations, such as the
+// path
+func scanTrip(row rowScanner, extra ...interface{}) (*domain.Trip, error) {
+	var (
+		t                  domain.Trip
+		driverID           uuid.NullUUID
+		endedAt            time.Time
+		fuelUsed           sql.NullInt64
+		maxSpeed, avgSpeed float64
+	)
+
+	dest := []interface{}{
+		&t.ID, &t.VehicleID, &driverID, &t.StartedAt, &endedAt, &t.EndReason,
+		&t.StartLocation.Lat, &t.StartLocation.Lng, &t.EndLocation.Lat, &t.EndLocation.Lng,
+		&t.DistanceKm, &t.DurationSeconds, &t.EnergyUsed, &fuelUsed, &maxSpeed, &avgSpeed,
+	}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+
+	t.Status = domain.TripStatusCompleted
+	t.DriverID = uuidPtr(driverID)
+	t.EndedAt = &endedAt
+	t.FuelUsed = intPtr(fuelUsed)
+	t.MaxSpeed = float32(maxSpeed)
+	t.AvgSpeed = float32(avgSpeed)
+	return &t, nil
+}
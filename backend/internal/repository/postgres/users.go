@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+const userSelect = `
+	SELECT id, email, name, role, is_active, created_at, updated_at
+	FROM users`
+
+// UserRepository is a PostgreSQL-backed repository.UserRepository
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new UserRepository
+func NewUserRepository(db *sql.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) List(ctx context.Context) ([]domain.User, error) {
+	rows, err := r.db.QueryContext(ctx, userSelect+` ORDER BY email`)
+	if err != nil {
+		return nil, fmt.Errorf("query users: %w", err)
+	}
+	defer rows.Close()
+
+	users := []domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan user: %w", err)
+		}
+		users = append(users, *user)
+	}
+	return users, rows.Err()
+}
+
+func (r *UserRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	row := r.db.QueryRowContext(ctx, userSelect+` WHERE id = $1`, id)
+	user, err := scanUser(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user: %w", err)
+	}
+	return user, nil
+}
+
+func (r *UserRepository) Create(ctx context.Context, u *domain.User) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO users (id, email, name, role, is_active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		u.ID, u.Email, u.Name, u.Role, u.IsActive, u.CreatedAt, u.UpdatedAt,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("insert user: %w", err)
+	}
+	return nil
+}
+
+func (r *UserRepository) Update(ctx context.Context, u *domain.User) error {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE users SET email = $2, name = $3, role = $4, is_active = $5
+		WHERE id = $1`,
+		u.ID, u.Email, u.Name, u.Role, u.IsActive,
+	)
+	if isUniqueViolation(err) {
+		return domain.ErrConflict
+	}
+	if err != nil {
+		return fmt.Errorf("update user: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func scanUser(row rowScanner) (*domain.User, error) {
+	var u domain.User
+	err := row.Scan(&u.ID, &u.Email, &u.Name, &u.Role, &u.IsActive, &u.CreatedAt, &u.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
@@ -0,0 +1,206 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+const vehicleSelect = `
+	SELECT
+		v.id, v.vin, v.name, v.model, v.brand, COALESCE(v.image, ''), COALESCE(v.status, 'idle'),
+		COALESCE(v.battery_level, 0), v.fuel_level, COALESCE(v.range_km, 0),
+		COALESCE(v.latitude, 0)::float8, COALESCE(v.longitude, 0)::float8, COALESCE(v.address, ''),
+		COALESCE(v.speed, 0)::float8, v.driver_id, COALESCE(v.temperature, 0)::float8,
+		COALESCE(v.odometer, 0), COALESCE(v.efficiency, ''), v.created_at, v.updated_at,
+		d.id, COALESCE(d.name, ''), COALESCE(d.email, ''), COALESCE(d.phone, ''),
+		COALESCE(d.avatar, ''), COALESCE(d.rating, 0)::float8, d.created_at, d.updated_at
+	FROM vehicles v
+	LEFT JOIN drivers d ON d.id = v.driver_id`
+
+// VehicleRepository is a PostgreSQL-backed repository.VehicleRepository
+type VehicleRepository struct {
+	db *sql.DB
+}
+
+// NewVehicleRepository creates a new VehicleRepository
+func NewVehicleRepository(db *sql.DB) *VehicleRepository {
+	return &VehicleRepository{db: db}
+}
+
+func (r *VehicleRepository) List(ctx context.Context) ([]domain.Vehicle, error) {
+	return r.query(ctx, vehicleSelect+` ORDER BY v.name`)
+}
+
+func (r *VehicleRepository) ListByStatus(ctx context.Context, status domain.VehicleStatus) ([]domain.Vehicle, error) {
+	return r.query(ctx, vehicleSelect+` WHERE v.status = $1 ORDER BY v.name`, string(status))
+}
+
+func (r *VehicleRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
+	row := r.db.QueryRowContext(ctx, vehicleSelect+` WHERE v.id = $1`, id)
+	vehicle, err := scanVehicle(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get vehicle: %w", err)
+	}
+	return vehicle, nil
+}
+
+func (r *VehicleRepository) Create(ctx context.Context, vehicle *domain.Vehicle) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO vehicles (
+			id, vin, name, model, brand, image, status, battery_level, fuel_level, range_km,
+			latitude, longitude, address, speed, driver_id, temperature, odometer, efficiency,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
+		vehicle.ID, vehicle.VIN, vehicle.Name, vehicle.Model, vehicle.Brand, vehicle.Image,
+		string(vehicle.Status), vehicle.BatteryLevel, vehicle.FuelLevel, vehicle.Range,
+		vehicle.Location.Lat, vehicle.Location.Lng, vehicle.Location.Address, vehicle.Speed,
+		vehicle.DriverID, vehicle.Temperature, vehicle.Odometer, vehicle.Efficiency,
+		vehicle.CreatedAt, vehicle.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("insert vehicle: %w", err)
+	}
+	return nil
+}
+
+func (r *VehicleRepository) Update(ctx context.Context, vehicle *domain.Vehicle) error {
+	err := r.db.QueryRowContext(ctx, `
+		UPDATE vehicles SET
+			vin = $2, name = $3, model = $4, brand = $5, image = $6, status = $7,
+			battery_level = $8, fuel_level = $9, range_km = $10, latitude = $11, longitude = $12,
+			address = $13, speed = $14, driver_id = $15, temperature = $16, odometer = $17,
+			efficiency = $18
+		WHERE id = $1
+		RETURNING updated_at`,
+		vehicle.ID, vehicle.VIN, vehicle.Name, vehicle.Model, vehicle.Brand, vehicle.Image,
+		string(vehicle.Status), vehicle.BatteryLevel, vehicle.FuelLevel, vehicle.Range,
+		vehicle.Location.Lat, vehicle.Location.Lng, vehicle.Location.Address, vehicle.Speed,
+		vehicle.DriverID, vehicle.Temperature, vehicle.Odometer, vehicle.Efficiency,
+	).Scan(&vehicle.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("update vehicle: %w", err)
+	}
+	return nil
+}
+
+func (r *VehicleRepository) UpdateLiveState(ctx context.Context, id uuid.UUID, state repository.VehicleLiveState) (*domain.Vehicle, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE vehicles SET
+			status = CASE WHEN status = $2 THEN $3 ELSE status END,
+			latitude = $4, longitude = $5, address = $6, speed = $7, battery_level = $8,
+			fuel_level = COALESCE($9, fuel_level), temperature = $10,
+			odometer = COALESCE(odometer, 0) + $11
+		WHERE id = $1`,
+		id, string(state.From), string(state.Status),
+		state.Location.Lat, state.Location.Lng, state.Location.Address, state.Speed,
+		state.BatteryLevel, state.FuelLevel, state.Temperature, state.DistanceKm,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update vehicle live state: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *VehicleRepository) SetStatus(ctx context.Context, id uuid.UUID, from, to domain.VehicleStatus) (*domain.Vehicle, error) {
+	result, err := r.db.ExecContext(ctx,
+		`UPDATE vehicles SET status = $3 WHERE id = $1 AND status = $2`,
+		id, string(from), string(to),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("set vehicle status: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		if _, err := r.GetByID(ctx, id); err != nil {
+			return nil, err
+		}
+		return nil, domain.ErrConflict
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *VehicleRepository) SetDriver(ctx context.Context, id uuid.UUID, driverID *uuid.UUID) (*domain.Vehicle, error) {
+	result, err := r.db.ExecContext(ctx, `UPDATE vehicles SET driver_id = $2 WHERE id = $1`, id, driverID)
+	if err != nil {
+		return nil, fmt.Errorf("set vehicle driver: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *VehicleRepository) query(ctx context.Context, query string, args ...interface{}) ([]domain.Vehicle, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query vehicles: %w", err)
+	}
+	defer rows.Close()
+
+	vehicles := []domain.Vehicle{}
+	for rows.Next() {
+		vehicle, err := scanVehicle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan vehicle: %w", err)
+		}
+		vehicles = append(vehicles, *vehicle)
+	}
+	return vehicles, rows.Err()
+}
+
+func scanVehicle(row rowScanner) (*domain.Vehicle, error) {
+	var (
+		v                      domain.Vehicle
+		status                 string
+		fuelLevel              sql.NullInt64
+		speed, temperature     float64
+		driverID               uuid.NullUUID
+		d                      domain.Driver
+		dID                    uuid.NullUUID
+		dRating                float64
+		dCreatedAt, dUpdatedAt sql.NullTime
+	)
+
+	err := row.Scan(
+		&v.ID, &v.VIN, &v.Name, &v.Model, &v.Brand, &v.Image, &status,
+		&v.BatteryLevel, &fuelLevel, &v.Range,
+		&v.Location.Lat, &v.Location.Lng, &v.Location.Address,
+		&speed, &driverID, &temperature,
+		&v.Odometer, &v.Efficiency, &v.CreatedAt, &v.UpdatedAt,
+		&dID, &d.Name, &d.Email, &d.Phone,
+		&d.Avatar, &dRating, &dCreatedAt, &dUpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	v.Status = domain.VehicleStatus(status)
+	v.FuelLevel = intPtr(fuelLevel)
+	v.Speed = float32(speed)
+	v.Temperature = float32(temperature)
+	v.DriverID = uuidPtr(driverID)
+
+	if dID.Valid {
+		d.ID = dID.UUID
+		d.Rating = float32(dRating)
+		d.CreatedAt = dCreatedAt.Time
+		d.UpdatedAt = dUpdatedAt.Time
+		v.Driver = &d
+	}
+
+	return &v, nil
+}
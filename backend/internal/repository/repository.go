@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// Repositories return domain.ErrNotFound when a lookup by ID matches nothing.
+
+// VehicleRepository persists fleet vehicles
+type VehicleRepository interface {
+	List(ctx context.Context) ([]domain.Vehicle, error)
+	ListByStatus(ctx context.Context, status domain.VehicleStatus) ([]domain.Vehicle, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error)
+	Create(ctx context.Context, vehicle *domain.Vehicle) error
+	Update(ctx context.Context, vehicle *domain.Vehicle) error
+	// UpdateLiveState saves the fields of a vehicle its telemetry feeds,
+	// leaving the others as stored, and returns the vehicle as saved
+	UpdateLiveState(ctx context.Context, id uuid.UUID, state VehicleLiveState) (*domain.Vehicle, error)
+	// SetStatus moves a vehicle from one status to another, leaving its
+	// other fields as stored, and returns the vehicle as saved. It returns
+	// domain.ErrConflict when the stored status is no longer from.
+	SetStatus(ctx context.Context, id uuid.UUID, from, to domain.VehicleStatus) (*domain.Vehicle, error)
+	// SetDriver sets or, with nil, clears a vehicle's driver, leaving its
+	// other fields as stored, and returns the vehicle as saved
+	SetDriver(ctx context.Context, id uuid.UUID, driverID *uuid.UUID) (*domain.Vehicle, error)
+}
+
+// VehicleLiveState is the part of a vehicle fed by its telemetry
+type VehicleLiveState struct {
+	// Status replaces the stored status only while that is still From, so
+	// a status set meanwhile, such as maintenance, is kept
+	From         domain.VehicleStatus
+	Status       domain.VehicleStatus
+	Location     domain.Location
+	Speed        float32
+	BatteryLevel int
+	FuelLevel    *int // kept as stored when nil
+	Temperature  float32
+	DistanceKm   int // added to the odometer
+}
+
+// AlertFilter narrows alert queries
+type AlertFilter struct {
+	Status    *domain.AlertStatus
+	Severity  *domain.AlertSeverity
+	VehicleID *uuid.UUID
+	Limit     int
+	Offset    int
+}
+
+// AlertRepository persists alerts
+type AlertRepository interface {
+	List(ctx context.Context, filter AlertFilter) ([]domain.Alert, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Alert, error)
+	Create(ctx context.Context, alert *domain.Alert) error
+	Update(ctx context.Context, alert *domain.Alert) error
+}
+
+// TelemetryAggregate selects and buckets telemetry for analytics
+type TelemetryAggregate struct {
+	VehicleIDs []uuid.UUID // every vehicle when empty
+	From       time.Time
+	To         time.Time
+	Bucket     domain.Bucket
+	Location   *time.Location // time zone of day, week and month boundaries
+}
+
+// TelemetryRepository persists time-series telemetry points
+type TelemetryRepository interface {
+	Insert(ctx context.Context, telemetry *domain.Telemetry) error
+	InsertBatch(ctx context.Context, telemetry []domain.Telemetry) error
+	ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.Telemetry, error)
+	// Aggregate returns one bucket per vehicle and bucket with records,
+	// ordered by bucket start
+	Aggregate(ctx context.Context, query TelemetryAggregate) ([]domain.TelemetryBucket, error)
+
+	// Rollup recomputes, from the raw records, the rollups of resolution
+	// for every bucket starting within [from, to) and returns how many were
+	// written
+	Rollup(ctx context.Context, resolution domain.Resolution, from, to time.Time) (int, error)
+	ListRollups(ctx context.Context, vehicleID uuid.UUID, resolution domain.Resolution, from, to time.Time) ([]domain.TelemetryRollup, error)
+	// RollupWatermark returns the start of the newest stored rollup of
+	// resolution or, when there is none, the time of the oldest raw record.
+	// It is zero when there is no telemetry at all.
+	RollupWatermark(ctx context.Context, resolution domain.Resolution) (time.Time, error)
+
+	// Prune deletes the records of a tier, raw or a rollup resolution, older
+	// than before and returns how many were deleted
+	Prune(ctx context.Context, tier domain.Resolution, before time.Time) (int64, error)
+	// Storage returns the telemetry stored for each vehicle that has any
+	Storage(ctx context.Context) ([]domain.TelemetryStorage, error)
+}
+
+// GeofenceRepository persists geofences
+type GeofenceRepository interface {
+	List(ctx context.Context) ([]domain.Geofence, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Geofence, error)
+	Create(ctx context.Context, geofence *domain.Geofence) error
+	Update(ctx context.Context, geofence *domain.Geofence) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// DeviceKeyRepository persists device API keys, looked up by the hash of
+// their secret
+type DeviceKeyRepository interface {
+	ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.DeviceKey, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.DeviceKey, error)
+	GetByHash(ctx context.Context, hash string) (*domain.DeviceKey, error)
+	Create(ctx context.Context, key *domain.DeviceKey) error
+	Update(ctx context.Context, key *domain.DeviceKey) error
+}
+
+// UserRepository persists API users
+type UserRepository interface {
+	List(ctx context.Context) ([]domain.User, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error)
+	Create(ctx context.Context, user *domain.User) error
+	Update(ctx context.Context, user *domain.User) error
+}
+
+// TripRepository persists completed trips
+type TripRepository interface {
+	// ListByVehicle returns the trips started within [from, to), newest
+	// first and without their paths
+	ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.Trip, error)
+	// ListByDriver returns the trips a driver started within [from, to),
+	// newest first and without their paths
+	ListByDriver(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]domain.Trip, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Trip, error)
+	Create(ctx context.Context, trip *domain.Trip) error
+}
+
+// MaintenanceRepository persists maintenance records
+type MaintenanceRepository interface {
+	// ListByVehicle returns a vehicle's records, latest date first
+	ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.MaintenanceRecord, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenanceRecord, error)
+	Create(ctx context.Context, record *domain.MaintenanceRecord) error
+	Update(ctx context.Context, record *domain.MaintenanceRecord) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// MaintenancePlanRepository persists recurring maintenance plans
+type MaintenancePlanRepository interface {
+	List(ctx context.Context) ([]domain.MaintenancePlan, error)
+	ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.MaintenancePlan, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.MaintenancePlan, error)
+	Create(ctx context.Context, plan *domain.MaintenancePlan) error
+	Update(ctx context.Context, plan *domain.MaintenancePlan) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// DriverRepository persists vehicle operators
+type DriverRepository interface {
+	List(ctx context.Context) ([]domain.Driver, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.Driver, error)
+	Create(ctx context.Context, driver *domain.Driver) error
+	Update(ctx context.Context, driver *domain.Driver) error
+	// UpdateRating saves a driver's rating, leaving the rest as stored
+	UpdateRating(ctx context.Context, id uuid.UUID, rating float32) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// DriverAssignmentRepository persists the history of which driver was
+// assigned to which vehicle. A vehicle and a driver each have at most one
+// open assignment; creating a second one returns domain.ErrConflict.
+type DriverAssignmentRepository interface {
+	// ListByVehicle returns a vehicle's assignments, latest first
+	ListByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.DriverAssignment, error)
+	// ListByDriver returns a driver's assignments, latest first
+	ListByDriver(ctx context.Context, driverID uuid.UUID) ([]domain.DriverAssignment, error)
+	// ActiveAt returns the assignment of a vehicle covering at
+	ActiveAt(ctx context.Context, vehicleID uuid.UUID, at time.Time) (*domain.DriverAssignment, error)
+	Create(ctx context.Context, assignment *domain.DriverAssignment) error
+	Update(ctx context.Context, assignment *domain.DriverAssignment) error
+}
+
+// DrivingEventRepository persists the risky driving events driver scores
+// are computed from
+type DrivingEventRepository interface {
+	// ListByDriver returns a driver's events within [from, to), latest first
+	ListByDriver(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]domain.DrivingEvent, error)
+	Create(ctx context.Context, event *domain.DrivingEvent) error
+}
+
+// VehicleStatusChangeRepository persists the status changes vehicle
+// timelines and utilization are built from
+type VehicleStatusChangeRepository interface {
+	// ListByVehicle returns a vehicle's changes within [from, to), oldest
+	// first
+	ListByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.VehicleStatusChange, error)
+	// LastBefore returns a vehicle's latest change before at
+	LastBefore(ctx context.Context, vehicleID uuid.UUID, at time.Time) (*domain.VehicleStatusChange, error)
+	Create(ctx context.Context, change *domain.VehicleStatusChange) error
+}
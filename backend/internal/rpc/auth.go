@@ -0,0 +1,119 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/auth"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	pb "github.com/sid-romero/fleetpulse/internal/pb/fleetpulse/v1"
+	"github.com/sid-romero/fleetpulse/internal/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DeviceKeyMetadata carries a device API key on Ingest calls, like the
+// X-Device-Key header of HTTP ingestion
+const DeviceKeyMetadata = "x-device-key"
+
+// permissions lists the permission each method requires of bearer tokens
+var permissions = map[string]auth.Permission{
+	pb.TelemetryService_Ingest_FullMethodName:    auth.PermissionTelemetryIngest,
+	pb.TelemetryService_Subscribe_FullMethodName: auth.PermissionFleetRead,
+}
+
+// Authenticator admits calls with the credentials the HTTP API accepts: a
+// device key on Ingest, or a bearer token whose user's role grants the
+// method's permission. With a nil verifier bearer authentication is
+// disabled and calls without a device key are let through anonymously.
+type Authenticator struct {
+	verifier *auth.Verifier
+	users    *service.UserService
+	devices  *service.DeviceKeyService
+	logger   zerolog.Logger
+}
+
+func NewAuthenticator(verifier *auth.Verifier, users *service.UserService, devices *service.DeviceKeyService, logger zerolog.Logger) *Authenticator {
+	return &Authenticator{
+		verifier: verifier,
+		users:    users,
+		devices:  devices,
+		logger:   logger,
+	}
+}
+
+// StreamInterceptor authenticates streaming calls
+func (a *Authenticator) StreamInterceptor(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(stream.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticate returns ctx carrying the caller's device key or user
+func (a *Authenticator) authenticate(ctx context.Context, method string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	if key := first(md, DeviceKeyMetadata); key != "" && method == pb.TelemetryService_Ingest_FullMethodName {
+		device, err := a.devices.Authenticate(ctx, key)
+		if errors.Is(err, domain.ErrNotFound) {
+			return nil, status.Error(codes.Unauthenticated, "invalid or revoked device key")
+		}
+		if err != nil {
+			a.logger.Error().Err(err).Msg("Failed to verify device key")
+			return nil, status.Error(codes.Internal, "failed to authenticate call")
+		}
+		return auth.WithDevice(ctx, device), nil
+	}
+
+	if a.verifier == nil {
+		return ctx, nil
+	}
+
+	scheme, token, _ := strings.Cut(first(md, "authorization"), " ")
+	if !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+	}
+	userID, err := a.verifier.Verify(strings.TrimSpace(token))
+	if err != nil {
+		a.logger.Debug().Err(err).Msg("Rejected bearer token")
+		return nil, status.Error(codes.Unauthenticated, "invalid or expired token")
+	}
+
+	user, err := a.users.GetByID(ctx, userID)
+	if errors.Is(err, domain.ErrNotFound) || (err == nil && !user.IsActive) {
+		return nil, status.Error(codes.Unauthenticated, "unknown or disabled user")
+	}
+	if err != nil {
+		a.logger.Error().Err(err).Str("userId", userID.String()).Msg("Failed to load authenticated user")
+		return nil, status.Error(codes.Internal, "failed to authenticate call")
+	}
+
+	permission, ok := permissions[method]
+	if !ok || !auth.Allowed(user.Role, permission) {
+		return nil, status.Errorf(codes.PermissionDenied, "role %s lacks permission %s", user.Role, permission)
+	}
+	return auth.WithUser(ctx, user), nil
+}
+
+// authenticatedStream replaces a stream's context with the authenticated one
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+func first(md metadata.MD, key string) string {
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
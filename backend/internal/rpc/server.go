@@ -0,0 +1,218 @@
+// Package rpc serves the gRPC telemetry streaming API defined in
+// proto/fleetpulse/v1.
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/codec"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	pb "github.com/sid-romero/fleetpulse/internal/pb/fleetpulse/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Ingester is the telemetry pipeline streamed records are fed into
+type Ingester interface {
+	Ingest(ctx context.Context, telemetry *domain.Telemetry) error
+}
+
+// TelemetryServer implements the TelemetryService RPCs. Ingest feeds the
+// same pipeline as HTTP ingestion; Subscribe streams the telemetry.received
+// events it is handed through HandleEvent.
+type TelemetryServer struct {
+	pb.UnimplementedTelemetryServiceServer
+
+	ingester Ingester
+	buffer   int
+	logger   zerolog.Logger
+
+	subscribers map[*subscriber]struct{}
+	closed      bool
+	mu          sync.Mutex
+}
+
+// subscriber is one Subscribe stream. Its queue is closed, with err set,
+// when the stream must end.
+type subscriber struct {
+	vehicles map[uuid.UUID]bool // every vehicle when empty
+	queue    chan *domain.Telemetry
+	err      error
+}
+
+// NewTelemetryServer creates a TelemetryServer queueing up to buffer records
+// per subscriber
+func NewTelemetryServer(ingester Ingester, buffer int, logger zerolog.Logger) *TelemetryServer {
+	return &TelemetryServer{
+		ingester:    ingester,
+		buffer:      buffer,
+		logger:      logger.With().Str("component", "grpc").Logger(),
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// NewServer creates a gRPC server exposing telemetry behind authenticator
+func NewServer(telemetry *TelemetryServer, authenticator *Authenticator) *grpc.Server {
+	server := grpc.NewServer(grpc.StreamInterceptor(authenticator.StreamInterceptor))
+	pb.RegisterTelemetryServiceServer(server, telemetry)
+	return server
+}
+
+// Ingest stores each received record and acknowledges it before reading the
+// next. Rejected records are acknowledged with the reason; a storage failure
+// ends the stream with UNAVAILABLE, and the client should resend every
+// record it has no ack for.
+func (s *TelemetryServer) Ingest(stream grpc.BidiStreamingServer[pb.IngestRequest, pb.IngestResponse]) error {
+	ctx := stream.Context()
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ack := &pb.IngestResponse{Sequence: req.GetSequence(), Accepted: true}
+		telemetry, err := codec.FromProto(req.GetTelemetry())
+		if err == nil {
+			err = s.ingester.Ingest(ctx, telemetry)
+		}
+		if err != nil {
+			code, message, ok := rejection(err)
+			if !ok {
+				s.logger.Error().Err(err).Uint64("sequence", req.GetSequence()).Msg("Failed to ingest streamed telemetry")
+				return status.Errorf(codes.Unavailable, "failed to store record %d", req.GetSequence())
+			}
+			reason, _ := domain.RejectReasonOf(err)
+			ack.Accepted, ack.Code, ack.Message, ack.Reason = false, code, message, string(reason)
+		}
+
+		if err := stream.Send(ack); err != nil {
+			return err
+		}
+	}
+}
+
+// Subscribe streams received telemetry until the client goes away, falls
+// behind or the server shuts down
+func (s *TelemetryServer) Subscribe(req *pb.SubscribeRequest, stream grpc.ServerStreamingServer[pb.SubscribeResponse]) error {
+	vehicles := make(map[uuid.UUID]bool, len(req.GetVehicleIds()))
+	for _, v := range req.GetVehicleIds() {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid vehicle ID %q", v)
+		}
+		vehicles[id] = true
+	}
+
+	sub, err := s.subscribe(vehicles)
+	if err != nil {
+		return err
+	}
+	defer s.unsubscribe(sub)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case telemetry, ok := <-sub.queue:
+			if !ok {
+				return sub.err
+			}
+			if err := stream.Send(&pb.SubscribeResponse{Telemetry: codec.ToProto(telemetry)}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// HandleEvent consumes telemetry.received events, queueing the record for
+// every interested subscriber. A subscriber whose queue is full is dropped.
+func (s *TelemetryServer) HandleEvent(ctx context.Context, event *domain.Event) {
+	if event.Type != domain.EventTypeTelemetryReceived {
+		return
+	}
+	var data domain.TelemetryEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid telemetry event")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		if len(sub.vehicles) > 0 && !sub.vehicles[data.Telemetry.VehicleID] {
+			continue
+		}
+		select {
+		case sub.queue <- &data.Telemetry:
+		default:
+			s.logger.Warn().Msg("Dropping slow telemetry subscriber")
+			s.drop(sub, status.Error(codes.ResourceExhausted, "subscriber fell behind"))
+		}
+	}
+}
+
+// Close ends every Subscribe stream, which would otherwise keep a graceful
+// stop waiting, and refuses new ones
+func (s *TelemetryServer) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sub := range s.subscribers {
+		s.drop(sub, status.Error(codes.Unavailable, "server is shutting down"))
+	}
+}
+
+func (s *TelemetryServer) subscribe(vehicles map[uuid.UUID]bool) (*subscriber, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil, status.Error(codes.Unavailable, "server is shutting down")
+	}
+	sub := &subscriber{vehicles: vehicles, queue: make(chan *domain.Telemetry, s.buffer)}
+	s.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+func (s *TelemetryServer) unsubscribe(sub *subscriber) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop(sub, nil)
+}
+
+// drop removes a subscriber and closes its queue. The caller must hold s.mu.
+func (s *TelemetryServer) drop(sub *subscriber, err error) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+	delete(s.subscribers, sub)
+	sub.err = err
+	close(sub.queue)
+}
+
+// rejection returns the error code and message of a record the pipeline
+// refused, and false for failures that are not the record's fault
+func rejection(err error) (string, string, bool) {
+	var validationErr *domain.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		return "INVALID_TELEMETRY", err.Error(), true
+	case errors.Is(err, domain.ErrNotFound):
+		return "NOT_FOUND", "Vehicle not found", true
+	case errors.Is(err, domain.ErrForbidden):
+		return "FORBIDDEN", err.Error(), true
+	}
+	return "", "", false
+}
@@ -0,0 +1,151 @@
+package scoring
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// stationarySpeed is the speed, in km/h, below which a vehicle is standing
+// still
+const stationarySpeed = 1
+
+// Detector finds risky driving in each vehicle's telemetry stream. Every
+// episode is reported once: a harsh acceleration, harsh braking or speeding
+// episode lasts while the threshold stays exceeded, an idling episode while
+// the vehicle stands with the ignition on, and night driving is reported
+// once per hour driven at night.
+type Detector struct {
+	cfg      config.ScoringConfig
+	location *time.Location
+	vehicles map[uuid.UUID]*vehicleState
+	mu       sync.Mutex
+}
+
+type vehicleState struct {
+	last         *domain.Telemetry
+	accelerating bool
+	braking      bool
+	speeding     bool
+	idleSince    time.Time // zero while not idling
+	idleReported bool
+	nightHour    time.Time // hour of the last night driving event
+}
+
+// NewDetector creates a Detector, failing when the night hours or their time
+// zone are invalid
+func NewDetector(cfg config.ScoringConfig) (*Detector, error) {
+	if cfg.NightStart < 0 || cfg.NightStart > 23 || cfg.NightEnd < 0 || cfg.NightEnd > 23 {
+		return nil, fmt.Errorf("night hours must be between 0 and 23")
+	}
+	location, err := time.LoadLocation(cfg.TimeZone)
+	if err != nil {
+		return nil, fmt.Errorf("load time zone: %w", err)
+	}
+	return &Detector{
+		cfg:      cfg,
+		location: location,
+		vehicles: make(map[uuid.UUID]*vehicleState),
+	}, nil
+}
+
+// Update feeds a vehicle's next record and returns the events it starts,
+// without a driver. Records older than the vehicle's newest are ignored.
+func (d *Detector) Update(t *domain.Telemetry) []domain.DrivingEvent {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	state, ok := d.vehicles[t.VehicleID]
+	if !ok {
+		state = &vehicleState{}
+		d.vehicles[t.VehicleID] = state
+	}
+	if state.last != nil && !t.Timestamp.After(state.last.Timestamp) {
+		return nil
+	}
+
+	var detected []domain.DrivingEvent
+	report := func(eventType domain.DrivingEventType, value float64) {
+		detected = append(detected, domain.DrivingEvent{
+			VehicleID:  t.VehicleID,
+			Type:       eventType,
+			OccurredAt: t.Timestamp,
+			Value:      value,
+			Location:   t.Location,
+		})
+	}
+
+	// Harsh acceleration and braking, from the speed change since the
+	// previous record
+	rate := 0.0
+	if last := state.last; last != nil {
+		if gap := t.Timestamp.Sub(last.Timestamp); gap <= d.cfg.MaxGap {
+			rate = float64(t.Speed-last.Speed) / gap.Seconds()
+		}
+	}
+	if rate >= d.cfg.HarshAcceleration {
+		if !state.accelerating {
+			report(domain.DrivingEventHarshAcceleration, rate)
+		}
+		state.accelerating = true
+	} else {
+		state.accelerating = false
+	}
+	if -rate >= d.cfg.HarshBraking {
+		if !state.braking {
+			report(domain.DrivingEventHarshBraking, -rate)
+		}
+		state.braking = true
+	} else {
+		state.braking = false
+	}
+
+	// Speeding
+	if float64(t.Speed) > d.cfg.SpeedLimit {
+		if !state.speeding {
+			report(domain.DrivingEventSpeeding, float64(t.Speed))
+		}
+		state.speeding = true
+	} else {
+		state.speeding = false
+	}
+
+	// Idling
+	if t.Speed < stationarySpeed && t.Ignition != nil && *t.Ignition {
+		if state.idleSince.IsZero() {
+			state.idleSince = t.Timestamp
+		}
+		if idle := t.Timestamp.Sub(state.idleSince); !state.idleReported && idle >= d.cfg.IdleTimeout {
+			report(domain.DrivingEventIdling, idle.Seconds())
+			state.idleReported = true
+		}
+	} else {
+		state.idleSince = time.Time{}
+		state.idleReported = false
+	}
+
+	// Night driving
+	if t.Speed >= stationarySpeed && d.night(t.Timestamp) {
+		if hour := t.Timestamp.Truncate(time.Hour); !hour.Equal(state.nightHour) {
+			report(domain.DrivingEventNightDriving, float64(t.Speed))
+			state.nightHour = hour
+		}
+	}
+
+	last := *t
+	state.last = &last
+	return detected
+}
+
+// night reports whether t falls within the configured night hours
+func (d *Detector) night(t time.Time) bool {
+	hour := t.In(d.location).Hour()
+	if d.cfg.NightStart > d.cfg.NightEnd {
+		return hour >= d.cfg.NightStart || hour < d.cfg.NightEnd
+	}
+	return hour >= d.cfg.NightStart && hour < d.cfg.NightEnd
+}
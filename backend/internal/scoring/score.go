@@ -0,0 +1,61 @@
+// Package scoring detects risky driving in telemetry and turns it into a
+// driver safety score.
+package scoring
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// weights are the points an event costs per 100 km driven
+var weights = map[domain.DrivingEventType]float64{
+	domain.DrivingEventHarshAcceleration: 3,
+	domain.DrivingEventHarshBraking:      4,
+	domain.DrivingEventSpeeding:          3,
+	domain.DrivingEventIdling:            1,
+	domain.DrivingEventNightDriving:      1,
+}
+
+// minScoredDistance is the least distance, in km, events are spread over,
+// so that a driver who barely drove is not ruined by a single event
+const minScoredDistance = 100
+
+// Score computes a driver's score from their events and the distance they
+// drove over the same period. Each event costs its weight per 100 km.
+func Score(driverID uuid.UUID, events []domain.DrivingEvent, distanceKm float64) domain.DriverScore {
+	counts := make(map[domain.DrivingEventType]int)
+	for _, e := range events {
+		counts[e.Type]++
+	}
+
+	scored := math.Max(distanceKm, minScoredDistance)
+	score := domain.DriverScore{
+		DriverID:   driverID,
+		Score:      100,
+		DistanceKm: round(distanceKm),
+		Breakdown:  make([]domain.DrivingEventSummary, 0, len(domain.DrivingEventTypes)),
+	}
+	for _, eventType := range domain.DrivingEventTypes {
+		count := counts[eventType]
+		per100Km := float64(count) * 100 / scored
+		penalty := weights[eventType] * per100Km
+		score.Score -= penalty
+		score.Breakdown = append(score.Breakdown, domain.DrivingEventSummary{
+			Type:     eventType,
+			Count:    count,
+			Per100Km: round(per100Km),
+			Penalty:  round(penalty),
+		})
+	}
+
+	score.Score = round(math.Max(score.Score, 0))
+	score.Rating = float32(math.Round(score.Score/2) / 10)
+	return score
+}
+
+// round rounds to one decimal
+func round(v float64) float64 {
+	return math.Round(v*10) / 10
+}
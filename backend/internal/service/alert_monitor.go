@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/alerting"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// AlertMonitor runs the alert rules engine on telemetry.received events,
+// raising an alert and publishing the matching domain event for each violation
+type AlertMonitor struct {
+	engine    *alerting.Engine
+	alerts    *AlertService
+	publisher EventPublisher
+	logger    zerolog.Logger
+}
+
+func NewAlertMonitor(engine *alerting.Engine, alerts *AlertService, publisher EventPublisher, logger zerolog.Logger) *AlertMonitor {
+	return &AlertMonitor{
+		engine:    engine,
+		alerts:    alerts,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// HandleEvent consumes telemetry.received events
+func (m *AlertMonitor) HandleEvent(ctx context.Context, event *domain.Event) {
+	var data domain.TelemetryEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil || data.Vehicle == nil {
+		m.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid telemetry event")
+		return
+	}
+
+	for _, violation := range m.engine.Evaluate(data.Vehicle, &data.Telemetry) {
+		if err := m.alerts.Raise(ctx, violation.Alert()); err != nil {
+			m.logger.Error().Err(err).
+				Str("vehicleId", data.VehicleID.String()).
+				Str("rule", violation.Rule.Type).
+				Msg("Failed to raise alert")
+			continue
+		}
+
+		event, err := violation.Event()
+		if err != nil {
+			m.logger.Error().Err(err).Str("rule", violation.Rule.Type).Msg("Failed to build alert event")
+			continue
+		}
+		if event != nil {
+			m.publisher.Publish(ctx, event)
+		}
+	}
+}
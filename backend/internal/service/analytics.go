@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// Analytics groupings
+const (
+	GroupByVehicle = "vehicle"
+	GroupByBrand   = "brand"
+	GroupByDriver  = "driver"
+)
+
+// unassignedDriver is the driver group of vehicles without a driver
+const unassignedDriver = "unassigned"
+
+// maxAnalyticsBuckets bounds the length of a single series
+const maxAnalyticsBuckets = 5000
+
+// AnalyticsQuery selects the telemetry summarized by the consumption and
+// distance analytics
+type AnalyticsQuery struct {
+	From      time.Time
+	To        time.Time
+	Bucket    domain.Bucket
+	Location  *time.Location // time zone of day, week and month boundaries
+	VehicleID *uuid.UUID     // every vehicle when nil
+	GroupBy   string         // vehicle, brand or driver; one fleet-wide series when empty
+}
+
+// Validate checks the range, bucket and grouping of a query
+func (q *AnalyticsQuery) Validate() error {
+	switch q.GroupBy {
+	case "", GroupByVehicle, GroupByBrand, GroupByDriver:
+	default:
+		return &domain.ValidationError{Field: "groupBy", Message: "must be vehicle, brand or driver"}
+	}
+
+	switch {
+	case !q.Bucket.Valid():
+		return &domain.ValidationError{Field: "bucket", Message: "must be hour, day, week or month"}
+	case !q.From.Before(q.To):
+		return &domain.ValidationError{Field: "from", Message: "must be before to"}
+	case len(q.buckets()) > maxAnalyticsBuckets:
+		return &domain.ValidationError{Field: "bucket", Message: "too many buckets in range; use a wider bucket"}
+	}
+	return nil
+}
+
+// buckets returns the start of every bucket overlapping the query range
+func (q *AnalyticsQuery) buckets() []time.Time {
+	var starts []time.Time
+	for start := q.Bucket.Truncate(q.From, q.Location); start.Before(q.To); start = q.Bucket.Next(start) {
+		if len(starts) > maxAnalyticsBuckets {
+			break
+		}
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// seriesPoint is one bucket of one group
+type seriesPoint struct {
+	start      time.Time
+	group      string
+	distanceKm float64
+	energyUsed float64
+}
+
+// GetConsumption returns the battery drained per bucket, in percentage
+// points summed over the vehicles in each group
+func (s *AnalyticsService) GetConsumption(ctx context.Context, q AnalyticsQuery) ([]ConsumptionData, error) {
+	series, err := s.series(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]ConsumptionData, 0, len(series))
+	for _, p := range series {
+		data = append(data, ConsumptionData{
+			Date:        p.start.Format("2006-01-02"),
+			BucketStart: p.start,
+			VehicleID:   q.vehicleID(p.group),
+			Group:       p.group,
+			Value:       p.energyUsed,
+			Unit:        "%",
+		})
+	}
+	return data, nil
+}
+
+// GetDistance returns the distance driven per bucket, in km summed over the
+// vehicles in each group
+func (s *AnalyticsService) GetDistance(ctx context.Context, q AnalyticsQuery) ([]DistanceData, error) {
+	series, err := s.series(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]DistanceData, 0, len(series))
+	for _, p := range series {
+		data = append(data, DistanceData{
+			Date:        p.start.Format("2006-01-02"),
+			BucketStart: p.start,
+			VehicleID:   q.vehicleID(p.group),
+			Group:       p.group,
+			Distance:    p.distanceKm,
+		})
+	}
+	return data, nil
+}
+
+// series aggregates stored telemetry into one zero-filled series per group,
+// ordered by bucket and then group. Brand groups use each vehicle's current
+// brand, while driver groups credit telemetry to the driver assigned when it
+// was recorded.
+func (s *AnalyticsService) series(ctx context.Context, q AnalyticsQuery) ([]seriesPoint, error) {
+	if q.Location == nil {
+		q.Location = time.UTC
+	}
+	if err := q.Validate(); err != nil {
+		return nil, err
+	}
+
+	aggregate := repository.TelemetryAggregate{
+		From:     q.From,
+		To:       q.To,
+		Bucket:   q.Bucket,
+		Location: q.Location,
+	}
+	if q.VehicleID != nil {
+		aggregate.VehicleIDs = []uuid.UUID{*q.VehicleID}
+	}
+	buckets, err := s.telemetry.Aggregate(ctx, aggregate)
+	if err != nil {
+		return nil, err
+	}
+
+	var grouped []groupedBucket
+	if q.GroupBy == GroupByDriver {
+		grouped, err = s.groupByDriver(ctx, aggregate, buckets)
+	} else {
+		grouped, err = s.groupByVehicle(ctx, q, buckets)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		start int64
+		group string
+	}
+	totals := make(map[key]*seriesPoint)
+	groups := make(map[string]bool)
+	if q.GroupBy == "" {
+		groups[""] = true
+	}
+	for _, b := range grouped {
+		groups[b.group] = true
+
+		k := key{b.Start.Unix(), b.group}
+		p, ok := totals[k]
+		if !ok {
+			p = &seriesPoint{}
+			totals[k] = p
+		}
+		p.distanceKm += b.DistanceKm
+		p.energyUsed += b.EnergyUsed
+	}
+
+	names := make([]string, 0, len(groups))
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	var series []seriesPoint
+	for _, start := range q.buckets() {
+		for _, group := range names {
+			p := seriesPoint{start: start, group: group}
+			if total, ok := totals[key{start.Unix(), group}]; ok {
+				p.distanceKm, p.energyUsed = total.distanceKm, total.energyUsed
+			}
+			series = append(series, p)
+		}
+	}
+	return series, nil
+}
+
+// groupedBucket is a vehicle's bucket with the group it counts towards
+type groupedBucket struct {
+	domain.TelemetryBucket
+	group string
+}
+
+// groupByVehicle groups buckets by a property of their vehicle as it is now
+func (s *AnalyticsService) groupByVehicle(ctx context.Context, q AnalyticsQuery, buckets []domain.TelemetryBucket) ([]groupedBucket, error) {
+	vehicles, err := s.vehicles.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	groupOf := make(map[uuid.UUID]string, len(vehicles))
+	for _, v := range vehicles {
+		groupOf[v.ID] = q.group(&v)
+	}
+
+	grouped := make([]groupedBucket, 0, len(buckets))
+	for _, b := range buckets {
+		group, ok := groupOf[b.VehicleID]
+		if !ok {
+			group = q.group(&domain.Vehicle{ID: b.VehicleID}) // deleted since
+		}
+		grouped = append(grouped, groupedBucket{TelemetryBucket: b, group: group})
+	}
+	return grouped, nil
+}
+
+// groupByDriver credits buckets to the drivers assigned to their vehicle at
+// the time. A vehicle that changed hands within the range is aggregated
+// again over each assignment, so a bucket spanning the handover is split
+// between the drivers; the movement between the records either side of it
+// is not counted.
+func (s *AnalyticsService) groupByDriver(ctx context.Context, aggregate repository.TelemetryAggregate, buckets []domain.TelemetryBucket) ([]groupedBucket, error) {
+	var vehicleIDs []uuid.UUID
+	byVehicle := make(map[uuid.UUID][]domain.TelemetryBucket)
+	for _, b := range buckets {
+		if _, ok := byVehicle[b.VehicleID]; !ok {
+			vehicleIDs = append(vehicleIDs, b.VehicleID)
+		}
+		byVehicle[b.VehicleID] = append(byVehicle[b.VehicleID], b)
+	}
+
+	grouped := make([]groupedBucket, 0, len(buckets))
+	for _, vehicleID := range vehicleIDs {
+		assignments, err := s.assignments.ListByVehicle(ctx, vehicleID)
+		if err != nil {
+			return nil, err
+		}
+
+		periods := driverPeriods(assignments, aggregate.From, aggregate.To)
+		if len(periods) == 1 {
+			for _, b := range byVehicle[vehicleID] {
+				grouped = append(grouped, groupedBucket{TelemetryBucket: b, group: periods[0].group})
+			}
+			continue
+		}
+
+		for _, period := range periods {
+			query := aggregate
+			query.VehicleIDs = []uuid.UUID{vehicleID}
+			query.From, query.To = period.from, period.to
+			split, err := s.telemetry.Aggregate(ctx, query)
+			if err != nil {
+				return nil, err
+			}
+			for _, b := range split {
+				grouped = append(grouped, groupedBucket{TelemetryBucket: b, group: period.group})
+			}
+		}
+	}
+	return grouped, nil
+}
+
+// driverPeriod is a span of time a vehicle had one driver, or none
+type driverPeriod struct {
+	from, to time.Time
+	group    string
+}
+
+// driverPeriods splits [from, to) by the driver a vehicle had, given its
+// assignments
+func driverPeriods(assignments []domain.DriverAssignment, from, to time.Time) []driverPeriod {
+	sort.Slice(assignments, func(i, j int) bool { return assignments[i].AssignedAt.Before(assignments[j].AssignedAt) })
+
+	var periods []driverPeriod
+	add := func(start, end time.Time, group string) {
+		if n := len(periods); n > 0 && periods[n-1].group == group && periods[n-1].to.Equal(start) {
+			periods[n-1].to = end
+			return
+		}
+		periods = append(periods, driverPeriod{from: start, to: end, group: group})
+	}
+
+	cursor := from
+	for _, a := range assignments {
+		start, end := a.AssignedAt, to
+		if a.UnassignedAt != nil && a.UnassignedAt.Before(to) {
+			end = *a.UnassignedAt
+		}
+		if start.Before(cursor) {
+			start = cursor
+		}
+		if !start.Before(end) {
+			continue
+		}
+		if cursor.Before(start) {
+			add(cursor, start, unassignedDriver)
+		}
+		add(start, end, a.DriverID.String())
+		cursor = end
+	}
+	if cursor.Before(to) {
+		add(cursor, to, unassignedDriver)
+	}
+	return periods
+}
+
+// group returns the series a vehicle's telemetry counts towards when grouped
+// by vehicle or brand
+func (q *AnalyticsQuery) group(v *domain.Vehicle) string {
+	switch q.GroupBy {
+	case GroupByVehicle:
+		return v.ID.String()
+	case GroupByBrand:
+		return v.Brand
+	}
+	return ""
+}
+
+// vehicleID returns the vehicle a series belongs to, if it is a single one
+func (q *AnalyticsQuery) vehicleID(group string) string {
+	switch {
+	case q.GroupBy == GroupByVehicle:
+		return group
+	case q.VehicleID != nil:
+		return q.VehicleID.String()
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository/memory"
+)
+
+func TestDistanceByDriverFollowsAssignments(t *testing.T) {
+	start := time.Now().Truncate(time.Hour).Add(-3 * time.Hour)
+	at := func(minute int) time.Time { return start.Add(time.Duration(minute) * time.Minute) }
+	first, second := uuid.New(), uuid.New()
+
+	// assignment is a driver's time with the vehicle, in minutes into the
+	// hour analysed; an until of 0 is still open
+	type assignment struct {
+		driver      uuid.UUID
+		from, until int
+	}
+
+	tests := []struct {
+		name        string
+		assignments []assignment
+		current     *uuid.UUID // the vehicle's driver now
+		want        map[string]float64
+	}{
+		{
+			name: "never assigned",
+			want: map[string]float64{unassignedDriver: 5},
+		},
+		{
+			name:        "one driver throughout",
+			assignments: []assignment{{first, -60, 0}},
+			current:     &first,
+			want:        map[string]float64{first.String(): 5},
+		},
+		{
+			name:        "reassigned since",
+			assignments: []assignment{{first, -60, 120}, {second, 120, 0}},
+			current:     &second,
+			want:        map[string]float64{first.String(): 5},
+		},
+		{
+			name:        "handed over within the hour",
+			assignments: []assignment{{first, -60, 25}, {second, 25, 0}},
+			current:     &second,
+			want:        map[string]float64{first.String(): 2, second.String(): 2},
+		},
+		{
+			name:        "assigned partway",
+			assignments: []assignment{{first, 25, 0}},
+			current:     &first,
+			want:        map[string]float64{unassignedDriver: 2, first.String(): 2},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			vehicleID := uuid.New()
+			vehicles := memory.NewVehicleRepository(domain.Vehicle{ID: vehicleID, Name: "Test vehicle", DriverID: tt.current})
+			assignments := memory.NewDriverAssignmentRepository()
+			for _, a := range tt.assignments {
+				assignment := &domain.DriverAssignment{ID: uuid.New(), DriverID: a.driver, VehicleID: vehicleID, AssignedAt: at(a.from)}
+				if a.until != 0 {
+					until := at(a.until)
+					assignment.UnassignedAt = &until
+				}
+				if err := assignments.Create(ctx, assignment); err != nil {
+					t.Fatalf("Create assignment: %v", err)
+				}
+			}
+
+			// Six records 1 km apart, ten minutes apart
+			telemetry := memory.NewTelemetryRepository()
+			for i := 0; i < 6; i++ {
+				err := telemetry.Insert(ctx, &domain.Telemetry{
+					VehicleID: vehicleID,
+					Timestamp: at(10 * i),
+					Location:  domain.Location{Lat: 40 + 0.009*float64(i), Lng: -3.7},
+				})
+				if err != nil {
+					t.Fatalf("Insert: %v", err)
+				}
+			}
+
+			analytics := NewAnalyticsService(vehicles, memory.NewAlertRepository(), telemetry, assignments,
+				publisherFunc(discardEvents), zerolog.Nop())
+			data, err := analytics.GetDistance(ctx, AnalyticsQuery{
+				From:    start,
+				To:      start.Add(time.Hour),
+				Bucket:  domain.BucketHour,
+				GroupBy: GroupByDriver,
+			})
+			if err != nil {
+				t.Fatalf("GetDistance: %v", err)
+			}
+
+			got := make(map[string]float64, len(data))
+			for _, d := range data {
+				got[d.Group] = d.Distance
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("distance by driver = %v, want %v", got, tt.want)
+			}
+			for group, want := range tt.want {
+				if math.Abs(got[group]-want) > 0.05 {
+					t.Errorf("distance of %s = %.2f km, want %.2f", group, got[group], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/auth"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// DeviceKeyService issues, rotates and revokes the API keys devices use to
+// report telemetry for their vehicle
+type DeviceKeyService struct {
+	keys     repository.DeviceKeyRepository
+	vehicles repository.VehicleRepository
+	logger   zerolog.Logger
+}
+
+func NewDeviceKeyService(
+	keys repository.DeviceKeyRepository,
+	vehicles repository.VehicleRepository,
+	logger zerolog.Logger,
+) *DeviceKeyService {
+	return &DeviceKeyService{
+		keys:     keys,
+		vehicles: vehicles,
+		logger:   logger,
+	}
+}
+
+// GetByVehicle lists a vehicle's keys, including revoked ones
+func (s *DeviceKeyService) GetByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.DeviceKey, error) {
+	if _, err := s.vehicles.GetByID(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	return s.keys.ListByVehicle(ctx, vehicleID)
+}
+
+// Issue creates a new key for a vehicle. The returned secret is not stored
+// and cannot be retrieved again.
+func (s *DeviceKeyService) Issue(ctx context.Context, vehicleID uuid.UUID, name string) (*domain.IssuedDeviceKey, error) {
+	if _, err := s.vehicles.GetByID(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, vehicleID, name)
+}
+
+// Rotate revokes a key and issues its replacement under the same name
+func (s *DeviceKeyService) Rotate(ctx context.Context, vehicleID, keyID uuid.UUID) (*domain.IssuedDeviceKey, error) {
+	key, err := s.get(ctx, vehicleID, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.RevokedAt != nil {
+		return nil, fmt.Errorf("%w: key already revoked", domain.ErrConflict)
+	}
+
+	if err := s.revoke(ctx, key); err != nil {
+		return nil, err
+	}
+	return s.issue(ctx, vehicleID, key.Name)
+}
+
+// Revoke disables a key immediately; revoking twice is a no-op
+func (s *DeviceKeyService) Revoke(ctx context.Context, vehicleID, keyID uuid.UUID) error {
+	key, err := s.get(ctx, vehicleID, keyID)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+	return s.revoke(ctx, key)
+}
+
+// Authenticate resolves a presented key, returning domain.ErrNotFound for
+// unknown and revoked keys alike
+func (s *DeviceKeyService) Authenticate(ctx context.Context, key string) (*domain.DeviceKey, error) {
+	deviceKey, err := s.keys.GetByHash(ctx, auth.HashDeviceKey(key))
+	if err != nil {
+		return nil, err
+	}
+	if deviceKey.RevokedAt != nil {
+		return nil, domain.ErrNotFound
+	}
+	return deviceKey, nil
+}
+
+// get loads a key, treating keys of other vehicles as missing
+func (s *DeviceKeyService) get(ctx context.Context, vehicleID, keyID uuid.UUID) (*domain.DeviceKey, error) {
+	key, err := s.keys.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key.VehicleID != vehicleID {
+		return nil, domain.ErrNotFound
+	}
+	return key, nil
+}
+
+func (s *DeviceKeyService) issue(ctx context.Context, vehicleID uuid.UUID, name string) (*domain.IssuedDeviceKey, error) {
+	secret, prefix, hash, err := auth.GenerateDeviceKey()
+	if err != nil {
+		return nil, fmt.Errorf("generate device key: %w", err)
+	}
+
+	issued := &domain.IssuedDeviceKey{
+		DeviceKey: domain.DeviceKey{
+			ID:        uuid.New(),
+			VehicleID: vehicleID,
+			Name:      name,
+			Prefix:    prefix,
+			KeyHash:   hash,
+			CreatedAt: time.Now(),
+		},
+		Key: secret,
+	}
+	if err := s.keys.Create(ctx, &issued.DeviceKey); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("keyId", issued.ID.String()).
+		Str("vehicleId", vehicleID.String()).
+		Msg("Device key issued")
+	return issued, nil
+}
+
+func (s *DeviceKeyService) revoke(ctx context.Context, key *domain.DeviceKey) error {
+	now := time.Now()
+	key.RevokedAt = &now
+	if err := s.keys.Update(ctx, key); err != nil {
+		return err
+	}
+
+	s.logger.Info().
+		Str("keyId", key.ID.String()).
+		Str("vehicleId", key.VehicleID.String()).
+		Msg("Device key revoked")
+	return nil
+}
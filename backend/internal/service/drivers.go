@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/auth"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// DriverService manages drivers and their assignment to vehicles. Every
+// assignment is kept so that telemetry and alerts can be attributed to
+// whoever was driving at the time.
+type DriverService struct {
+	drivers     repository.DriverRepository
+	assignments repository.DriverAssignmentRepository
+	vehicles    *VehicleService
+	publisher   EventPublisher
+	logger      zerolog.Logger
+
+	mu sync.Mutex // serializes assignment changes
+}
+
+func NewDriverService(
+	drivers repository.DriverRepository,
+	assignments repository.DriverAssignmentRepository,
+	vehicles *VehicleService,
+	publisher EventPublisher,
+	logger zerolog.Logger,
+) *DriverService {
+	return &DriverService{
+		drivers:     drivers,
+		assignments: assignments,
+		vehicles:    vehicles,
+		publisher:   publisher,
+		logger:      logger,
+	}
+}
+
+func (s *DriverService) GetAll(ctx context.Context) ([]domain.Driver, error) {
+	return s.drivers.List(ctx)
+}
+
+func (s *DriverService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Driver, error) {
+	return s.drivers.GetByID(ctx, id)
+}
+
+func (s *DriverService) Create(ctx context.Context, driver *domain.Driver) (*domain.Driver, error) {
+	driver.Email = strings.ToLower(strings.TrimSpace(driver.Email))
+	if err := driver.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	driver.ID = uuid.New()
+	driver.CreatedAt = now
+	driver.UpdatedAt = now
+	if err := s.drivers.Create(ctx, driver); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().Str("driverId", driver.ID.String()).Msg("Driver created")
+	return driver, nil
+}
+
+// Update stores a driver. Vehicles embed their driver when read, so the
+// vehicle the driver is assigned to shows the change without being saved.
+func (s *DriverService) Update(ctx context.Context, driver *domain.Driver) (*domain.Driver, error) {
+	driver.Email = strings.ToLower(strings.TrimSpace(driver.Email))
+	if err := driver.Validate(); err != nil {
+		return nil, err
+	}
+
+	driver.UpdatedAt = time.Now()
+	if err := s.drivers.Update(ctx, driver); err != nil {
+		return nil, err
+	}
+	return driver, nil
+}
+
+// SetRating saves a driver's rating alone, so edits made to the driver
+// meanwhile are kept
+func (s *DriverService) SetRating(ctx context.Context, id uuid.UUID, rating float32) error {
+	return s.drivers.UpdateRating(ctx, id, rating)
+}
+
+// Delete unassigns a driver from their vehicle and removes them
+func (s *DriverService) Delete(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.drivers.GetByID(ctx, id); err != nil {
+		return err
+	}
+	current, err := s.openByDriver(ctx, id)
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		if err := s.close(ctx, current, time.Now()); err != nil {
+			return err
+		}
+	}
+	return s.drivers.Delete(ctx, id)
+}
+
+// Assign makes driverID the driver of vehicleID. The vehicle's previous
+// driver is unassigned, and so is the driver from any other vehicle they
+// were assigned to. Assigning the current driver again is a no-op.
+func (s *DriverService) Assign(ctx context.Context, vehicleID, driverID uuid.UUID) (*domain.DriverAssignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.vehicles.GetByID(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	_, err := s.drivers.GetByID(ctx, driverID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, &domain.ValidationError{Field: "driverId", Message: "must be an existing driver"}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.openByVehicle(ctx, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	if current != nil && current.DriverID == driverID {
+		return current, nil
+	}
+
+	now := time.Now()
+	if current != nil {
+		if err := s.close(ctx, current, now); err != nil {
+			return nil, err
+		}
+	}
+	previous, err := s.openByDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		if err := s.close(ctx, previous, now); err != nil {
+			return nil, err
+		}
+	}
+
+	assignment := &domain.DriverAssignment{
+		ID:         uuid.New(),
+		DriverID:   driverID,
+		VehicleID:  vehicleID,
+		AssignedAt: now,
+		AssignedBy: auth.UserID(ctx),
+	}
+	if err := s.assignments.Create(ctx, assignment); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.vehicles.SetDriver(ctx, vehicleID, &driverID); err != nil {
+		return nil, err
+	}
+
+	publishEvent(ctx, s.publisher, s.logger, domain.EventTypeDriverAssigned, vehicleID.String(), domain.DriverAssignmentData{
+		Assignment: *assignment,
+		ActionBy:   assignment.AssignedBy,
+	})
+	return assignment, nil
+}
+
+// Unassign removes the driver of vehicleID and returns the closed
+// assignment
+func (s *DriverService) Unassign(ctx context.Context, vehicleID uuid.UUID) (*domain.DriverAssignment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.vehicles.GetByID(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	current, err := s.openByVehicle(ctx, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("%w: vehicle has no driver assigned", domain.ErrConflict)
+	}
+	if err := s.close(ctx, current, time.Now()); err != nil {
+		return nil, err
+	}
+	return current, nil
+}
+
+// GetVehicleAssignments returns a vehicle's assignments, latest first
+func (s *DriverService) GetVehicleAssignments(ctx context.Context, vehicleID uuid.UUID) ([]domain.DriverAssignment, error) {
+	if _, err := s.vehicles.GetByID(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	return s.assignments.ListByVehicle(ctx, vehicleID)
+}
+
+// GetDriverAssignments returns a driver's assignments, latest first
+func (s *DriverService) GetDriverAssignments(ctx context.Context, driverID uuid.UUID) ([]domain.DriverAssignment, error) {
+	if _, err := s.drivers.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+	return s.assignments.ListByDriver(ctx, driverID)
+}
+
+// AssignmentAt returns the assignment of vehicleID covering at, which
+// attributes a telemetry record or an alert of the vehicle to a driver. It
+// returns domain.ErrNotFound when nobody was assigned.
+func (s *DriverService) AssignmentAt(ctx context.Context, vehicleID uuid.UUID, at time.Time) (*domain.DriverAssignment, error) {
+	return s.assignments.ActiveAt(ctx, vehicleID, at)
+}
+
+// close ends an open assignment at now and clears the vehicle's driver
+func (s *DriverService) close(ctx context.Context, a *domain.DriverAssignment, now time.Time) error {
+	a.UnassignedAt = &now
+	if err := s.assignments.Update(ctx, a); err != nil {
+		return err
+	}
+
+	vehicle, err := s.vehicles.GetByID(ctx, a.VehicleID)
+	if err != nil {
+		return err
+	}
+	if vehicle.DriverID != nil && *vehicle.DriverID == a.DriverID {
+		if _, err := s.vehicles.SetDriver(ctx, a.VehicleID, nil); err != nil {
+			return err
+		}
+	}
+
+	publishEvent(ctx, s.publisher, s.logger, domain.EventTypeDriverUnassigned, a.VehicleID.String(), domain.DriverAssignmentData{
+		Assignment: *a,
+		ActionBy:   auth.UserID(ctx),
+	})
+	return nil
+}
+
+func (s *DriverService) openByVehicle(ctx context.Context, vehicleID uuid.UUID) (*domain.DriverAssignment, error) {
+	assignments, err := s.assignments.ListByVehicle(ctx, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	return openAssignment(assignments), nil
+}
+
+func (s *DriverService) openByDriver(ctx context.Context, driverID uuid.UUID) (*domain.DriverAssignment, error) {
+	assignments, err := s.assignments.ListByDriver(ctx, driverID)
+	if err != nil {
+		return nil, err
+	}
+	return openAssignment(assignments), nil
+}
+
+// openAssignment returns the assignment of assignments that has not ended
+func openAssignment(assignments []domain.DriverAssignment) *domain.DriverAssignment {
+	for i := range assignments {
+		if assignments[i].UnassignedAt == nil {
+			return &assignments[i]
+		}
+	}
+	return nil
+}
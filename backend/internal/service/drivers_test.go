@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+	"github.com/sid-romero/fleetpulse/internal/repository/memory"
+)
+
+// movingVehicles applies a kilometre of telemetry, switching the vehicle
+// between idle and active, after every read, as if it had landed before the
+// reader saved the vehicle
+type movingVehicles struct {
+	*memory.VehicleRepository
+	applied int
+}
+
+func (r *movingVehicles) GetByID(ctx context.Context, id uuid.UUID) (*domain.Vehicle, error) {
+	vehicle, err := r.VehicleRepository.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	next := domain.VehicleStatusActive
+	if vehicle.Status == domain.VehicleStatusActive {
+		next = domain.VehicleStatusIdle
+	}
+	state := repository.VehicleLiveState{From: vehicle.Status, Status: next, DistanceKm: 1}
+	if _, err := r.UpdateLiveState(ctx, id, state); err != nil {
+		return nil, err
+	}
+	r.applied++
+	return vehicle, nil
+}
+
+func TestDriverChangesKeepTelemetry(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(ctx context.Context, s *DriverService, vehicleID, otherID uuid.UUID, driver *domain.Driver) error
+		// driverOf is the expected driver of the vehicle and the other one,
+		// true for the test driver
+		driverOf [2]bool
+	}{
+		{
+			name: "assign",
+			change: func(ctx context.Context, s *DriverService, vehicleID, otherID uuid.UUID, driver *domain.Driver) error {
+				_, err := s.Assign(ctx, vehicleID, driver.ID)
+				return err
+			},
+			driverOf: [2]bool{true, false},
+		},
+		{
+			name: "reassign to another vehicle",
+			change: func(ctx context.Context, s *DriverService, vehicleID, otherID uuid.UUID, driver *domain.Driver) error {
+				if _, err := s.Assign(ctx, vehicleID, driver.ID); err != nil {
+					return err
+				}
+				_, err := s.Assign(ctx, otherID, driver.ID)
+				return err
+			},
+			driverOf: [2]bool{false, true},
+		},
+		{
+			name: "unassign",
+			change: func(ctx context.Context, s *DriverService, vehicleID, otherID uuid.UUID, driver *domain.Driver) error {
+				if _, err := s.Assign(ctx, vehicleID, driver.ID); err != nil {
+					return err
+				}
+				_, err := s.Unassign(ctx, vehicleID)
+				return err
+			},
+		},
+		{
+			name: "update the assigned driver",
+			change: func(ctx context.Context, s *DriverService, vehicleID, otherID uuid.UUID, driver *domain.Driver) error {
+				if _, err := s.Assign(ctx, vehicleID, driver.ID); err != nil {
+					return err
+				}
+				driver.Name = "Renamed driver"
+				_, err := s.Update(ctx, driver)
+				return err
+			},
+			driverOf: [2]bool{true, false},
+		},
+		{
+			name: "delete the assigned driver",
+			change: func(ctx context.Context, s *DriverService, vehicleID, otherID uuid.UUID, driver *domain.Driver) error {
+				if _, err := s.Assign(ctx, vehicleID, driver.ID); err != nil {
+					return err
+				}
+				return s.Delete(ctx, driver.ID)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := zerolog.Nop()
+			vehicleIDs := [2]uuid.UUID{uuid.New(), uuid.New()}
+			driver := &domain.Driver{ID: uuid.New(), Name: "Test driver"}
+			drivers := memory.NewDriverRepository(*driver)
+			vehicles := &movingVehicles{VehicleRepository: memory.NewVehicleRepository(
+				domain.Vehicle{ID: vehicleIDs[0], Name: "Van 1", Status: domain.VehicleStatusIdle, Odometer: 1000},
+				domain.Vehicle{ID: vehicleIDs[1], Name: "Van 2", Status: domain.VehicleStatusIdle, Odometer: 1000},
+			).JoinDrivers(drivers)}
+			service := NewDriverService(drivers, memory.NewDriverAssignmentRepository(),
+				NewVehicleService(vehicles, publisherFunc(discardEvents), logger), publisherFunc(discardEvents), logger)
+
+			if err := tt.change(ctx, service, vehicleIDs[0], vehicleIDs[1], driver); err != nil {
+				t.Fatalf("change: %v", err)
+			}
+
+			odometers := 0
+			for i, id := range vehicleIDs {
+				stored, err := vehicles.VehicleRepository.GetByID(ctx, id)
+				if err != nil {
+					t.Fatalf("GetByID: %v", err)
+				}
+				odometers += stored.Odometer - 1000
+
+				assigned := stored.DriverID != nil && *stored.DriverID == driver.ID
+				if assigned != tt.driverOf[i] {
+					t.Errorf("vehicle %d assigned = %v, want %v", i, assigned, tt.driverOf[i])
+				}
+				if assigned && (stored.Driver == nil || stored.Driver.Name != driver.Name) {
+					t.Errorf("vehicle %d embeds driver %+v, want %q", i, stored.Driver, driver.Name)
+				}
+			}
+			if odometers != vehicles.applied {
+				t.Errorf("odometers advanced %d km, want the %d km of telemetry applied", odometers, vehicles.applied)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// FleetStatsEventTypes are the events that can change fleet statistics
+var FleetStatsEventTypes = []domain.EventType{
+	domain.EventTypeVehicleUpdated,
+	domain.EventTypeAlertCreated,
+	domain.EventTypeAlertAcknowledged,
+	domain.EventTypeAlertResolved,
+}
+
+// fleetTotals are the running sums behind domain.FleetStats. Each vehicle's
+// contribution is remembered so an update subtracts the old values before
+// adding the new ones.
+type fleetTotals struct {
+	vehicles map[uuid.UUID]vehicleContribution
+	critical map[uuid.UUID]bool // active critical alerts
+
+	active, charging int
+	odometerKm       int
+	efficiencySum    float64
+	efficiencyCount  int
+}
+
+type vehicleContribution struct {
+	status     domain.VehicleStatus
+	odometerKm int
+	efficiency float64
+	rated      bool // efficiency was parseable
+}
+
+func newFleetTotals() *fleetTotals {
+	return &fleetTotals{
+		vehicles: make(map[uuid.UUID]vehicleContribution),
+		critical: make(map[uuid.UUID]bool),
+	}
+}
+
+// applyVehicle replaces a vehicle's contribution with its current state
+func (t *fleetTotals) applyVehicle(v *domain.Vehicle) {
+	if old, ok := t.vehicles[v.ID]; ok {
+		t.add(old, -1)
+	}
+
+	c := vehicleContribution{status: v.Status, odometerKm: v.Odometer}
+	c.efficiency, c.rated = efficiencyPer100km(v.Efficiency)
+	t.vehicles[v.ID] = c
+	t.add(c, 1)
+}
+
+func (t *fleetTotals) add(c vehicleContribution, sign int) {
+	switch c.status {
+	case domain.VehicleStatusActive:
+		t.active += sign
+	case domain.VehicleStatusCharging:
+		t.charging += sign
+	}
+	t.odometerKm += sign * c.odometerKm
+	if c.rated {
+		t.efficiencySum += float64(sign) * c.efficiency
+		t.efficiencyCount += sign
+	}
+}
+
+// applyAlert counts an alert while it is active and critical
+func (t *fleetTotals) applyAlert(a *domain.Alert) {
+	if a.Status == domain.AlertStatusActive && a.Severity == domain.AlertSeverityCritical {
+		t.critical[a.ID] = true
+	} else {
+		delete(t.critical, a.ID)
+	}
+}
+
+func (t *fleetTotals) stats() domain.FleetStats {
+	stats := domain.FleetStats{
+		ActiveVehicles:   t.active,
+		TotalVehicles:    len(t.vehicles),
+		CriticalAlerts:   len(t.critical),
+		TotalDistanceKm:  float64(t.odometerKm),
+		VehiclesCharging: t.charging,
+	}
+	if t.efficiencyCount > 0 {
+		// Rounded so float drift in the running sum never reads as a change
+		stats.AvgEfficiency = math.Round(t.efficiencySum/float64(t.efficiencyCount)*10) / 10
+	}
+	return stats
+}
+
+var efficiencyPattern = regexp.MustCompile(`^\s*([0-9]+(?:\.[0-9]+)?)\s*kWh\s*/\s*(100\s*)?km\s*$`)
+
+// efficiencyPer100km parses a vehicle efficiency such as "19 kWh/100km" or
+// "1.2 kWh/km" into kWh per 100 km
+func efficiencyPer100km(efficiency string) (float64, bool) {
+	m := efficiencyPattern.FindStringSubmatch(efficiency)
+	if m == nil {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(m[1], 64)
+	if err != nil {
+		return 0, false
+	}
+	if m[2] == "" {
+		value *= 100
+	}
+	return value, true
+}
+
+// GetFleetStats returns statistics over the current vehicle and alert state
+func (s *AnalyticsService) GetFleetStats(ctx context.Context) (*domain.FleetStats, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		return nil, err
+	}
+
+	stats := s.totals.stats()
+	stats.Timestamp = time.Now()
+	return &stats, nil
+}
+
+// HandleEvent consumes FleetStatsEventTypes, publishing fleet.stats.updated
+// whenever the statistics change
+func (s *AnalyticsService) HandleEvent(ctx context.Context, event *domain.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.load(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to load fleet statistics")
+		return
+	}
+
+	switch event.Type {
+	case domain.EventTypeVehicleUpdated:
+		var vehicle domain.Vehicle
+		if err := json.Unmarshal(event.Data, &vehicle); err != nil {
+			s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid vehicle event")
+			return
+		}
+		s.totals.applyVehicle(&vehicle)
+
+	case domain.EventTypeAlertCreated, domain.EventTypeAlertAcknowledged, domain.EventTypeAlertResolved:
+		var data domain.AlertEventData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid alert event")
+			return
+		}
+		s.totals.applyAlert(&data.Alert)
+
+	default:
+		return
+	}
+
+	stats := s.totals.stats()
+	if stats == s.published {
+		return
+	}
+	s.published = stats
+
+	stats.Timestamp = time.Now()
+	publishEvent(ctx, s.publisher, s.logger, domain.EventTypeFleetStatsUpdated, "system", stats)
+}
+
+// load seeds the totals from storage on first use; the caller must hold s.mu
+func (s *AnalyticsService) load(ctx context.Context) error {
+	if s.totals != nil {
+		return nil
+	}
+
+	vehicles, err := s.vehicles.List(ctx)
+	if err != nil {
+		return err
+	}
+	status, severity := domain.AlertStatusActive, domain.AlertSeverityCritical
+	alerts, err := s.alerts.List(ctx, repository.AlertFilter{Status: &status, Severity: &severity})
+	if err != nil {
+		return err
+	}
+
+	totals := newFleetTotals()
+	for i := range vehicles {
+		totals.applyVehicle(&vehicles[i])
+	}
+	for i := range alerts {
+		totals.applyAlert(&alerts[i])
+	}
+
+	s.totals = totals
+	s.published = totals.stats()
+	return nil
+}
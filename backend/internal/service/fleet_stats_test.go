@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository/memory"
+)
+
+func TestFleetDistanceFollowsTelemetry(t *testing.T) {
+	tests := []struct {
+		name     string
+		records  int
+		step     float64 // degrees of latitude between records, 0.009 is about 1 km
+		distance float64
+	}{
+		{name: "first record", records: 1, step: 0.009, distance: 1500},
+		{name: "under a km", records: 2, step: 0.0045, distance: 1500},
+		{name: "several km", records: 6, step: 0.009, distance: 1505},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := zerolog.Nop()
+			moving, parked := uuid.New(), uuid.New()
+			vehicles := memory.NewVehicleRepository(
+				domain.Vehicle{ID: moving, Name: "Moving", Status: domain.VehicleStatusIdle, Odometer: 1000},
+				domain.Vehicle{ID: parked, Name: "Parked", Status: domain.VehicleStatusIdle, Odometer: 500},
+			)
+
+			var published []domain.FleetStats
+			var analytics *AnalyticsService
+			analytics = NewAnalyticsService(vehicles, memory.NewAlertRepository(), memory.NewTelemetryRepository(), memory.NewDriverAssignmentRepository(),
+				publisherFunc(func(ctx context.Context, event *domain.Event) {
+					var stats domain.FleetStats
+					if err := json.Unmarshal(event.Data, &stats); err != nil {
+						t.Errorf("decode stats: %v", err)
+					}
+					published = append(published, stats)
+				}), logger)
+			publisher := publisherFunc(func(ctx context.Context, event *domain.Event) {
+				if event.Type == domain.EventTypeVehicleUpdated {
+					analytics.HandleEvent(ctx, event)
+				}
+			})
+			telemetry := NewTelemetryService(memory.NewTelemetryRepository(), vehicles, newTestValidator(t), 5*time.Minute, publisher, logger)
+
+			start := time.Now().Add(-time.Hour)
+			for i := 0; i < tt.records; i++ {
+				err := telemetry.Ingest(ctx, &domain.Telemetry{
+					VehicleID:    moving,
+					Timestamp:    start.Add(time.Duration(i) * 30 * time.Second),
+					Location:     domain.Location{Lat: 40 + tt.step*float64(i), Lng: -3.7},
+					Speed:        100,
+					BatteryLevel: 80,
+				})
+				if err != nil {
+					t.Fatalf("Ingest record %d: %v", i, err)
+				}
+			}
+
+			stats, err := analytics.GetFleetStats(ctx)
+			if err != nil {
+				t.Fatalf("GetFleetStats: %v", err)
+			}
+			if stats.TotalDistanceKm != tt.distance {
+				t.Errorf("TotalDistanceKm = %v, want %v", stats.TotalDistanceKm, tt.distance)
+			}
+			if tt.distance > 1500 {
+				if len(published) == 0 || published[len(published)-1].TotalDistanceKm != tt.distance {
+					t.Errorf("published stats %+v, want the last at %v km", published, tt.distance)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/geofence"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// GeofenceService manages geofences and detects vehicles crossing them
+type GeofenceService struct {
+	geofences repository.GeofenceRepository
+	alerts    *AlertService
+	publisher EventPublisher
+	tracker   *geofence.Tracker
+	logger    zerolog.Logger
+
+	// active caches the active geofences evaluated on every telemetry record;
+	// it is invalidated whenever a geofence changes. generation counts the
+	// invalidations, so a list read before one is never cached.
+	active     []domain.Geofence
+	loaded     bool
+	generation uint64
+	mu         sync.RWMutex
+}
+
+func NewGeofenceService(
+	geofences repository.GeofenceRepository,
+	alerts *AlertService,
+	publisher EventPublisher,
+	logger zerolog.Logger,
+) *GeofenceService {
+	return &GeofenceService{
+		geofences: geofences,
+		alerts:    alerts,
+		publisher: publisher,
+		tracker:   geofence.NewTracker(),
+		logger:    logger,
+	}
+}
+
+func (s *GeofenceService) GetAll(ctx context.Context) ([]domain.Geofence, error) {
+	return s.geofences.List(ctx)
+}
+
+func (s *GeofenceService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Geofence, error) {
+	return s.geofences.GetByID(ctx, id)
+}
+
+func (s *GeofenceService) Create(ctx context.Context, g *domain.Geofence) (*domain.Geofence, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+
+	g.ID = uuid.New()
+	g.CreatedAt = time.Now()
+	if err := s.geofences.Create(ctx, g); err != nil {
+		return nil, err
+	}
+
+	s.invalidate()
+	s.publishChange(ctx, domain.EventTypeGeofenceCreated, g.ID, g)
+	return g, nil
+}
+
+func (s *GeofenceService) Update(ctx context.Context, g *domain.Geofence) (*domain.Geofence, error) {
+	if err := g.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.geofences.Update(ctx, g); err != nil {
+		return nil, err
+	}
+
+	s.tracker.Forget(g.ID)
+	s.invalidate()
+	s.publishChange(ctx, domain.EventTypeGeofenceUpdated, g.ID, g)
+	return g, nil
+}
+
+func (s *GeofenceService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := s.geofences.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	s.tracker.Forget(id)
+	s.invalidate()
+	s.publishChange(ctx, domain.EventTypeGeofenceDeleted, id, nil)
+	return nil
+}
+
+// GeofenceChangeEventTypes are published when a geofence changes, so that
+// other API replicas can drop their cached geofences
+var GeofenceChangeEventTypes = []domain.EventType{
+	domain.EventTypeGeofenceCreated,
+	domain.EventTypeGeofenceUpdated,
+	domain.EventTypeGeofenceDeleted,
+}
+
+func (s *GeofenceService) publishChange(ctx context.Context, eventType domain.EventType, id uuid.UUID, g *domain.Geofence) {
+	publishEvent(ctx, s.publisher, s.logger, eventType, id.String(), domain.GeofenceChangeData{GeofenceID: id, Geofence: g})
+}
+
+// HandleChange consumes geofence changes relayed from other replicas,
+// dropping the cached geofences and, for a reshaped or deleted geofence,
+// the vehicles tracked inside it. Other events are ignored.
+func (s *GeofenceService) HandleChange(ctx context.Context, event *domain.Event) {
+	switch event.Type {
+	case domain.EventTypeGeofenceCreated:
+	case domain.EventTypeGeofenceUpdated, domain.EventTypeGeofenceDeleted:
+		var data domain.GeofenceChangeData
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid geofence event")
+			return
+		}
+		s.tracker.Forget(data.GeofenceID)
+	default:
+		return
+	}
+	s.invalidate()
+}
+
+// HandleEvent consumes telemetry.received events
+func (s *GeofenceService) HandleEvent(ctx context.Context, event *domain.Event) {
+	var data domain.TelemetryEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil || data.Vehicle == nil {
+		s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid telemetry event")
+		return
+	}
+
+	active, err := s.activeGeofences(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to load geofences")
+		return
+	}
+
+	for _, transition := range s.tracker.Update(data.VehicleID, data.Telemetry.Location, active) {
+		s.handleTransition(ctx, data.Vehicle, &data.Telemetry, transition)
+	}
+}
+
+func (s *GeofenceService) handleTransition(ctx context.Context, vehicle *domain.Vehicle, telemetry *domain.Telemetry, transition geofence.Transition) {
+	g := transition.Geofence
+	data := &domain.GeofenceEventData{
+		VehicleID:    vehicle.ID,
+		GeofenceID:   g.ID,
+		GeofenceName: g.Name,
+		Location:     telemetry.Location,
+		EventType:    "exit",
+	}
+	eventType := domain.EventTypeGeofenceExit
+	if transition.Entered {
+		data.EventType = "enter"
+		eventType = domain.EventTypeGeofenceEnter
+	}
+
+	publishEvent(ctx, s.publisher, s.logger, eventType, vehicle.ID.String(), data)
+
+	if (transition.Entered && g.AlertOnEnter) || (!transition.Entered && g.AlertOnExit) {
+		alert := geofenceAlert(vehicle, telemetry, transition)
+		if err := s.alerts.Raise(ctx, alert); err != nil {
+			s.logger.Error().Err(err).Str("geofenceId", g.ID.String()).Msg("Failed to raise geofence alert")
+		}
+	}
+}
+
+func geofenceAlert(vehicle *domain.Vehicle, telemetry *domain.Telemetry, transition geofence.Transition) *domain.Alert {
+	vehicleID := vehicle.ID
+	alert := &domain.Alert{
+		VehicleID: &vehicleID,
+		Type:      "geofence_exit",
+		Severity:  domain.AlertSeverityWarning,
+		Message:   fmt.Sprintf("%s left geofence %s", vehicle.Name, transition.Geofence.Name),
+		CreatedAt: telemetry.Timestamp,
+	}
+	if transition.Entered {
+		alert.Type = "geofence_enter"
+		alert.Severity = domain.AlertSeverityInfo
+		alert.Message = fmt.Sprintf("%s entered geofence %s", vehicle.Name, transition.Geofence.Name)
+	}
+	return alert
+}
+
+func (s *GeofenceService) activeGeofences(ctx context.Context) ([]domain.Geofence, error) {
+	s.mu.RLock()
+	active, loaded := s.active, s.loaded
+	s.mu.RUnlock()
+
+	if loaded {
+		return active, nil
+	}
+	return s.reload(ctx)
+}
+
+// invalidate forces the next telemetry record to reload the active geofences
+func (s *GeofenceService) invalidate() {
+	s.mu.Lock()
+	s.loaded = false
+	s.generation++
+	s.mu.Unlock()
+}
+
+// reload lists the active geofences and caches them unless they were
+// invalidated while being listed
+func (s *GeofenceService) reload(ctx context.Context) ([]domain.Geofence, error) {
+	s.mu.RLock()
+	generation := s.generation
+	s.mu.RUnlock()
+
+	all, err := s.geofences.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	active := make([]domain.Geofence, 0, len(all))
+	for _, g := range all {
+		if g.IsActive {
+			active = append(active, g)
+		}
+	}
+
+	s.mu.Lock()
+	if s.generation == generation {
+		s.active, s.loaded = active, true
+	}
+	s.mu.Unlock()
+	return active, nil
+}
@@ -0,0 +1,472 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// maintenanceSweepInterval is how often plans are checked for due dates
+const maintenanceSweepInterval = time.Hour
+
+// maintenanceTransitions lists the statuses each record status may move to;
+// completed and cancelled records keep their status
+var maintenanceTransitions = map[domain.MaintenanceStatus][]domain.MaintenanceStatus{
+	domain.MaintenanceStatusScheduled: {
+		domain.MaintenanceStatusInProgress,
+		domain.MaintenanceStatusCompleted,
+		domain.MaintenanceStatusCancelled,
+	},
+	domain.MaintenanceStatusInProgress: {
+		domain.MaintenanceStatusCompleted,
+		domain.MaintenanceStatusCancelled,
+	},
+}
+
+// MaintenanceService manages maintenance records and plans. A record in
+// progress puts its vehicle in maintenance, and plans that fall due publish
+// maintenance.due and raise an alert.
+type MaintenanceService struct {
+	records   repository.MaintenanceRepository
+	plans     repository.MaintenancePlanRepository
+	vehicles  *VehicleService
+	alerts    *AlertService
+	publisher EventPublisher
+	logger    zerolog.Logger
+
+	// odometers holds the reading each vehicle's plans were last checked at,
+	// so only odometer changes trigger a check
+	odometers map[uuid.UUID]int
+	mu        sync.Mutex // serializes due checks so a plan falls due once
+}
+
+func NewMaintenanceService(
+	records repository.MaintenanceRepository,
+	plans repository.MaintenancePlanRepository,
+	vehicles *VehicleService,
+	alerts *AlertService,
+	publisher EventPublisher,
+	logger zerolog.Logger,
+) *MaintenanceService {
+	return &MaintenanceService{
+		records:   records,
+		plans:     plans,
+		vehicles:  vehicles,
+		alerts:    alerts,
+		publisher: publisher,
+		logger:    logger,
+		odometers: make(map[uuid.UUID]int),
+	}
+}
+
+// GetByVehicle returns a vehicle's maintenance records, latest first
+func (s *MaintenanceService) GetByVehicle(ctx context.Context, vehicleID uuid.UUID) ([]domain.MaintenanceRecord, error) {
+	if _, err := s.vehicles.GetByID(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	return s.records.ListByVehicle(ctx, vehicleID)
+}
+
+// GetByID returns a record, treating records of other vehicles as missing
+func (s *MaintenanceService) GetByID(ctx context.Context, vehicleID, id uuid.UUID) (*domain.MaintenanceRecord, error) {
+	record, err := s.records.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if record.VehicleID != vehicleID {
+		return nil, domain.ErrNotFound
+	}
+	return record, nil
+}
+
+// Create stores a record for its vehicle, scheduled unless the record says
+// otherwise
+func (s *MaintenanceService) Create(ctx context.Context, m *domain.MaintenanceRecord) (*domain.MaintenanceRecord, error) {
+	vehicle, err := s.vehicles.GetByID(ctx, m.VehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Status == "" {
+		m.Status = domain.MaintenanceStatusScheduled
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.validatePlan(ctx, m); err != nil {
+		return nil, err
+	}
+
+	m.ID = uuid.New()
+	m.CreatedAt = time.Now()
+	stampCompletion(m, vehicle)
+	if err := s.records.Create(ctx, m); err != nil {
+		return nil, err
+	}
+
+	s.applyRecord(ctx, "", m)
+	return m, nil
+}
+
+// Update replaces a record. Its status may only move forward: scheduled to
+// in_progress, and either to completed or cancelled.
+func (s *MaintenanceService) Update(ctx context.Context, m *domain.MaintenanceRecord) (*domain.MaintenanceRecord, error) {
+	existing, err := s.GetByID(ctx, m.VehicleID, m.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	if m.PlanID != nil && (existing.PlanID == nil || *existing.PlanID != *m.PlanID) {
+		if err := s.validatePlan(ctx, m); err != nil {
+			return nil, err
+		}
+	}
+	if !maintenanceTransitionAllowed(existing.Status, m.Status) {
+		return nil, fmt.Errorf("%w: cannot move a maintenance record from %s to %s", domain.ErrConflict, existing.Status, m.Status)
+	}
+
+	vehicle, err := s.vehicles.GetByID(ctx, m.VehicleID)
+	if err != nil {
+		return nil, err
+	}
+	m.CreatedAt = existing.CreatedAt
+	stampCompletion(m, vehicle)
+	if err := s.records.Update(ctx, m); err != nil {
+		return nil, err
+	}
+
+	s.applyRecord(ctx, existing.Status, m)
+	return m, nil
+}
+
+// Delete removes a record, releasing its vehicle from maintenance if the
+// record was in progress
+func (s *MaintenanceService) Delete(ctx context.Context, vehicleID, id uuid.UUID) error {
+	record, err := s.GetByID(ctx, vehicleID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.records.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	if record.Status == domain.MaintenanceStatusInProgress {
+		s.releaseVehicle(ctx, vehicleID, "maintenance record deleted")
+	}
+	return nil
+}
+
+// GetPlans returns a vehicle's maintenance plans
+func (s *MaintenanceService) GetPlans(ctx context.Context, vehicleID uuid.UUID) ([]domain.MaintenancePlan, error) {
+	if _, err := s.vehicles.GetByID(ctx, vehicleID); err != nil {
+		return nil, err
+	}
+	return s.plans.ListByVehicle(ctx, vehicleID)
+}
+
+// GetPlan returns a plan, treating plans of other vehicles as missing
+func (s *MaintenanceService) GetPlan(ctx context.Context, vehicleID, id uuid.UUID) (*domain.MaintenancePlan, error) {
+	plan, err := s.plans.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.VehicleID != vehicleID {
+		return nil, domain.ErrNotFound
+	}
+	return plan, nil
+}
+
+// CreatePlan stores a plan for its vehicle. Without a last service date the
+// plan counts from now and the vehicle's current odometer.
+func (s *MaintenanceService) CreatePlan(ctx context.Context, p *domain.MaintenancePlan) (*domain.MaintenancePlan, error) {
+	vehicle, err := s.vehicles.GetByID(ctx, p.VehicleID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	p.ID = uuid.New()
+	p.CreatedAt = time.Now()
+	p.DueSince = nil
+	if p.LastServiceDate.IsZero() {
+		p.LastServiceDate = p.CreatedAt
+		if p.LastServiceOdometer == 0 {
+			p.LastServiceOdometer = vehicle.Odometer
+		}
+	}
+	if err := s.plans.Create(ctx, p); err != nil {
+		return nil, err
+	}
+
+	s.checkPlans(ctx, vehicle, []domain.MaintenancePlan{*p}, time.Now())
+	return p, nil
+}
+
+// UpdatePlan replaces a plan; a plan that is no longer due can fall due
+// again
+func (s *MaintenanceService) UpdatePlan(ctx context.Context, p *domain.MaintenancePlan) (*domain.MaintenancePlan, error) {
+	existing, err := s.GetPlan(ctx, p.VehicleID, p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	vehicle, err := s.vehicles.GetByID(ctx, p.VehicleID)
+	if err != nil {
+		return nil, err
+	}
+
+	p.CreatedAt = existing.CreatedAt
+	now := time.Now()
+	if p.DueReason(vehicle.Odometer, now) == "" {
+		p.DueSince = nil
+	}
+	if err := s.plans.Update(ctx, p); err != nil {
+		return nil, err
+	}
+
+	s.checkPlans(ctx, vehicle, []domain.MaintenancePlan{*p}, now)
+	return p, nil
+}
+
+// DeletePlan removes a plan; records of past services keep their data
+func (s *MaintenanceService) DeletePlan(ctx context.Context, vehicleID, id uuid.UUID) error {
+	if _, err := s.GetPlan(ctx, vehicleID, id); err != nil {
+		return err
+	}
+	return s.plans.Delete(ctx, id)
+}
+
+// HandleEvent consumes vehicle.updated events, checking the vehicle's plans
+// whenever its odometer changes
+func (s *MaintenanceService) HandleEvent(ctx context.Context, event *domain.Event) {
+	var vehicle domain.Vehicle
+	if err := json.Unmarshal(event.Data, &vehicle); err != nil {
+		s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid vehicle event")
+		return
+	}
+
+	s.mu.Lock()
+	last, ok := s.odometers[vehicle.ID]
+	s.odometers[vehicle.ID] = vehicle.Odometer
+	s.mu.Unlock()
+	if ok && last == vehicle.Odometer {
+		return
+	}
+
+	plans, err := s.plans.ListByVehicle(ctx, vehicle.ID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("vehicleId", vehicle.ID.String()).Msg("Failed to load maintenance plans")
+		return
+	}
+	s.checkPlans(ctx, &vehicle, plans, time.Now())
+}
+
+// Run checks every plan for due dates until ctx is cancelled
+func (s *MaintenanceService) Run(ctx context.Context) {
+	ticker := time.NewTicker(maintenanceSweepInterval)
+	defer ticker.Stop()
+
+	s.sweep(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.sweep(ctx, now)
+		}
+	}
+}
+
+func (s *MaintenanceService) sweep(ctx context.Context, now time.Time) {
+	plans, err := s.plans.List(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to load maintenance plans")
+		return
+	}
+	vehicles, err := s.vehicles.GetAll(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to load vehicles")
+		return
+	}
+
+	byVehicle := make(map[uuid.UUID][]domain.MaintenancePlan)
+	for _, p := range plans {
+		byVehicle[p.VehicleID] = append(byVehicle[p.VehicleID], p)
+	}
+	for i := range vehicles {
+		if plans := byVehicle[vehicles[i].ID]; len(plans) > 0 {
+			s.checkPlans(ctx, &vehicles[i], plans, now)
+		}
+	}
+}
+
+// checkPlans marks the active plans of vehicle that have fallen due,
+// publishing maintenance.due and raising an alert for each
+func (s *MaintenanceService) checkPlans(ctx context.Context, vehicle *domain.Vehicle, plans []domain.MaintenancePlan, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range plans {
+		if !p.IsActive || p.DueSince != nil {
+			continue
+		}
+		reason := p.DueReason(vehicle.Odometer, now)
+		if reason == "" {
+			continue
+		}
+
+		// Re-read so a plan checked concurrently isn't reported twice
+		current, err := s.plans.GetByID(ctx, p.ID)
+		if err != nil || current.DueSince != nil {
+			continue
+		}
+		current.DueSince = &now
+		if err := s.plans.Update(ctx, current); err != nil {
+			s.logger.Error().Err(err).Str("planId", p.ID.String()).Msg("Failed to mark maintenance plan due")
+			continue
+		}
+
+		publishEvent(ctx, s.publisher, s.logger, domain.EventTypeMaintenanceDue, vehicle.ID.String(), domain.MaintenanceDueData{
+			VehicleID:       vehicle.ID,
+			PlanID:          current.ID,
+			Type:            current.Type,
+			Reason:          reason,
+			Odometer:        vehicle.Odometer,
+			NextDueOdometer: current.NextDueOdometer(),
+			NextDueDate:     current.NextDueDate(),
+		})
+
+		vehicleID := vehicle.ID
+		alert := &domain.Alert{
+			VehicleID: &vehicleID,
+			Type:      "maintenance_due",
+			Severity:  domain.AlertSeverityWarning,
+			Message:   fmt.Sprintf("%s is due for %s", vehicle.Name, current.Type),
+		}
+		if err := s.alerts.Raise(ctx, alert); err != nil {
+			s.logger.Error().Err(err).Str("planId", p.ID.String()).Msg("Failed to raise maintenance alert")
+		}
+	}
+}
+
+// validatePlan checks that a record's plan belongs to the same vehicle
+func (s *MaintenanceService) validatePlan(ctx context.Context, m *domain.MaintenanceRecord) error {
+	if m.PlanID == nil {
+		return nil
+	}
+
+	_, err := s.GetPlan(ctx, m.VehicleID, *m.PlanID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return &domain.ValidationError{Field: "planId", Message: "must be a maintenance plan of this vehicle"}
+	}
+	return err
+}
+
+// applyRecord carries a record's status change, from previous, over to its
+// vehicle and plan
+func (s *MaintenanceService) applyRecord(ctx context.Context, previous domain.MaintenanceStatus, m *domain.MaintenanceRecord) {
+	if previous == m.Status {
+		return
+	}
+
+	switch m.Status {
+	case domain.MaintenanceStatusInProgress:
+		if _, err := s.vehicles.SetStatus(ctx, m.VehicleID, domain.VehicleStatusMaintenance, "maintenance started: "+m.Type); err != nil {
+			s.logger.Error().Err(err).Str("vehicleId", m.VehicleID.String()).Msg("Failed to put vehicle in maintenance")
+		}
+		return
+	case domain.MaintenanceStatusCompleted:
+		s.resetPlan(ctx, m)
+	}
+
+	if previous == domain.MaintenanceStatusInProgress {
+		s.releaseVehicle(ctx, m.VehicleID, "maintenance "+string(m.Status)+": "+m.Type)
+	}
+}
+
+// resetPlan restarts the plan a completed record fulfils from that service
+func (s *MaintenanceService) resetPlan(ctx context.Context, m *domain.MaintenanceRecord) {
+	if m.PlanID == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	plan, err := s.plans.GetByID(ctx, *m.PlanID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("planId", m.PlanID.String()).Msg("Failed to load maintenance plan")
+		return
+	}
+	plan.LastServiceDate = *m.CompletedAt
+	plan.LastServiceOdometer = *m.Odometer
+	plan.DueSince = nil
+	if err := s.plans.Update(ctx, plan); err != nil {
+		s.logger.Error().Err(err).Str("planId", plan.ID.String()).Msg("Failed to reset maintenance plan")
+	}
+}
+
+// releaseVehicle returns a vehicle in maintenance to idle once none of its
+// records is in progress
+func (s *MaintenanceService) releaseVehicle(ctx context.Context, vehicleID uuid.UUID, reason string) {
+	records, err := s.records.ListByVehicle(ctx, vehicleID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to load maintenance records")
+		return
+	}
+	for _, r := range records {
+		if r.Status == domain.MaintenanceStatusInProgress {
+			return
+		}
+	}
+
+	vehicle, err := s.vehicles.GetByID(ctx, vehicleID)
+	if err != nil || vehicle.Status != domain.VehicleStatusMaintenance {
+		return
+	}
+	if _, err := s.vehicles.SetStatus(ctx, vehicleID, domain.VehicleStatusIdle, reason); err != nil {
+		s.logger.Error().Err(err).Str("vehicleId", vehicleID.String()).Msg("Failed to release vehicle from maintenance")
+	}
+}
+
+// stampCompletion stamps a completed record with its completion time and, when
+// missing, the vehicle's odometer
+func stampCompletion(m *domain.MaintenanceRecord, vehicle *domain.Vehicle) {
+	if m.Status != domain.MaintenanceStatusCompleted {
+		return
+	}
+	if m.CompletedAt == nil {
+		now := time.Now()
+		m.CompletedAt = &now
+	}
+	if m.Odometer == nil {
+		odometer := vehicle.Odometer
+		m.Odometer = &odometer
+	}
+}
+
+func maintenanceTransitionAllowed(from, to domain.MaintenanceStatus) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range maintenanceTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository/memory"
+	"github.com/sid-romero/fleetpulse/internal/validation"
+)
+
+// publisherFunc adapts a function to EventPublisher
+type publisherFunc func(ctx context.Context, event *domain.Event)
+
+func (f publisherFunc) Publish(ctx context.Context, event *domain.Event) { f(ctx, event) }
+
+func discardEvents(context.Context, *domain.Event) {}
+
+func newTestValidator(t *testing.T) *validation.Validator {
+	t.Helper()
+	validator, err := validation.NewValidator(config.ValidationConfig{
+		MaxClockSkew: 5 * time.Minute,
+		MaxSpeed:     300,
+		MinJump:      500,
+	})
+	if err != nil {
+		t.Fatalf("NewValidator: %v", err)
+	}
+	return validator
+}
+
+func TestTelemetryMakesDistancePlansDue(t *testing.T) {
+	tests := []struct {
+		name       string
+		intervalKm int
+		records    int // about 1 km apart
+		odometer   int
+		due        bool
+	}{
+		{name: "short of the interval", intervalKm: 10, records: 8, odometer: 1007, due: false},
+		{name: "past the interval", intervalKm: 5, records: 8, odometer: 1007, due: true},
+		{name: "reaching the interval", intervalKm: 3, records: 4, odometer: 1003, due: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := zerolog.Nop()
+			vehicleID := uuid.New()
+			vehicles := memory.NewVehicleRepository(domain.Vehicle{
+				ID:       vehicleID,
+				Name:     "Test vehicle",
+				Status:   domain.VehicleStatusIdle,
+				Odometer: 1000,
+			})
+			plans := memory.NewMaintenancePlanRepository()
+
+			maintenance := NewMaintenanceService(
+				memory.NewMaintenanceRepository(),
+				plans,
+				NewVehicleService(vehicles, publisherFunc(discardEvents), logger),
+				NewAlertService(memory.NewAlertRepository(), publisherFunc(discardEvents), logger),
+				publisherFunc(discardEvents),
+				logger,
+			)
+			intervalKm := tt.intervalKm
+			plan, err := maintenance.CreatePlan(ctx, &domain.MaintenancePlan{
+				VehicleID:  vehicleID,
+				Type:       "tire_rotation",
+				IntervalKm: &intervalKm,
+				IsActive:   true,
+			})
+			if err != nil {
+				t.Fatalf("CreatePlan: %v", err)
+			}
+
+			publisher := publisherFunc(func(ctx context.Context, event *domain.Event) {
+				if event.Type == domain.EventTypeVehicleUpdated {
+					maintenance.HandleEvent(ctx, event)
+				}
+			})
+			telemetry := NewTelemetryService(memory.NewTelemetryRepository(), vehicles, newTestValidator(t), 5*time.Minute, publisher, logger)
+
+			start := time.Now().Add(-time.Hour)
+			for i := 0; i < tt.records; i++ {
+				err := telemetry.Ingest(ctx, &domain.Telemetry{
+					VehicleID:    vehicleID,
+					Timestamp:    start.Add(time.Duration(i) * 30 * time.Second),
+					Location:     domain.Location{Lat: 40 + 0.009*float64(i), Lng: -3.7},
+					Speed:        120,
+					BatteryLevel: 80,
+				})
+				if err != nil {
+					t.Fatalf("Ingest record %d: %v", i, err)
+				}
+			}
+
+			vehicle, err := vehicles.GetByID(ctx, vehicleID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if vehicle.Odometer != tt.odometer {
+				t.Errorf("odometer = %d, want %d", vehicle.Odometer, tt.odometer)
+			}
+
+			plan, err = plans.GetByID(ctx, plan.ID)
+			if err != nil {
+				t.Fatalf("GetByID plan: %v", err)
+			}
+			if due := plan.DueSince != nil; due != tt.due {
+				t.Errorf("plan due = %v, want %v", due, tt.due)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/metrics"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// retentionTiers are pruned in this order, raw records first
+var retentionTiers = append([]domain.Resolution{domain.ResolutionRaw}, domain.RollupResolutions...)
+
+// RetentionService prunes telemetry older than the retention policy of its
+// tier and reports how much telemetry is stored
+type RetentionService struct {
+	telemetry repository.TelemetryRepository
+	policy    domain.RetentionPolicy
+	interval  time.Duration
+	logger    zerolog.Logger
+
+	lastRun *domain.RetentionRun
+	mu      sync.Mutex
+}
+
+func NewRetentionService(
+	telemetry repository.TelemetryRepository,
+	policy domain.RetentionPolicy,
+	interval time.Duration,
+	logger zerolog.Logger,
+) *RetentionService {
+	return &RetentionService{
+		telemetry: telemetry,
+		policy:    policy,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// GetReport returns the retention policy, the last pruning pass and the
+// telemetry stored per vehicle, largest first
+func (s *RetentionService) GetReport(ctx context.Context) (*domain.RetentionReport, error) {
+	storage, err := s.telemetry.Storage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.RetentionReport{
+		Policy:   s.policy,
+		Interval: s.interval.String(),
+		Vehicles: storage,
+	}
+	for _, vehicle := range storage {
+		report.TotalBytes += vehicle.EstimatedBytes
+	}
+
+	s.mu.Lock()
+	if s.lastRun != nil {
+		run := *s.lastRun
+		report.LastRun = &run
+	}
+	s.mu.Unlock()
+	return report, nil
+}
+
+// Run prunes telemetry every interval until ctx is cancelled
+func (s *RetentionService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.prune(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.prune(ctx, now)
+		}
+	}
+}
+
+func (s *RetentionService) prune(ctx context.Context, now time.Time) {
+	run := &domain.RetentionRun{StartedAt: now, Deleted: make(map[domain.Resolution]int64)}
+
+	for _, tier := range retentionTiers {
+		before, ok := s.policy.Cutoff(tier, now)
+		if !ok {
+			continue
+		}
+
+		deleted, err := s.telemetry.Prune(ctx, tier, before)
+		run.Deleted[tier] = deleted
+		metrics.RetentionRowsDeleted.Add(string(tier), deleted)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			run.Error = err.Error()
+			metrics.RetentionFailures.Add(1)
+			s.logger.Error().Err(err).Str("tier", string(tier)).Msg("Failed to prune telemetry")
+			break
+		}
+	}
+	run.FinishedAt = time.Now()
+	metrics.RetentionRuns.Add(1)
+
+	s.logger.Info().
+		Interface("deleted", run.Deleted).
+		Dur("duration", run.FinishedAt.Sub(run.StartedAt)).
+		Msg("Pruned telemetry")
+
+	s.mu.Lock()
+	s.lastRun = run
+	s.mu.Unlock()
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// rollupChunk bounds the range recomputed by a single repository call while
+// catching up on a backlog
+const rollupChunk = 24 * time.Hour
+
+// RollupService keeps the telemetry rollups of every resolution up to date.
+// Each pass recomputes the buckets since the last pass, and those within
+// lateness of now so records that arrive late are folded in.
+type RollupService struct {
+	telemetry repository.TelemetryRepository
+	interval  time.Duration
+	lateness  time.Duration
+	logger    zerolog.Logger
+
+	// next is the first bucket of each resolution still to be computed,
+	// unset until the repository has telemetry
+	next map[domain.Resolution]time.Time
+}
+
+func NewRollupService(
+	telemetry repository.TelemetryRepository,
+	interval, lateness time.Duration,
+	logger zerolog.Logger,
+) *RollupService {
+	return &RollupService{
+		telemetry: telemetry,
+		interval:  interval,
+		lateness:  lateness,
+		logger:    logger,
+		next:      make(map[domain.Resolution]time.Time),
+	}
+}
+
+// Run rolls telemetry up every interval until ctx is cancelled
+func (s *RollupService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.rollupAll(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.rollupAll(ctx, now)
+		}
+	}
+}
+
+func (s *RollupService) rollupAll(ctx context.Context, now time.Time) {
+	for _, resolution := range domain.RollupResolutions {
+		if err := s.rollup(ctx, resolution, now); err != nil && ctx.Err() == nil {
+			s.logger.Error().Err(err).Str("resolution", string(resolution)).Msg("Failed to roll up telemetry")
+		}
+	}
+}
+
+// rollup recomputes the buckets of resolution from the earlier of next and
+// now-lateness up to and including the bucket in progress
+func (s *RollupService) rollup(ctx context.Context, resolution domain.Resolution, now time.Time) error {
+	interval := resolution.Interval()
+
+	next, ok := s.next[resolution]
+	if !ok {
+		watermark, err := s.telemetry.RollupWatermark(ctx, resolution)
+		if err != nil {
+			return err
+		}
+		if watermark.IsZero() {
+			return nil
+		}
+		next = watermark.Truncate(interval)
+	}
+
+	current := now.Truncate(interval)
+	from := next
+	if late := now.Add(-s.lateness).Truncate(interval); late.Before(from) {
+		from = late
+	}
+	to := current.Add(interval)
+
+	written := 0
+	for start := from; start.Before(to); start = start.Add(rollupChunk) {
+		end := start.Add(rollupChunk)
+		if end.After(to) {
+			end = to
+		}
+		n, err := s.telemetry.Rollup(ctx, resolution, start, end)
+		if err != nil {
+			return err
+		}
+		written += n
+		s.next[resolution] = end // resume after the chunks already done
+	}
+
+	// The bucket in progress is recomputed by the next pass
+	s.next[resolution] = current
+
+	s.logger.Debug().
+		Str("resolution", string(resolution)).
+		Time("from", from).
+		Time("to", to).
+		Int("rollups", written).
+		Msg("Rolled up telemetry")
+	return nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+	"github.com/sid-romero/fleetpulse/internal/scoring"
+)
+
+// ScoringService detects risky driving in telemetry, stores it against the
+// driver assigned at the time and scores drivers from it. Driver ratings are
+// kept in step with their score over the scoring window.
+type ScoringService struct {
+	events   repository.DrivingEventRepository
+	trips    repository.TripRepository
+	drivers  *DriverService
+	detector *scoring.Detector
+	window   time.Duration
+	interval time.Duration
+	logger   zerolog.Logger
+}
+
+func NewScoringService(
+	events repository.DrivingEventRepository,
+	trips repository.TripRepository,
+	drivers *DriverService,
+	detector *scoring.Detector,
+	window time.Duration,
+	interval time.Duration,
+	logger zerolog.Logger,
+) *ScoringService {
+	return &ScoringService{
+		events:   events,
+		trips:    trips,
+		drivers:  drivers,
+		detector: detector,
+		window:   window,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Window is the period scores cover by default
+func (s *ScoringService) Window() time.Duration {
+	return s.window
+}
+
+// GetScore scores a driver on their events and trips within [from, to)
+func (s *ScoringService) GetScore(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*domain.DriverScore, error) {
+	if _, err := s.drivers.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+	return s.score(ctx, driverID, from, to)
+}
+
+// GetEvents returns a driver's events within [from, to), latest first
+func (s *ScoringService) GetEvents(ctx context.Context, driverID uuid.UUID, from, to time.Time) ([]domain.DrivingEvent, error) {
+	if _, err := s.drivers.GetByID(ctx, driverID); err != nil {
+		return nil, err
+	}
+	return s.events.ListByDriver(ctx, driverID, from, to)
+}
+
+// HandleEvent consumes telemetry.received events
+func (s *ScoringService) HandleEvent(ctx context.Context, event *domain.Event) {
+	var data domain.TelemetryEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid telemetry event")
+		return
+	}
+
+	for _, detected := range s.detector.Update(&data.Telemetry) {
+		s.record(ctx, detected)
+	}
+}
+
+// Run refreshes driver ratings every interval until ctx is cancelled
+func (s *ScoringService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	s.refreshRatings(ctx, time.Now())
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.refreshRatings(ctx, now)
+		}
+	}
+}
+
+// record attributes an event to the driver assigned when it occurred and
+// stores it; events of vehicles without a driver are dropped
+func (s *ScoringService) record(ctx context.Context, e domain.DrivingEvent) {
+	assignment, err := s.drivers.AssignmentAt(ctx, e.VehicleID, e.OccurredAt)
+	if errors.Is(err, domain.ErrNotFound) {
+		s.logger.Debug().
+			Str("vehicleId", e.VehicleID.String()).
+			Str("type", string(e.Type)).
+			Msg("Dropped driving event of a vehicle without a driver")
+		return
+	}
+	if err != nil {
+		s.logger.Error().Err(err).Str("vehicleId", e.VehicleID.String()).Msg("Failed to attribute driving event")
+		return
+	}
+
+	e.ID = uuid.New()
+	e.DriverID = assignment.DriverID
+	if err := s.events.Create(ctx, &e); err != nil {
+		s.logger.Error().Err(err).
+			Str("vehicleId", e.VehicleID.String()).
+			Str("driverId", e.DriverID.String()).
+			Msg("Failed to store driving event")
+	}
+}
+
+func (s *ScoringService) score(ctx context.Context, driverID uuid.UUID, from, to time.Time) (*domain.DriverScore, error) {
+	events, err := s.events.ListByDriver(ctx, driverID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	trips, err := s.trips.ListByDriver(ctx, driverID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	distance := 0.0
+	for _, t := range trips {
+		distance += t.DistanceKm
+	}
+	score := scoring.Score(driverID, events, distance)
+	score.From, score.To = from, to
+	return &score, nil
+}
+
+// refreshRatings sets each driver's rating from their score over the
+// window ending at now
+func (s *ScoringService) refreshRatings(ctx context.Context, now time.Time) {
+	drivers, err := s.drivers.GetAll(ctx)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to list drivers for rating")
+		return
+	}
+
+	for i := range drivers {
+		driver := &drivers[i]
+		score, err := s.score(ctx, driver.ID, now.Add(-s.window), now)
+		if err != nil {
+			s.logger.Error().Err(err).Str("driverId", driver.ID.String()).Msg("Failed to score driver")
+			continue
+		}
+		if score.Rating == driver.Rating {
+			continue
+		}
+
+		err = s.drivers.SetRating(ctx, driver.ID, score.Rating)
+		if err != nil && !errors.Is(err, domain.ErrNotFound) && ctx.Err() == nil {
+			s.logger.Error().Err(err).Str("driverId", driver.ID.String()).Msg("Failed to update driver rating")
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository/memory"
+)
+
+// editedDrivers applies edit to every driver right after listing them, as
+// if an operator had saved a change while the list was being scored
+type editedDrivers struct {
+	*memory.DriverRepository
+	edit func(ctx context.Context, r *memory.DriverRepository, driver domain.Driver)
+}
+
+func (r *editedDrivers) List(ctx context.Context) ([]domain.Driver, error) {
+	drivers, err := r.DriverRepository.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drivers {
+		r.edit(ctx, r.DriverRepository, d)
+	}
+	return drivers, nil
+}
+
+func TestRefreshRatingsKeepsDriverEdits(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func(ctx context.Context, r *memory.DriverRepository, driver domain.Driver)
+		deleted bool
+	}{
+		{
+			name: "renamed",
+			edit: func(ctx context.Context, r *memory.DriverRepository, driver domain.Driver) {
+				driver.Name, driver.Phone = "Renamed driver", "+34 600 000 000"
+				if err := r.Update(ctx, &driver); err != nil {
+					t.Fatalf("Update: %v", err)
+				}
+			},
+		},
+		{
+			name: "deleted",
+			edit: func(ctx context.Context, r *memory.DriverRepository, driver domain.Driver) {
+				if err := r.Delete(ctx, driver.ID); err != nil {
+					t.Fatalf("Delete: %v", err)
+				}
+			},
+			deleted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			logger := zerolog.Nop()
+			driverID := uuid.New()
+			drivers := &editedDrivers{
+				DriverRepository: memory.NewDriverRepository(domain.Driver{ID: driverID, Name: "Test driver", Rating: 3}),
+				edit:             tt.edit,
+			}
+			driverService := NewDriverService(drivers, memory.NewDriverAssignmentRepository(),
+				NewVehicleService(memory.NewVehicleRepository(), publisherFunc(discardEvents), logger),
+				publisherFunc(discardEvents), logger)
+			scoringService := NewScoringService(memory.NewDrivingEventRepository(), memory.NewTripRepository(),
+				driverService, nil, 30*24*time.Hour, time.Hour, logger)
+
+			scoringService.refreshRatings(ctx, time.Now())
+
+			stored, err := drivers.GetByID(ctx, driverID)
+			if tt.deleted {
+				if err == nil {
+					t.Errorf("deleted driver was saved again: %+v", stored)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if stored.Name != "Renamed driver" || stored.Phone != "+34 600 000 000" {
+				t.Errorf("driver = %+v, want the edit kept", stored)
+			}
+			if stored.Rating != 5 {
+				t.Errorf("rating = %v, want 5 for a driver without events", stored.Rating)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+	"github.com/sid-romero/fleetpulse/internal/repository/memory"
+)
+
+// racingVehicles applies a telemetry update before each status write, as if
+// it had landed between the service reading the vehicle and saving it
+type racingVehicles struct {
+	*memory.VehicleRepository
+	races []repository.VehicleLiveState
+}
+
+func (r *racingVehicles) SetStatus(ctx context.Context, id uuid.UUID, from, to domain.VehicleStatus) (*domain.Vehicle, error) {
+	if len(r.races) > 0 {
+		if _, err := r.UpdateLiveState(ctx, id, r.races[0]); err != nil {
+			return nil, err
+		}
+		r.races = r.races[1:]
+	}
+	return r.VehicleRepository.SetStatus(ctx, id, from, to)
+}
+
+func TestVehicleServiceSetStatusKeepsTelemetry(t *testing.T) {
+	moved := func(from, to domain.VehicleStatus) repository.VehicleLiveState {
+		return repository.VehicleLiveState{From: from, Status: to, Location: domain.Location{Lat: 40.4, Lng: -3.7}, DistanceKm: 2}
+	}
+
+	tests := []struct {
+		name     string
+		status   domain.VehicleStatus
+		races    []repository.VehicleLiveState
+		wantErr  error
+		previous domain.VehicleStatus // of the status change published
+		odometer int
+	}{
+		{
+			name:     "no telemetry meanwhile",
+			status:   domain.VehicleStatusMaintenance,
+			previous: domain.VehicleStatusIdle,
+			odometer: 1000,
+		},
+		{
+			name:     "telemetry without a status change",
+			status:   domain.VehicleStatusMaintenance,
+			races:    []repository.VehicleLiveState{moved(domain.VehicleStatusIdle, domain.VehicleStatusIdle)},
+			previous: domain.VehicleStatusIdle,
+			odometer: 1002,
+		},
+		{
+			name:     "status changed by telemetry",
+			status:   domain.VehicleStatusMaintenance,
+			races:    []repository.VehicleLiveState{moved(domain.VehicleStatusIdle, domain.VehicleStatusActive)},
+			previous: domain.VehicleStatusActive,
+			odometer: 1002,
+		},
+		{
+			name:     "status changed to one that can't move on",
+			status:   domain.VehicleStatusCharging,
+			races:    []repository.VehicleLiveState{moved(domain.VehicleStatusIdle, domain.VehicleStatusActive)},
+			wantErr:  domain.ErrConflict,
+			odometer: 1002,
+		},
+		{
+			name:   "status changing on every attempt",
+			status: domain.VehicleStatusMaintenance,
+			races: []repository.VehicleLiveState{
+				moved(domain.VehicleStatusIdle, domain.VehicleStatusActive),
+				moved(domain.VehicleStatusActive, domain.VehicleStatusIdle),
+				moved(domain.VehicleStatusIdle, domain.VehicleStatusActive),
+			},
+			wantErr:  domain.ErrConflict,
+			odometer: 1006,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			vehicleID := uuid.New()
+			vehicles := &racingVehicles{
+				VehicleRepository: memory.NewVehicleRepository(domain.Vehicle{
+					ID:       vehicleID,
+					Name:     "Test vehicle",
+					Status:   domain.VehicleStatusIdle,
+					Odometer: 1000,
+				}),
+				races: tt.races,
+			}
+			var changes []domain.VehicleStatusChangeData
+			publisher := publisherFunc(func(ctx context.Context, event *domain.Event) {
+				if event.Type != domain.EventTypeVehicleStatusChange {
+					return
+				}
+				var change domain.VehicleStatusChangeData
+				if err := json.Unmarshal(event.Data, &change); err != nil {
+					t.Fatalf("decode status change: %v", err)
+				}
+				changes = append(changes, change)
+			})
+
+			_, err := NewVehicleService(vehicles, publisher, zerolog.Nop()).SetStatus(ctx, vehicleID, tt.status, "test")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetStatus() = %v, want %v", err, tt.wantErr)
+			}
+
+			stored, err := vehicles.GetByID(ctx, vehicleID)
+			if err != nil {
+				t.Fatalf("GetByID: %v", err)
+			}
+			if stored.Odometer != tt.odometer {
+				t.Errorf("odometer = %d, want %d", stored.Odometer, tt.odometer)
+			}
+			if tt.wantErr != nil {
+				if len(changes) != 0 {
+					t.Errorf("published %d status changes, want none", len(changes))
+				}
+				return
+			}
+			if stored.Status != tt.status {
+				t.Errorf("status = %s, want %s", stored.Status, tt.status)
+			}
+			if len(changes) != 1 || changes[0].PreviousStatus != tt.previous || changes[0].NewStatus != tt.status {
+				t.Errorf("status changes = %+v, want one from %s to %s", changes, tt.previous, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/auth"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/metrics"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+	"github.com/sid-romero/fleetpulse/internal/validation"
+)
+
+// TelemetryService runs the ingestion pipeline: each record is validated,
+// against the vehicle's latest record too, stored as history and applied to
+// the vehicle's live state, then published as a telemetry.received event
+// for downstream consumers. The vehicle's status follows its telemetry: it
+// turns active when moving, charging while drawing current and idle after
+// standing still for idleTimeout. Its odometer grows by the distance
+// between consecutive records.
+type TelemetryService struct {
+	telemetry   repository.TelemetryRepository
+	vehicles    repository.VehicleRepository
+	validator   *validation.Validator
+	idleTimeout time.Duration
+	publisher   EventPublisher
+	logger      zerolog.Logger
+
+	// latest holds the newest record applied to each vehicle so late or
+	// replayed records don't overwrite fresher state
+	latest map[uuid.UUID]domain.Telemetry
+	// stationarySince holds when each vehicle stopped moving, absent while
+	// it moves
+	stationarySince map[uuid.UUID]time.Time
+	// travelled holds the meters each vehicle has covered that are not on
+	// its odometer yet, which counts whole km
+	travelled map[uuid.UUID]float64
+	mu        sync.Mutex
+}
+
+func NewTelemetryService(
+	telemetry repository.TelemetryRepository,
+	vehicles repository.VehicleRepository,
+	validator *validation.Validator,
+	idleTimeout time.Duration,
+	publisher EventPublisher,
+	logger zerolog.Logger,
+) *TelemetryService {
+	return &TelemetryService{
+		telemetry:       telemetry,
+		vehicles:        vehicles,
+		validator:       validator,
+		idleTimeout:     idleTimeout,
+		publisher:       publisher,
+		logger:          logger,
+		latest:          make(map[uuid.UUID]domain.Telemetry),
+		stationarySince: make(map[uuid.UUID]time.Time),
+		travelled:       make(map[uuid.UUID]float64),
+	}
+}
+
+func (s *TelemetryService) GetByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.Telemetry, error) {
+	return s.telemetry.ListByVehicle(ctx, vehicleID, from, to)
+}
+
+// GetRollups returns a vehicle's telemetry rollups of resolution, starting
+// with the bucket that contains from
+func (s *TelemetryService) GetRollups(ctx context.Context, vehicleID uuid.UUID, resolution domain.Resolution, from, to time.Time) ([]domain.TelemetryRollup, error) {
+	interval := resolution.Interval()
+	if interval == 0 {
+		return nil, &domain.ValidationError{Field: "resolution", Message: "must be 1m, 15m or 1h"}
+	}
+	return s.telemetry.ListRollups(ctx, vehicleID, resolution, from.Truncate(interval), to)
+}
+
+// Ingest stores a single record and updates the vehicle's live state.
+// It returns a *domain.TelemetryError, which is a *domain.ValidationError,
+// for malformed or implausible records, domain.ErrNotFound when the vehicle
+// is unknown and domain.ErrForbidden when a device reports for a vehicle
+// other than its own. Rejected records are counted in
+// metrics.TelemetryRejected.
+func (s *TelemetryService) Ingest(ctx context.Context, telemetry *domain.Telemetry) error {
+	vehicles := make(map[uuid.UUID]*domain.Vehicle, 1)
+	prepareTelemetry(telemetry)
+	if err := s.admit(ctx, telemetry, vehicles, make(map[uuid.UUID]*domain.Telemetry, 1)); err != nil {
+		return err
+	}
+	vehicle := vehicles[telemetry.VehicleID]
+
+	if err := s.telemetry.Insert(ctx, telemetry); err != nil {
+		return err
+	}
+
+	previous, ok := s.advance(telemetry)
+	if !ok {
+		return nil
+	}
+	if err := s.updateLiveState(ctx, vehicle, telemetry); err != nil {
+		return err
+	}
+	s.publishTelemetry(ctx, vehicle, telemetry, previous)
+	return nil
+}
+
+// BatchIngest validates every record before storing any of them. Records
+// are checked in timestamp order, each against the record before it from
+// the same vehicle, the first against the vehicle's latest record from
+// before the batch. When records are rejected it stores none and returns
+// the errors Ingest would for each of them, as *domain.RecordError joined
+// together. Each record is published in timestamp order, but only the
+// newest record per vehicle is applied to live state.
+func (s *TelemetryService) BatchIngest(ctx context.Context, telemetry []domain.Telemetry) error {
+	order := make([]int, len(telemetry))
+	for i := range telemetry {
+		prepareTelemetry(&telemetry[i])
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return telemetry[order[a]].Timestamp.Before(telemetry[order[b]].Timestamp)
+	})
+
+	vehicles := make(map[uuid.UUID]*domain.Vehicle)
+	previous := make(map[uuid.UUID]*domain.Telemetry)
+	var rejected []*domain.RecordError
+	for _, i := range order {
+		err := s.admit(ctx, &telemetry[i], vehicles, previous)
+		if _, ok := domain.RejectReasonOf(err); ok {
+			rejected = append(rejected, &domain.RecordError{Index: i, Err: err})
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("record %d: %w", i, err)
+		}
+	}
+	if len(rejected) > 0 {
+		sort.Slice(rejected, func(a, b int) bool { return rejected[a].Index < rejected[b].Index })
+		errs := make([]error, len(rejected))
+		for i, err := range rejected {
+			errs[i] = err
+		}
+		return errors.Join(errs...)
+	}
+	return s.store(ctx, telemetry, vehicles)
+}
+
+// store stores admitted records of the given vehicles, then publishes them
+// and applies them to live state
+func (s *TelemetryService) store(ctx context.Context, telemetry []domain.Telemetry, vehicles map[uuid.UUID]*domain.Vehicle) error {
+	if err := s.telemetry.InsertBatch(ctx, telemetry); err != nil {
+		return err
+	}
+
+	ordered := make([]*domain.Telemetry, len(telemetry))
+	for i := range telemetry {
+		ordered[i] = &telemetry[i]
+	}
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Timestamp.Before(ordered[j].Timestamp) })
+
+	latest := make(map[uuid.UUID]*domain.Telemetry, len(vehicles))
+	for _, t := range ordered {
+		previous, ok := s.advance(t)
+		if !ok {
+			continue
+		}
+		latest[t.VehicleID] = t
+		s.publishTelemetry(ctx, vehicles[t.VehicleID], t, previous)
+	}
+
+	// Apply in a stable order so updates are deterministic
+	ids := make([]uuid.UUID, 0, len(latest))
+	for id := range latest {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+
+	for _, id := range ids {
+		if err := s.updateLiveState(ctx, vehicles[id], latest[id]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// admit checks that a prepared record may be stored, looking up its vehicle
+// unless it is already in vehicles. Its move is checked against the
+// vehicle's record in previous or, when there is none, the vehicle's latest
+// record; once admitted it takes that place in previous. Rejections are
+// counted.
+func (s *TelemetryService) admit(ctx context.Context, telemetry *domain.Telemetry, vehicles map[uuid.UUID]*domain.Vehicle, previous map[uuid.UUID]*domain.Telemetry) error {
+	err := s.check(ctx, telemetry, vehicles, previous)
+	if reason, ok := domain.RejectReasonOf(err); ok {
+		metrics.TelemetryRejected.Add(string(reason), 1)
+	}
+	if err != nil {
+		return err
+	}
+
+	admitted := *telemetry
+	previous[telemetry.VehicleID] = &admitted
+	return nil
+}
+
+func (s *TelemetryService) check(ctx context.Context, telemetry *domain.Telemetry, vehicles map[uuid.UUID]*domain.Vehicle, previous map[uuid.UUID]*domain.Telemetry) error {
+	if err := authorizeDevice(ctx, telemetry); err != nil {
+		return err
+	}
+	last, ok := previous[telemetry.VehicleID]
+	if !ok {
+		last = s.previous(telemetry.VehicleID)
+	}
+	if err := s.validator.Check(telemetry, last, time.Now()); err != nil {
+		return err
+	}
+
+	if _, ok := vehicles[telemetry.VehicleID]; ok {
+		return nil
+	}
+	vehicle, err := s.vehicles.GetByID(ctx, telemetry.VehicleID)
+	if err != nil {
+		return err
+	}
+	vehicles[telemetry.VehicleID] = vehicle
+	return nil
+}
+
+// previous returns the vehicle's latest record, or nil before its first
+func (s *TelemetryService) previous(vehicleID uuid.UUID) *domain.Telemetry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, ok := s.latest[vehicleID]
+	if !ok {
+		return nil
+	}
+	return &last
+}
+
+// advance records telemetry as the vehicle's newest and returns the record it
+// replaces. It reports false for records older than the newest one; those
+// are kept as history but neither applied nor published.
+func (s *TelemetryService) advance(telemetry *domain.Telemetry) (*domain.Telemetry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last, ok := s.latest[telemetry.VehicleID]
+	if ok && telemetry.Timestamp.Before(last.Timestamp) {
+		return nil, false
+	}
+	s.latest[telemetry.VehicleID] = *telemetry
+	if telemetry.Speed > 0 {
+		delete(s.stationarySince, telemetry.VehicleID)
+	} else if _, stopped := s.stationarySince[telemetry.VehicleID]; !stopped {
+		s.stationarySince[telemetry.VehicleID] = telemetry.Timestamp
+	}
+	if !ok {
+		return nil, true
+	}
+	s.travelled[telemetry.VehicleID] += domain.DistanceMeters(last.Location, telemetry.Location)
+	return &last, true
+}
+
+// takeDistance returns the whole km a vehicle has travelled since the last
+// call, keeping the remainder for the next
+func (s *TelemetryService) takeDistance(vehicleID uuid.UUID) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	km := int(s.travelled[vehicleID] / 1000)
+	s.travelled[vehicleID] -= float64(km) * 1000
+	return km
+}
+
+// returnDistance puts back km that could not be added to the odometer
+func (s *TelemetryService) returnDistance(vehicleID uuid.UUID, km int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.travelled[vehicleID] += float64(km) * 1000
+}
+
+// stationaryFor returns how long a vehicle had been standing still when
+// telemetry was recorded
+func (s *TelemetryService) stationaryFor(telemetry *domain.Telemetry) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	since, ok := s.stationarySince[telemetry.VehicleID]
+	if !ok {
+		return 0
+	}
+	return telemetry.Timestamp.Sub(since)
+}
+
+// updateLiveState saves a record as the vehicle's live state, advances its
+// odometer, moves it to the status the record implies and publishes the
+// changes. Only the fields telemetry feeds are written, so a driver assigned
+// or a status set in the meantime is kept; vehicle is refreshed with what
+// was saved.
+func (s *TelemetryService) updateLiveState(ctx context.Context, vehicle *domain.Vehicle, telemetry *domain.Telemetry) error {
+	previous := vehicle.Status
+	status, reason := domain.InferStatus(previous, telemetry, s.stationaryFor(telemetry), s.idleTimeout)
+	path := previous.PathTo(status)
+	if len(path) == 0 {
+		status = previous
+	}
+
+	distance := s.takeDistance(vehicle.ID)
+	saved, err := s.vehicles.UpdateLiveState(ctx, vehicle.ID, repository.VehicleLiveState{
+		From:         previous,
+		Status:       status,
+		Location:     telemetry.Location,
+		Speed:        telemetry.Speed,
+		BatteryLevel: telemetry.BatteryLevel,
+		FuelLevel:    telemetry.FuelLevel,
+		Temperature:  telemetry.EngineTemp,
+		DistanceKm:   distance,
+	})
+	if err != nil {
+		s.returnDistance(vehicle.ID, distance)
+		return err
+	}
+	*vehicle = *saved
+
+	publishEvent(ctx, s.publisher, s.logger, domain.EventTypeVehicleUpdated, vehicle.ID.String(), vehicle)
+	if vehicle.Status != status {
+		// the status changed meanwhile and was kept
+		return nil
+	}
+	for _, next := range path {
+		publishStatusChange(ctx, s.publisher, s.logger, vehicle.ID, previous, next, reason, telemetry.Timestamp)
+		previous = next
+	}
+	return nil
+}
+
+func (s *TelemetryService) publishTelemetry(ctx context.Context, vehicle *domain.Vehicle, telemetry, previous *domain.Telemetry) {
+	snapshot := *vehicle
+	publishEvent(ctx, s.publisher, s.logger, domain.EventTypeTelemetryReceived, vehicle.ID.String(), domain.TelemetryEventData{
+		VehicleID:    vehicle.ID,
+		Telemetry:    *telemetry,
+		PreviousData: previous,
+		Vehicle:      &snapshot,
+	})
+}
+
+// authorizeDevice binds records sent with a device key to the key's
+// vehicle: a missing vehicle ID is filled in and any other is rejected
+func authorizeDevice(ctx context.Context, telemetry *domain.Telemetry) error {
+	key, ok := auth.DeviceFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	if telemetry.VehicleID == uuid.Nil {
+		telemetry.VehicleID = key.VehicleID
+	}
+	if telemetry.VehicleID != key.VehicleID {
+		return fmt.Errorf("%w: device key is bound to vehicle %s", domain.ErrForbidden, key.VehicleID)
+	}
+	return nil
+}
+
+func prepareTelemetry(telemetry *domain.Telemetry) {
+	telemetry.ID = uuid.New()
+	if telemetry.Timestamp.IsZero() {
+		telemetry.Timestamp = time.Now()
+	}
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// streamChunkSize is the number of records a TelemetryStream stores at once
+const streamChunkSize = 1000
+
+// TelemetryStream ingests a batch of any size record by record. Each record
+// is checked as it is added, so a bad record is rejected on its own, and
+// accepted records are stored a chunk at a time.
+type TelemetryStream struct {
+	service  *TelemetryService
+	vehicles map[uuid.UUID]*domain.Vehicle   // vehicles known to exist
+	previous map[uuid.UUID]*domain.Telemetry // last record added per vehicle
+	pending  []domain.Telemetry
+	accepted int
+}
+
+// NewStream starts a TelemetryStream
+func (s *TelemetryService) NewStream() *TelemetryStream {
+	return &TelemetryStream{
+		service:  s,
+		vehicles: make(map[uuid.UUID]*domain.Vehicle),
+		previous: make(map[uuid.UUID]*domain.Telemetry),
+	}
+}
+
+// Add checks a record and queues it for storage. Its move is checked
+// against the vehicle's last record admitted to the stream, or its latest
+// record before the stream. A rejected record returns the errors Ingest
+// would and leaves the stream usable.
+func (t *TelemetryStream) Add(ctx context.Context, telemetry *domain.Telemetry) error {
+	prepareTelemetry(telemetry)
+	if err := t.service.admit(ctx, telemetry, t.vehicles, t.previous); err != nil {
+		return err
+	}
+	t.pending = append(t.pending, *telemetry)
+	return nil
+}
+
+// Full reports whether the queued records should be flushed before adding
+// more
+func (t *TelemetryStream) Full() bool {
+	return len(t.pending) >= streamChunkSize
+}
+
+// Flush stores the queued records, which are dropped if that fails
+func (t *TelemetryStream) Flush(ctx context.Context) error {
+	pending := t.pending
+	t.pending = nil
+	if len(pending) == 0 {
+		return nil
+	}
+
+	// Reload the vehicles so live state builds on their current status
+	vehicles := make(map[uuid.UUID]*domain.Vehicle)
+	for i := range pending {
+		id := pending[i].VehicleID
+		if _, ok := vehicles[id]; ok {
+			continue
+		}
+		vehicle, err := t.service.vehicles.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		vehicles[id] = vehicle
+	}
+
+	if err := t.service.store(ctx, pending, vehicles); err != nil {
+		return err
+	}
+	t.accepted += len(pending)
+	return nil
+}
+
+// Accepted returns the number of records stored so far
+func (t *TelemetryStream) Accepted() int {
+	return t.accepted
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository/memory"
+)
+
+// newTestTelemetryService returns a TelemetryService over a single idle
+// vehicle
+func newTestTelemetryService(t *testing.T) (*TelemetryService, uuid.UUID) {
+	t.Helper()
+	vehicleID := uuid.New()
+	vehicles := memory.NewVehicleRepository(domain.Vehicle{ID: vehicleID, Name: "Test vehicle", Status: domain.VehicleStatusIdle})
+	return NewTelemetryService(memory.NewTelemetryRepository(), vehicles, newTestValidator(t),
+		5*time.Minute, publisherFunc(discardEvents), zerolog.Nop()), vehicleID
+}
+
+// position is a record some minutes into the test at a latitude
+type position struct {
+	minute int
+	lat    float64
+}
+
+func TestBatchIngestChecksJumpsWithinTheBatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		records  []position
+		rejected []int // indexes rejected as jumps
+	}{
+		{
+			name:    "steady drive",
+			records: []position{{0, 40}, {1, 40.018}, {2, 40.036}},
+		},
+		{
+			name:    "steady drive out of order",
+			records: []position{{2, 40.036}, {0, 40}, {1, 40.018}},
+		},
+		{
+			name:     "jump between consecutive records",
+			records:  []position{{0, 40}, {1, 40.018}, {2, 41}},
+			rejected: []int{2},
+		},
+		{
+			name:     "jump sent first",
+			records:  []position{{1, 41}, {0, 40}},
+			rejected: []int{0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, vehicleID := newTestTelemetryService(t)
+			start := time.Now().Add(-time.Hour)
+			batch := make([]domain.Telemetry, len(tt.records))
+			for i, p := range tt.records {
+				batch[i] = domain.Telemetry{
+					VehicleID:    vehicleID,
+					Timestamp:    start.Add(time.Duration(p.minute) * time.Minute),
+					Location:     domain.Location{Lat: p.lat, Lng: -3.7},
+					Speed:        60,
+					BatteryLevel: 80,
+				}
+			}
+
+			err := service.BatchIngest(context.Background(), batch)
+			if got := jumpIndexes(t, err); !equalInts(got, tt.rejected) {
+				t.Errorf("rejected %v as jumps, want %v (err %v)", got, tt.rejected, err)
+			}
+		})
+	}
+}
+
+func TestTelemetryStreamChecksJumpsWithinTheStream(t *testing.T) {
+	service, vehicleID := newTestTelemetryService(t)
+	ctx := context.Background()
+	stream := service.NewStream()
+	start := time.Now().Add(-time.Hour)
+
+	lines := []struct {
+		position
+		jump bool
+	}{
+		{position{0, 40}, false},
+		{position{1, 40.018}, false},
+		{position{2, 41}, true},
+		{position{3, 40.054}, false}, // plausible from the last record admitted
+	}
+	for i, line := range lines {
+		err := stream.Add(ctx, &domain.Telemetry{
+			VehicleID:    vehicleID,
+			Timestamp:    start.Add(time.Duration(line.minute) * time.Minute),
+			Location:     domain.Location{Lat: line.lat, Lng: -3.7},
+			Speed:        60,
+			BatteryLevel: 80,
+		})
+		reason, _ := domain.RejectReasonOf(err)
+		if jump := reason == domain.RejectJump; jump != line.jump {
+			t.Errorf("line %d: jump = %v, want %v (err %v)", i, jump, line.jump, err)
+		}
+	}
+	if err := stream.Flush(ctx); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if stream.Accepted() != 3 {
+		t.Errorf("accepted %d records, want 3", stream.Accepted())
+	}
+}
+
+// jumpIndexes returns the indexes of the records err rejected as jumps
+func jumpIndexes(t *testing.T, err error) []int {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var indexes []int
+	for _, err := range joined.Unwrap() {
+		var recordErr *domain.RecordError
+		if !errors.As(err, &recordErr) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if reason, _ := domain.RejectReasonOf(recordErr.Err); reason == domain.RejectJump {
+			indexes = append(indexes, recordErr.Index)
+		}
+	}
+	return indexes
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+	"github.com/sid-romero/fleetpulse/internal/trip"
+)
+
+// tripSweepInterval is how often trips of vehicles that stopped reporting
+// are closed
+const tripSweepInterval = time.Minute
+
+// TripService segments telemetry into trips, stores completed trips and
+// publishes a trip.completed event for each
+type TripService struct {
+	trips     repository.TripRepository
+	segmenter *trip.Segmenter
+	publisher EventPublisher
+	logger    zerolog.Logger
+}
+
+func NewTripService(
+	trips repository.TripRepository,
+	segmenter *trip.Segmenter,
+	publisher EventPublisher,
+	logger zerolog.Logger,
+) *TripService {
+	return &TripService{
+		trips:     trips,
+		segmenter: segmenter,
+		publisher: publisher,
+		logger:    logger,
+	}
+}
+
+// GetByVehicle returns the trips a vehicle started within [from, to), newest
+// first, led by the trip in progress. Paths are left out.
+func (s *TripService) GetByVehicle(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.Trip, error) {
+	trips, err := s.trips.ListByVehicle(ctx, vehicleID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	current, ok := s.segmenter.Current(vehicleID)
+	if !ok || current.StartedAt.Before(from) || !current.StartedAt.Before(to) {
+		return trips, nil
+	}
+	current.Path = nil
+	return append([]domain.Trip{*current}, trips...), nil
+}
+
+// GetByID returns a trip, in progress or completed, with its full path
+func (s *TripService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Trip, error) {
+	if current, ok := s.segmenter.Find(id); ok {
+		return current, nil
+	}
+	return s.trips.GetByID(ctx, id)
+}
+
+// HandleEvent consumes telemetry.received events
+func (s *TripService) HandleEvent(ctx context.Context, event *domain.Event) {
+	var data domain.TelemetryEventData
+	if err := json.Unmarshal(event.Data, &data); err != nil || data.Vehicle == nil {
+		s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid telemetry event")
+		return
+	}
+
+	if completed := s.segmenter.Update(&data.Telemetry, data.Vehicle.DriverID); completed != nil {
+		s.complete(ctx, completed)
+	}
+}
+
+// Run closes the trips of vehicles that stopped reporting until ctx is
+// cancelled
+func (s *TripService) Run(ctx context.Context) {
+	ticker := time.NewTicker(tripSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			for _, completed := range s.segmenter.Expire(now) {
+				s.complete(ctx, &completed)
+			}
+		}
+	}
+}
+
+func (s *TripService) complete(ctx context.Context, t *domain.Trip) {
+	if err := s.trips.Create(ctx, t); err != nil {
+		s.logger.Error().Err(err).
+			Str("vehicleId", t.VehicleID.String()).
+			Str("tripId", t.ID.String()).
+			Msg("Failed to store trip")
+		return
+	}
+
+	summary := *t
+	summary.Path = nil
+	publishEvent(ctx, s.publisher, s.logger, domain.EventTypeTripCompleted, t.VehicleID.String(), summary)
+}
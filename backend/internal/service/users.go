@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// UserService manages the operators allowed to use the API
+type UserService struct {
+	users  repository.UserRepository
+	logger zerolog.Logger
+}
+
+func NewUserService(users repository.UserRepository, logger zerolog.Logger) *UserService {
+	return &UserService{users: users, logger: logger}
+}
+
+func (s *UserService) GetAll(ctx context.Context) ([]domain.User, error) {
+	return s.users.List(ctx)
+}
+
+func (s *UserService) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return s.users.GetByID(ctx, id)
+}
+
+func (s *UserService) Create(ctx context.Context, user *domain.User) (*domain.User, error) {
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	user.ID = uuid.New()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().Str("userId", user.ID.String()).Str("role", string(user.Role)).Msg("User created")
+	return user, nil
+}
+
+func (s *UserService) Update(ctx context.Context, user *domain.User) (*domain.User, error) {
+	user.Email = strings.ToLower(strings.TrimSpace(user.Email))
+	if err := user.Validate(); err != nil {
+		return nil, err
+	}
+
+	user.UpdatedAt = time.Now()
+	if err := s.users.Update(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
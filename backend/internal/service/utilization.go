@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+	"github.com/sid-romero/fleetpulse/internal/repository"
+)
+
+// UtilizationService records vehicle status changes and reports how long
+// vehicles spent in each status
+type UtilizationService struct {
+	changes  repository.VehicleStatusChangeRepository
+	vehicles repository.VehicleRepository
+	logger   zerolog.Logger
+}
+
+func NewUtilizationService(
+	changes repository.VehicleStatusChangeRepository,
+	vehicles repository.VehicleRepository,
+	logger zerolog.Logger,
+) *UtilizationService {
+	return &UtilizationService{
+		changes:  changes,
+		vehicles: vehicles,
+		logger:   logger,
+	}
+}
+
+// HandleEvent consumes vehicle.status.changed events
+func (s *UtilizationService) HandleEvent(ctx context.Context, event *domain.Event) {
+	var data domain.VehicleStatusChangeData
+	if err := json.Unmarshal(event.Data, &data); err != nil {
+		s.logger.Error().Err(err).Str("eventId", event.ID.String()).Msg("Invalid vehicle status change event")
+		return
+	}
+
+	change := domain.VehicleStatusChange{
+		ID:             uuid.New(),
+		VehicleID:      data.VehicleID,
+		PreviousStatus: data.PreviousStatus,
+		Status:         data.NewStatus,
+		Reason:         data.Reason,
+		ChangedAt:      data.ChangedAt,
+	}
+	if change.ChangedAt.IsZero() {
+		change.ChangedAt = event.Timestamp
+	}
+	if err := s.changes.Create(ctx, &change); err != nil {
+		s.logger.Error().Err(err).Str("vehicleId", data.VehicleID.String()).Msg("Failed to store vehicle status change")
+	}
+}
+
+// GetTimeline returns the periods a vehicle spent in each status within
+// [from, to), oldest first
+func (s *UtilizationService) GetTimeline(ctx context.Context, vehicleID uuid.UUID, from, to time.Time) ([]domain.VehicleStatusPeriod, error) {
+	vehicle, err := s.vehicles.GetByID(ctx, vehicleID)
+	if err != nil {
+		return nil, err
+	}
+	return s.timeline(ctx, vehicle, from, to)
+}
+
+// GetReport returns the share of time each vehicle, and the fleet as a
+// whole, spent in each status within [from, to)
+func (s *UtilizationService) GetReport(ctx context.Context, from, to time.Time) (*domain.UtilizationReport, error) {
+	vehicles, err := s.vehicles.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &domain.UtilizationReport{
+		From:     from,
+		To:       to,
+		Vehicles: make([]domain.VehicleUtilization, 0, len(vehicles)),
+	}
+	fleet := make(map[domain.VehicleStatus]time.Duration)
+	var fleetTracked time.Duration
+	for i := range vehicles {
+		periods, err := s.timeline(ctx, &vehicles[i], from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		spent := make(map[domain.VehicleStatus]time.Duration)
+		var tracked time.Duration
+		for _, p := range periods {
+			d := p.End.Sub(p.Start)
+			spent[p.Status] += d
+			fleet[p.Status] += d
+			tracked += d
+		}
+		fleetTracked += tracked
+
+		report.Vehicles = append(report.Vehicles, domain.VehicleUtilization{
+			VehicleID:    vehicles[i].ID,
+			Name:         vehicles[i].Name,
+			TrackedHours: hours(tracked),
+			Statuses:     utilization(spent, tracked),
+		})
+	}
+	report.Fleet = utilization(fleet, fleetTracked)
+	return report, nil
+}
+
+// timeline splits [from, to) into the periods the vehicle spent in each
+// status. Time before the vehicle was added and after now is left out. The
+// status before the first change in range comes from the last change before
+// it, and without any change the vehicle has always had its current status.
+func (s *UtilizationService) timeline(ctx context.Context, vehicle *domain.Vehicle, from, to time.Time) ([]domain.VehicleStatusPeriod, error) {
+	periods := []domain.VehicleStatusPeriod{}
+	start, end := from, to
+	if vehicle.CreatedAt.After(start) {
+		start = vehicle.CreatedAt
+	}
+	if now := time.Now(); now.Before(end) {
+		end = now
+	}
+	if !start.Before(end) {
+		return periods, nil
+	}
+
+	changes, err := s.changes.ListByVehicle(ctx, vehicle.ID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	current := domain.VehicleStatusPeriod{Status: vehicle.Status, Start: start}
+	last, err := s.changes.LastBefore(ctx, vehicle.ID, start)
+	switch {
+	case err == nil:
+		current.Status, current.Reason = last.Status, last.Reason
+	case !errors.Is(err, domain.ErrNotFound):
+		return nil, err
+	case len(changes) > 0:
+		current.Status = changes[0].PreviousStatus
+	}
+
+	for _, c := range changes {
+		if c.ChangedAt.After(current.Start) {
+			periods = append(periods, closePeriod(current, c.ChangedAt))
+		}
+		current = domain.VehicleStatusPeriod{Status: c.Status, Start: c.ChangedAt, Reason: c.Reason}
+	}
+	return append(periods, closePeriod(current, end)), nil
+}
+
+func closePeriod(p domain.VehicleStatusPeriod, end time.Time) domain.VehicleStatusPeriod {
+	p.End = end
+	p.Hours = hours(end.Sub(p.Start))
+	return p
+}
+
+// utilization lists the time spent in every status, in domain.VehicleStatuses
+// order, as hours and as a percentage of tracked
+func utilization(spent map[domain.VehicleStatus]time.Duration, tracked time.Duration) []domain.StatusUtilization {
+	statuses := make([]domain.StatusUtilization, 0, len(domain.VehicleStatuses))
+	for _, status := range domain.VehicleStatuses {
+		u := domain.StatusUtilization{Status: status, Hours: hours(spent[status])}
+		if tracked > 0 {
+			u.Percent = math.Round(float64(spent[status])/float64(tracked)*1000) / 10
+		}
+		statuses = append(statuses, u)
+	}
+	return statuses
+}
+
+// hours converts d to hours, rounded to two decimals
+func hours(d time.Duration) float64 {
+	return math.Round(d.Hours()*100) / 100
+}
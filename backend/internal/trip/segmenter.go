@@ -0,0 +1,235 @@
+package trip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// Segmenter turns each vehicle's telemetry stream into trips. A trip starts
+// when the vehicle moves and ends when the ignition is switched off, when it
+// has been stationary for the idle timeout, or when it has not reported for
+// that long. Trips in progress live in memory only.
+type Segmenter struct {
+	cfg      config.TripsConfig
+	vehicles map[uuid.UUID]*vehicleState
+	mu       sync.Mutex
+}
+
+type vehicleState struct {
+	last     *domain.Telemetry // newest record, kept to anchor the next trip's start
+	received time.Time         // when the newest record arrived, by the server clock
+	trip     *openTrip
+}
+
+type openTrip struct {
+	id       uuid.UUID
+	driverID *uuid.UUID
+	points   []domain.Telemetry
+	stopped  int // index of the record the current stop began at, -1 while moving
+}
+
+// NewSegmenter creates a Segmenter with no open trips
+func NewSegmenter(cfg config.TripsConfig) *Segmenter {
+	return &Segmenter{
+		cfg:      cfg,
+		vehicles: make(map[uuid.UUID]*vehicleState),
+	}
+}
+
+// Update feeds a vehicle's next record, in timestamp order, and returns the
+// trip it completed, if any. driverID is recorded when the record starts a
+// trip.
+func (s *Segmenter) Update(t *domain.Telemetry, driverID *uuid.UUID) *domain.Trip {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.vehicles[t.VehicleID]
+	if !ok {
+		state = &vehicleState{}
+		s.vehicles[t.VehicleID] = state
+	}
+	state.received = time.Now()
+	defer func() {
+		last := *t
+		state.last = &last
+	}()
+
+	var completed *domain.Trip
+	if open := state.trip; open != nil {
+		if t.Timestamp.Sub(open.lastSeen()) >= s.cfg.IdleTimeout {
+			completed = s.close(state, open.timeoutReason())
+		}
+	}
+
+	open := state.trip
+	if open == nil {
+		if s.moving(t) && !ignitionOff(t) {
+			s.start(state, t, driverID)
+		}
+		return completed
+	}
+
+	open.points = append(open.points, *t)
+	switch {
+	case ignitionOff(t):
+		return s.close(state, domain.TripEndIgnitionOff)
+	case s.moving(t):
+		open.stopped = -1
+	case open.stopped < 0:
+		open.stopped = len(open.points) - 1
+	case t.Timestamp.Sub(open.points[open.stopped].Timestamp) >= s.cfg.IdleTimeout:
+		return s.close(state, domain.TripEndIdle)
+	}
+	return completed
+}
+
+// Expire completes the trips of vehicles that have sent nothing for the idle
+// timeout before now. It goes by when records arrived rather than by their
+// timestamps, so a vehicle replaying records it buffered offline is not cut
+// off mid-trip; gaps within the replay are found by Update.
+func (s *Segmenter) Expire(now time.Time) []domain.Trip {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var completed []domain.Trip
+	for _, state := range s.vehicles {
+		open := state.trip
+		if open == nil || now.Sub(state.received) < s.cfg.IdleTimeout {
+			continue
+		}
+		if trip := s.close(state, open.timeoutReason()); trip != nil {
+			completed = append(completed, *trip)
+		}
+	}
+	return completed
+}
+
+// Current returns the trip in progress for a vehicle, with its path so far
+func (s *Segmenter) Current(vehicleID uuid.UUID) (*domain.Trip, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state, ok := s.vehicles[vehicleID]
+	if !ok || state.trip == nil {
+		return nil, false
+	}
+	trip := summarize(state.trip, state.trip.points)
+	return &trip, true
+}
+
+// Find returns the trip in progress with the given ID
+func (s *Segmenter) Find(id uuid.UUID) (*domain.Trip, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, state := range s.vehicles {
+		if state.trip != nil && state.trip.id == id {
+			trip := summarize(state.trip, state.trip.points)
+			return &trip, true
+		}
+	}
+	return nil, false
+}
+
+// start opens a trip at t, beginning from the previous record when it is
+// recent enough to mark where the vehicle set off from
+func (s *Segmenter) start(state *vehicleState, t *domain.Telemetry, driverID *uuid.UUID) {
+	open := &openTrip{id: uuid.New(), driverID: driverID, stopped: -1}
+	if last := state.last; last != nil && t.Timestamp.Sub(last.Timestamp) < s.cfg.IdleTimeout {
+		open.points = append(open.points, *last)
+	}
+	open.points = append(open.points, *t)
+	state.trip = open
+}
+
+// close ends the open trip, trimming records after the stop it ended at, and
+// returns it unless it was too short to count
+func (s *Segmenter) close(state *vehicleState, reason string) *domain.Trip {
+	open := state.trip
+	state.trip = nil
+
+	points := open.points
+	if reason != domain.TripEndIgnitionOff && open.stopped >= 0 {
+		points = points[:open.stopped+1]
+	}
+
+	trip := summarize(open, points)
+	if trip.DistanceKm*1000 < s.cfg.MinDistance {
+		return nil
+	}
+
+	end := points[len(points)-1].Timestamp
+	trip.Status = domain.TripStatusCompleted
+	trip.EndedAt = &end
+	trip.EndReason = reason
+	return &trip
+}
+
+func (s *Segmenter) moving(t *domain.Telemetry) bool {
+	return float64(t.Speed) >= s.cfg.MinSpeed
+}
+
+func ignitionOff(t *domain.Telemetry) bool {
+	return t.Ignition != nil && !*t.Ignition
+}
+
+func (o *openTrip) lastSeen() time.Time {
+	return o.points[len(o.points)-1].Timestamp
+}
+
+// timeoutReason tells a vehicle parked with the ignition on from one that
+// went silent while moving
+func (o *openTrip) timeoutReason() string {
+	if o.stopped >= 0 {
+		return domain.TripEndIdle
+	}
+	return domain.TripEndSignalLost
+}
+
+// summarize computes a trip's totals over points
+func summarize(open *openTrip, points []domain.Telemetry) domain.Trip {
+	first, last := points[0], points[len(points)-1]
+	trip := domain.Trip{
+		ID:            open.id,
+		VehicleID:     first.VehicleID,
+		DriverID:      open.driverID,
+		Status:        domain.TripStatusInProgress,
+		StartedAt:     first.Timestamp,
+		StartLocation: first.Location,
+		EndLocation:   last.Location,
+		EnergyUsed:    first.BatteryLevel - last.BatteryLevel,
+		Path:          make([]domain.TripPoint, 0, len(points)),
+	}
+	if first.FuelLevel != nil && last.FuelLevel != nil {
+		fuel := *first.FuelLevel - *last.FuelLevel
+		trip.FuelUsed = &fuel
+	}
+
+	var meters float64
+	for i, p := range points {
+		if i > 0 {
+			meters += domain.DistanceMeters(points[i-1].Location, p.Location)
+		}
+		if p.Speed > trip.MaxSpeed {
+			trip.MaxSpeed = p.Speed
+		}
+		trip.Path = append(trip.Path, domain.TripPoint{
+			Lat:       p.Location.Lat,
+			Lng:       p.Location.Lng,
+			Speed:     p.Speed,
+			Timestamp: p.Timestamp,
+		})
+	}
+
+	duration := last.Timestamp.Sub(first.Timestamp)
+	trip.DistanceKm = meters / 1000
+	trip.DurationSeconds = int64(duration.Seconds())
+	if hours := duration.Hours(); hours > 0 {
+		trip.AvgSpeed = float32(trip.DistanceKm / hours)
+	}
+	return trip
+}
@@ -0,0 +1,166 @@
+package trip
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+var testConfig = config.TripsConfig{
+	MinSpeed:    5,
+	IdleTimeout: 5 * time.Minute,
+	MinDistance: 500,
+}
+
+// record is a reading some minutes into the test; 0.009 degrees of latitude
+// is about 1 km
+type record struct {
+	minute   int
+	lat      float64
+	speed    float32
+	ignition *bool
+}
+
+var off = new(bool)
+
+func (r record) telemetry(vehicleID uuid.UUID, start time.Time) *domain.Telemetry {
+	return &domain.Telemetry{
+		VehicleID: vehicleID,
+		Timestamp: start.Add(time.Duration(r.minute) * time.Minute),
+		Location:  domain.Location{Lat: r.lat, Lng: -3.7},
+		Speed:     r.speed,
+		Ignition:  r.ignition,
+	}
+}
+
+func TestSegmenterUpdate(t *testing.T) {
+	tests := []struct {
+		name       string
+		records    []record
+		completed  int // index of the record that completes a trip, -1 for none
+		reason     string
+		endMinute  int
+		distanceKm float64
+		open       bool // whether a trip is in progress after the records
+	}{
+		{
+			name:      "parked vehicle",
+			records:   []record{{0, 40, 0, nil}, {1, 40, 0, nil}, {10, 40, 0, nil}},
+			completed: -1,
+		},
+		{
+			name:      "trip in progress",
+			records:   []record{{0, 40, 0, nil}, {1, 40.009, 60, nil}},
+			completed: -1,
+			open:      true,
+		},
+		{
+			name:       "ignition off",
+			records:    []record{{0, 40, 0, nil}, {1, 40.009, 60, nil}, {2, 40.018, 60, nil}, {3, 40.018, 0, off}},
+			completed:  3,
+			reason:     domain.TripEndIgnitionOff,
+			endMinute:  3,
+			distanceKm: 2,
+		},
+		{
+			name:       "stationary for the idle timeout",
+			records:    []record{{0, 40, 60, nil}, {1, 40.009, 60, nil}, {2, 40.009, 0, nil}, {4, 40.009, 0, nil}, {7, 40.009, 0, nil}},
+			completed:  4,
+			reason:     domain.TripEndIdle,
+			endMinute:  2,
+			distanceKm: 1,
+		},
+		{
+			name:       "silent while moving",
+			records:    []record{{0, 40, 60, nil}, {1, 40.009, 60, nil}, {10, 40.1, 60, nil}},
+			completed:  2,
+			reason:     domain.TripEndSignalLost,
+			endMinute:  1,
+			distanceKm: 1,
+			open:       true,
+		},
+		{
+			name:      "shorter than the minimum distance",
+			records:   []record{{0, 40, 10, nil}, {1, 40.001, 10, nil}, {2, 40.001, 0, off}},
+			completed: -1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segmenter := NewSegmenter(testConfig)
+			vehicleID := uuid.New()
+			start := time.Now().Add(-time.Hour)
+
+			completed := -1
+			var trip *domain.Trip
+			for i, r := range tt.records {
+				if done := segmenter.Update(r.telemetry(vehicleID, start), nil); done != nil {
+					if trip != nil {
+						t.Fatalf("records %d and %d both completed a trip", completed, i)
+					}
+					completed, trip = i, done
+				}
+			}
+
+			if completed != tt.completed {
+				t.Fatalf("trip completed by record %d, want %d", completed, tt.completed)
+			}
+			if trip != nil {
+				if trip.Status != domain.TripStatusCompleted || trip.EndReason != tt.reason {
+					t.Errorf("trip %s/%s, want completed/%s", trip.Status, trip.EndReason, tt.reason)
+				}
+				if end := start.Add(time.Duration(tt.endMinute) * time.Minute); trip.EndedAt == nil || !trip.EndedAt.Equal(end) {
+					t.Errorf("trip ended at %v, want %v", trip.EndedAt, end)
+				}
+				if math.Abs(trip.DistanceKm-tt.distanceKm) > 0.05 {
+					t.Errorf("trip distance = %.2f km, want %.2f", trip.DistanceKm, tt.distanceKm)
+				}
+			}
+			if _, open := segmenter.Current(vehicleID); open != tt.open {
+				t.Errorf("trip in progress = %v, want %v", open, tt.open)
+			}
+		})
+	}
+}
+
+func TestSegmenterExpire(t *testing.T) {
+	tests := []struct {
+		name    string
+		age     time.Duration // of the records' timestamps
+		after   time.Duration // from their arrival to the expiry check
+		expired bool
+	}{
+		{name: "live vehicle just reported", after: time.Minute},
+		{name: "live vehicle went silent", after: testConfig.IdleTimeout, expired: true},
+		{name: "replay of old records just arrived", age: 24 * time.Hour, after: time.Minute},
+		{name: "replay of old records went silent", age: 24 * time.Hour, after: testConfig.IdleTimeout, expired: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segmenter := NewSegmenter(testConfig)
+			vehicleID := uuid.New()
+			start := time.Now().Add(-tt.age - 2*time.Minute)
+
+			for _, r := range []record{{0, 40, 60, nil}, {1, 40.009, 60, nil}, {2, 40.018, 60, nil}} {
+				segmenter.Update(r.telemetry(vehicleID, start), nil)
+			}
+
+			trips := segmenter.Expire(time.Now().Add(tt.after))
+			if expired := len(trips) == 1; expired != tt.expired {
+				t.Fatalf("expired %d trips, want expired = %v", len(trips), tt.expired)
+			}
+			if tt.expired && trips[0].EndReason != domain.TripEndSignalLost {
+				t.Errorf("end reason = %s, want %s", trips[0].EndReason, domain.TripEndSignalLost)
+			}
+			if _, open := segmenter.Current(vehicleID); open == tt.expired {
+				t.Errorf("trip in progress = %v after expiry", open)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Package validation checks telemetry records before they are stored.
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+// Validator checks a record's readings, its timestamp against the server
+// clock and its position against the vehicle's previous record. Whether the
+// vehicle exists is left to the ingestion pipeline, which looks it up anyway.
+type Validator struct {
+	cfg config.ValidationConfig
+}
+
+// NewValidator creates a Validator, failing when a bound is negative
+func NewValidator(cfg config.ValidationConfig) (*Validator, error) {
+	if cfg.MaxClockSkew < 0 || cfg.MaxAge < 0 || cfg.MaxSpeed < 0 || cfg.MinJump < 0 {
+		return nil, fmt.Errorf("telemetry validation bounds must not be negative")
+	}
+	return &Validator{cfg: cfg}, nil
+}
+
+// Check validates t as of now. previous is the vehicle's latest record, or
+// nil when there is none. Errors are *domain.TelemetryError.
+func (v *Validator) Check(t, previous *domain.Telemetry, now time.Time) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	if err := v.checkTimestamp(t, now); err != nil {
+		return err
+	}
+	if previous != nil {
+		return v.checkMove(t, previous)
+	}
+	return nil
+}
+
+func (v *Validator) checkTimestamp(t *domain.Telemetry, now time.Time) error {
+	if t.Timestamp.After(now.Add(v.cfg.MaxClockSkew)) {
+		return domain.NewTelemetryError(domain.RejectClockSkew, "timestamp",
+			fmt.Sprintf("is more than %s ahead of the server clock", v.cfg.MaxClockSkew))
+	}
+	if v.cfg.MaxAge > 0 && t.Timestamp.Before(now.Add(-v.cfg.MaxAge)) {
+		return domain.NewTelemetryError(domain.RejectTooOld, "timestamp",
+			fmt.Sprintf("is more than %s old", v.cfg.MaxAge))
+	}
+	return nil
+}
+
+// checkMove rejects a record further from previous than the vehicle could
+// have travelled at MaxSpeed in the time between them. Late records are
+// compared the same way, going back in time.
+func (v *Validator) checkMove(t, previous *domain.Telemetry) error {
+	meters := domain.DistanceMeters(previous.Location, t.Location)
+	if meters <= v.cfg.MinJump {
+		return nil
+	}
+
+	elapsed := t.Timestamp.Sub(previous.Timestamp).Abs()
+	if meters/1000 <= v.cfg.MaxSpeed*elapsed.Hours() {
+		return nil
+	}
+	return domain.NewTelemetryError(domain.RejectJump, "location",
+		fmt.Sprintf("is %.1f km from the previous position, %s apart", meters/1000, elapsed.Round(time.Second)))
+}
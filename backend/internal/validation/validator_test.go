@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sid-romero/fleetpulse/internal/config"
+	"github.com/sid-romero/fleetpulse/internal/domain"
+)
+
+var testConfig = config.ValidationConfig{
+	MaxClockSkew: 5 * time.Minute,
+	MaxAge:       24 * time.Hour,
+	MaxSpeed:     300,
+	MinJump:      500,
+}
+
+func TestNewValidator(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *config.ValidationConfig)
+		wantErr bool
+	}{
+		{name: "valid bounds", modify: func(cfg *config.ValidationConfig) {}},
+		{name: "zero bounds", modify: func(cfg *config.ValidationConfig) { *cfg = config.ValidationConfig{} }},
+		{name: "negative clock skew", modify: func(cfg *config.ValidationConfig) { cfg.MaxClockSkew = -time.Second }, wantErr: true},
+		{name: "negative age", modify: func(cfg *config.ValidationConfig) { cfg.MaxAge = -time.Hour }, wantErr: true},
+		{name: "negative speed", modify: func(cfg *config.ValidationConfig) { cfg.MaxSpeed = -1 }, wantErr: true},
+		{name: "negative jump", modify: func(cfg *config.ValidationConfig) { cfg.MinJump = -1 }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testConfig
+			tt.modify(&cfg)
+			if _, err := NewValidator(cfg); (err != nil) != tt.wantErr {
+				t.Errorf("NewValidator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatorCheck(t *testing.T) {
+	validator, err := NewValidator(testConfig)
+	if err != nil {
+		t.Fatalf("NewValidator: %v", err)
+	}
+	now := time.Now()
+	vehicleID := uuid.New()
+	// 0.009 degrees of latitude is about 1 km
+	previous := &domain.Telemetry{VehicleID: vehicleID, Timestamp: now.Add(-10 * time.Minute), Location: domain.Location{Lat: 40, Lng: -3.7}}
+
+	tests := []struct {
+		name     string
+		offset   time.Duration // of the record from now
+		lat      float64
+		speed    float32
+		previous *domain.Telemetry
+		reason   domain.RejectReason // empty when the record is valid
+	}{
+		{name: "valid without a previous record", lat: 40},
+		{name: "reading out of range", lat: 40, speed: 500, reason: domain.RejectOutOfRange},
+		{name: "position not a number", lat: math.NaN(), reason: domain.RejectOutOfRange},
+		{name: "within the clock skew", offset: 4 * time.Minute, lat: 40},
+		{name: "ahead of the clock skew", offset: 6 * time.Minute, lat: 40, reason: domain.RejectClockSkew},
+		{name: "within the age limit", offset: -23 * time.Hour, lat: 40},
+		{name: "older than the age limit", offset: -25 * time.Hour, lat: 40, reason: domain.RejectTooOld},
+		{name: "jitter without time passing", offset: -10 * time.Minute, lat: 40.0036, previous: previous},
+		{name: "plausible move", offset: -9 * time.Minute, lat: 40.009, previous: previous},
+		{name: "jump", offset: -9 * time.Minute, lat: 40.09, previous: previous, reason: domain.RejectJump},
+		{name: "plausible late record", offset: -11 * time.Minute, lat: 39.991, previous: previous},
+		{name: "late record jump", offset: -11 * time.Minute, lat: 39.91, previous: previous, reason: domain.RejectJump},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := &domain.Telemetry{
+				VehicleID: vehicleID,
+				Timestamp: now.Add(tt.offset),
+				Location:  domain.Location{Lat: tt.lat, Lng: -3.7},
+				Speed:     tt.speed,
+			}
+
+			err := validator.Check(record, tt.previous, now)
+			if tt.reason == "" {
+				if err != nil {
+					t.Errorf("Check() = %v, want nil", err)
+				}
+				return
+			}
+			if reason, _ := domain.RejectReasonOf(err); reason != tt.reason {
+				t.Errorf("Check() = %v, want reason %s", err, tt.reason)
+			}
+		})
+	}
+}
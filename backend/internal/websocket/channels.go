@@ -0,0 +1,98 @@
+package websocket
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Channels group broadcast messages. A message is delivered to a client
+// subscribed to any of its channels; subscriptions may use glob patterns,
+// e.g. "vehicle:*" for every vehicle or "*" for everything.
+const (
+	ChannelAll       = "*"
+	ChannelTelemetry = "telemetry"
+	ChannelAlerts    = "alerts"
+	ChannelVehicles  = "vehicles"
+	ChannelGeofences = "geofences"
+	ChannelStats     = "stats"
+
+	vehicleChannelPrefix  = "vehicle:"
+	geofenceChannelPrefix = "geofence:"
+)
+
+// VehicleChannel carries telemetry, alerts, status and geofence transitions
+// for a single vehicle
+func VehicleChannel(id uuid.UUID) string {
+	return vehicleChannelPrefix + id.String()
+}
+
+// GeofenceChannel carries enter/exit transitions for a single geofence
+func GeofenceChannel(id uuid.UUID) string {
+	return geofenceChannelPrefix + id.String()
+}
+
+// SubscriptionAck is sent in reply to subscribe and unsubscribe requests
+type SubscriptionAck struct {
+	Channels      []string `json:"channels"`           // channels named in the request
+	Subscriptions []string `json:"subscriptions"`      // everything the client is now subscribed to
+	Rejected      []string `json:"rejected,omitempty"` // unknown or malformed channels
+}
+
+// validChannel reports whether pattern names a known channel or a valid
+// pattern over one
+func validChannel(pattern string) bool {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return false
+	}
+
+	switch pattern {
+	case ChannelAll, ChannelTelemetry, ChannelAlerts, ChannelVehicles, ChannelGeofences, ChannelStats:
+		return true
+	}
+	for _, prefix := range []string{vehicleChannelPrefix, geofenceChannelPrefix} {
+		if strings.HasPrefix(pattern, prefix) && len(pattern) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches reports whether any subscription matches any of channels.
+// Messages without channels (pings) go to everyone.
+func matches(subscriptions map[string]bool, channels []string) bool {
+	if len(channels) == 0 {
+		return true
+	}
+	for pattern := range subscriptions {
+		for _, channel := range channels {
+			if ok, _ := path.Match(pattern, channel); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseChannels splits a comma-separated channel list, as accepted in the
+// channels query parameter
+func parseChannels(value string) []string {
+	var channels []string
+	for _, channel := range strings.Split(value, ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+func sortedChannels(subscriptions map[string]bool) []string {
+	channels := make([]string, 0, len(subscriptions))
+	for channel := range subscriptions {
+		channels = append(channels, channel)
+	}
+	sort.Strings(channels)
+	return channels
+}
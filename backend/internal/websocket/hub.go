@@ -18,14 +18,17 @@ import (
 type MessageType string
 
 const (
-	MessageTypeTelemetry   MessageType = "telemetry"
-	MessageTypeAlert       MessageType = "alert"
-	MessageTypeVehicle     MessageType = "vehicle"
-	MessageTypeStats       MessageType = "stats"
-	MessageTypeSubscribe   MessageType = "subscribe"
-	MessageTypeUnsubscribe MessageType = "unsubscribe"
-	MessageTypePing        MessageType = "ping"
-	MessageTypePong        MessageType = "pong"
+	MessageTypeTelemetry    MessageType = "telemetry"
+	MessageTypeAlert        MessageType = "alert"
+	MessageTypeVehicle      MessageType = "vehicle"
+	MessageTypeStats        MessageType = "stats"
+	MessageTypeGeofence     MessageType = "geofence"
+	MessageTypeSubscribe    MessageType = "subscribe"
+	MessageTypeUnsubscribe  MessageType = "unsubscribe"
+	MessageTypeSubscribed   MessageType = "subscribed"
+	MessageTypeUnsubscribed MessageType = "unsubscribed"
+	MessageTypePing         MessageType = "ping"
+	MessageTypePong         MessageType = "pong"
 )
 
 // Message represents a WebSocket message
@@ -42,26 +45,36 @@ type Client struct {
 	Hub           *Hub
 	Subscriptions map[string]bool // channels subscribed to
 	Send          chan Message
+	closed        bool
 	mu            sync.RWMutex
 }
 
 // Hub manages WebSocket connections and broadcasting
 type Hub struct {
 	clients    map[uuid.UUID]*Client
-	broadcast  chan Message
+	broadcast  chan broadcastMessage
 	register   chan *Client
 	unregister chan *Client
+	done       chan struct{}
 	logger     zerolog.Logger
 	mu         sync.RWMutex
 }
 
+// broadcastMessage is a message queued for the clients subscribed to any of
+// its channels
+type broadcastMessage struct {
+	message  Message
+	channels []string
+}
+
 // NewHub creates a new WebSocket hub
 func NewHub(logger zerolog.Logger) *Hub {
 	return &Hub{
 		clients:    make(map[uuid.UUID]*Client),
-		broadcast:  make(chan Message, 256),
+		broadcast:  make(chan broadcastMessage, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		done:       make(chan struct{}),
 		logger:     logger,
 	}
 }
@@ -75,9 +88,11 @@ func (h *Hub) Run(ctx context.Context) {
 		select {
 		case <-ctx.Done():
 			h.logger.Info().Msg("WebSocket hub shutting down")
+			close(h.done)
 			h.mu.Lock()
-			for _, client := range h.clients {
-				close(client.Send)
+			for id, client := range h.clients {
+				delete(h.clients, id)
+				client.close()
 			}
 			h.mu.Unlock()
 			return
@@ -93,38 +108,56 @@ func (h *Hub) Run(ctx context.Context) {
 
 		case client := <-h.unregister:
 			h.mu.Lock()
-			if _, ok := h.clients[client.ID]; ok {
-				delete(h.clients, client.ID)
-				close(client.Send)
-			}
+			h.removeClient(client)
 			h.mu.Unlock()
-			h.logger.Info().
-				Str("clientId", client.ID.String()).
-				Int("totalClients", len(h.clients)).
-				Msg("Client disconnected")
-
-		case message := <-h.broadcast:
-			h.mu.RLock()
-			for _, client := range h.clients {
-				select {
-				case client.Send <- message:
-				default:
-					// Client buffer full, disconnect
-					h.mu.RUnlock()
-					h.unregister <- client
-					h.mu.RLock()
-				}
-			}
-			h.mu.RUnlock()
+
+		case b := <-h.broadcast:
+			h.deliver(b)
 
 		case <-ticker.C:
-			// Send periodic ping to all clients
-			h.BroadcastPing()
+			// Send periodic ping to all clients. It is delivered here rather
+			// than queued, as Run is the only reader of a full queue.
+			h.deliver(pingMessage())
+		}
+	}
+}
+
+// deliver sends a message to the subscribed clients, disconnecting those
+// that can't keep up
+func (h *Hub) deliver(b broadcastMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, client := range h.clients {
+		if !client.subscribed(b.channels) {
+			continue
 		}
+		if !client.send(b.message) {
+			// Client buffer full, disconnect
+			h.removeClient(client)
+		}
+	}
+}
+
+// removeClient drops a client and closes its send buffer. The caller must
+// hold h.mu.
+func (h *Hub) removeClient(client *Client) {
+	if _, ok := h.clients[client.ID]; !ok {
+		return
 	}
+	delete(h.clients, client.ID)
+	client.close()
+
+	h.logger.Info().
+		Str("clientId", client.ID.String()).
+		Int("totalClients", len(h.clients)).
+		Msg("Client disconnected")
 }
 
-// HandleWebSocket handles WebSocket upgrade requests
+// HandleWebSocket handles WebSocket upgrade requests. Clients receive every
+// channel unless the channels query parameter lists the ones they want,
+// e.g. /ws?channels=vehicle:<id>,alerts; either way the initial
+// subscriptions are acknowledged with a subscribed message.
 func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	conn, err := websocket.Accept(w, r, &websocket.AcceptOptions{
 		OriginPatterns: []string{"*"}, // Configure based on environment
@@ -142,53 +175,158 @@ func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		Send:          make(chan Message, 64),
 	}
 
-	h.register <- client
+	channels := []string{ChannelAll}
+	if param := r.URL.Query().Get("channels"); param != "" {
+		channels = parseChannels(param)
+	}
+	client.subscribe(MessageTypeSubscribed, channels)
 
-	// Start read/write goroutines
-	go client.writePump(r.Context())
-	go client.readPump(r.Context())
+	select {
+	case h.register <- client:
+	case <-h.done:
+		conn.Close(websocket.StatusGoingAway, "server shutting down")
+		return
+	}
+
+	// The connection outlives request cancellation (e.g. the router's
+	// timeout middleware); the read pump keeps the handler alive until the
+	// client goes away
+	ctx := context.WithoutCancel(r.Context())
+	go client.writePump(ctx)
+	client.readPump(ctx)
 }
 
-// Broadcast sends a message to all connected clients
-func (h *Hub) Broadcast(msgType MessageType, data interface{}) error {
+// Broadcast sends a message to the clients subscribed to any of channels,
+// or to every client when no channel is given
+func (h *Hub) Broadcast(msgType MessageType, data interface{}, channels ...string) error {
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	h.broadcast <- Message{
-		Type:      msgType,
-		Timestamp: time.Now().UTC(),
-		Data:      jsonData,
-	}
+	h.enqueue(broadcastMessage{
+		message: Message{
+			Type:      msgType,
+			Timestamp: time.Now().UTC(),
+			Data:      jsonData,
+		},
+		channels: channels,
+	})
 	return nil
 }
 
-// BroadcastTelemetry sends telemetry update to all clients
+// enqueue hands a message to Run, giving up once the hub has stopped
+func (h *Hub) enqueue(b broadcastMessage) {
+	select {
+	case h.broadcast <- b:
+	case <-h.done:
+	}
+}
+
+// BroadcastTelemetry sends a telemetry update to the telemetry and vehicle channels
 func (h *Hub) BroadcastTelemetry(telemetry *domain.Telemetry) error {
-	return h.Broadcast(MessageTypeTelemetry, telemetry)
+	return h.Broadcast(MessageTypeTelemetry, telemetry, ChannelTelemetry, VehicleChannel(telemetry.VehicleID))
 }
 
-// BroadcastAlert sends an alert to all clients
+// BroadcastAlert sends an alert to the alerts channel and, for vehicle
+// alerts, the vehicle channel
 func (h *Hub) BroadcastAlert(alert *domain.Alert) error {
-	return h.Broadcast(MessageTypeAlert, alert)
+	channels := []string{ChannelAlerts}
+	if alert.VehicleID != nil {
+		channels = append(channels, VehicleChannel(*alert.VehicleID))
+	}
+	return h.Broadcast(MessageTypeAlert, alert, channels...)
 }
 
 // BroadcastVehicleUpdate sends vehicle status update
 func (h *Hub) BroadcastVehicleUpdate(vehicle *domain.Vehicle) error {
-	return h.Broadcast(MessageTypeVehicle, vehicle)
+	return h.Broadcast(MessageTypeVehicle, vehicle, ChannelVehicles, VehicleChannel(vehicle.ID))
+}
+
+// BroadcastGeofenceEvent sends a geofence enter/exit transition
+func (h *Hub) BroadcastGeofenceEvent(event *domain.GeofenceEventData) error {
+	return h.Broadcast(MessageTypeGeofence, event,
+		ChannelGeofences, GeofenceChannel(event.GeofenceID), VehicleChannel(event.VehicleID))
 }
 
 // BroadcastStats sends fleet stats update
 func (h *Hub) BroadcastStats(stats *domain.FleetStats) error {
-	return h.Broadcast(MessageTypeStats, stats)
+	return h.Broadcast(MessageTypeStats, stats, ChannelStats)
 }
 
 // BroadcastPing sends ping to all clients
 func (h *Hub) BroadcastPing() {
-	h.broadcast <- Message{
-		Type:      MessageTypePing,
-		Timestamp: time.Now().UTC(),
+	h.enqueue(pingMessage())
+}
+
+func pingMessage() broadcastMessage {
+	return broadcastMessage{
+		message: Message{
+			Type:      MessageTypePing,
+			Timestamp: time.Now().UTC(),
+		},
+	}
+}
+
+// RelayedEventTypes are the broadcast events shared between API replicas.
+// Fleet stats are left out: each replica derives them from these events.
+var RelayedEventTypes = []domain.EventType{
+	domain.EventTypeTelemetryReceived,
+	domain.EventTypeVehicleUpdated,
+	domain.EventTypeAlertCreated,
+	domain.EventTypeAlertAcknowledged,
+	domain.EventTypeAlertResolved,
+	domain.EventTypeGeofenceEnter,
+	domain.EventTypeGeofenceExit,
+}
+
+// BroadcastEventTypes are the domain events forwarded to clients by HandleEvent
+var BroadcastEventTypes = append(RelayedEventTypes[:len(RelayedEventTypes):len(RelayedEventTypes)],
+	domain.EventTypeFleetStatsUpdated,
+)
+
+// HandleEvent forwards a domain event to connected clients as the matching
+// message type
+func (h *Hub) HandleEvent(ctx context.Context, event *domain.Event) {
+	var err error
+	switch event.Type {
+	case domain.EventTypeTelemetryReceived:
+		var data domain.TelemetryEventData
+		if err = json.Unmarshal(event.Data, &data); err == nil {
+			err = h.BroadcastTelemetry(&data.Telemetry)
+		}
+
+	case domain.EventTypeVehicleUpdated:
+		var vehicle domain.Vehicle
+		if err = json.Unmarshal(event.Data, &vehicle); err == nil {
+			err = h.BroadcastVehicleUpdate(&vehicle)
+		}
+
+	case domain.EventTypeAlertCreated, domain.EventTypeAlertAcknowledged, domain.EventTypeAlertResolved:
+		var data domain.AlertEventData
+		if err = json.Unmarshal(event.Data, &data); err == nil {
+			err = h.BroadcastAlert(&data.Alert)
+		}
+
+	case domain.EventTypeGeofenceEnter, domain.EventTypeGeofenceExit:
+		var data domain.GeofenceEventData
+		if err = json.Unmarshal(event.Data, &data); err == nil {
+			err = h.BroadcastGeofenceEvent(&data)
+		}
+
+	case domain.EventTypeFleetStatsUpdated:
+		var stats domain.FleetStats
+		if err = json.Unmarshal(event.Data, &stats); err == nil {
+			err = h.BroadcastStats(&stats)
+		}
+	}
+
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("eventId", event.ID.String()).
+			Str("eventType", string(event.Type)).
+			Msg("Failed to broadcast event")
 	}
 }
 
@@ -201,10 +339,83 @@ func (h *Hub) GetClientCount() int {
 
 // Client methods
 
+// subscribed reports whether the client wants a message on channels
+func (c *Client) subscribed(channels []string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return matches(c.Subscriptions, channels)
+}
+
+// send queues a message without blocking, reporting false when the
+// client's buffer is full
+func (c *Client) send(message Message) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return true
+	}
+	select {
+	case c.Send <- message:
+		return true
+	default:
+		return false
+	}
+}
+
+// close closes the send buffer, which stops the write pump
+func (c *Client) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		close(c.Send)
+	}
+}
+
+// leave asks the hub to drop the client, unless the hub has already stopped
+func (c *Client) leave() {
+	select {
+	case c.Hub.unregister <- c:
+	case <-c.Hub.done:
+	}
+}
+
+// subscribe adds (or, for MessageTypeUnsubscribed, removes) channels and
+// acknowledges the request with the resulting subscriptions
+func (c *Client) subscribe(ack MessageType, channels []string) {
+	result := SubscriptionAck{Channels: channels}
+
+	c.mu.Lock()
+	for _, ch := range channels {
+		switch {
+		case ack == MessageTypeUnsubscribed:
+			delete(c.Subscriptions, ch)
+		case validChannel(ch):
+			c.Subscriptions[ch] = true
+		default:
+			result.Rejected = append(result.Rejected, ch)
+		}
+	}
+	result.Subscriptions = sortedChannels(c.Subscriptions)
+	c.mu.Unlock()
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+	c.send(Message{
+		Type:      ack,
+		Timestamp: time.Now().UTC(),
+		Data:      data,
+	})
+}
+
 func (c *Client) writePump(ctx context.Context) {
 	defer func() {
 		c.Conn.Close(websocket.StatusNormalClosure, "")
-		c.Hub.unregister <- c
+		c.leave()
 	}()
 
 	for {
@@ -234,7 +445,7 @@ func (c *Client) writePump(ctx context.Context) {
 
 func (c *Client) readPump(ctx context.Context) {
 	defer func() {
-		c.Hub.unregister <- c
+		c.leave()
 		c.Conn.Close(websocket.StatusNormalClosure, "")
 	}()
 
@@ -256,21 +467,13 @@ func (c *Client) readPump(ctx context.Context) {
 		case MessageTypeSubscribe:
 			var channels []string
 			if err := json.Unmarshal(msg.Data, &channels); err == nil {
-				c.mu.Lock()
-				for _, ch := range channels {
-					c.Subscriptions[ch] = true
-				}
-				c.mu.Unlock()
+				c.subscribe(MessageTypeSubscribed, channels)
 			}
 
 		case MessageTypeUnsubscribe:
 			var channels []string
 			if err := json.Unmarshal(msg.Data, &channels); err == nil {
-				c.mu.Lock()
-				for _, ch := range channels {
-					delete(c.Subscriptions, ch)
-				}
-				c.mu.Unlock()
+				c.subscribe(MessageTypeUnsubscribed, channels)
 			}
 
 		case MessageTypePong:
@@ -14,11 +14,23 @@ CREATE EXTENSION IF NOT EXISTS "pg_trgm"; -- For text search
 CREATE TYPE vehicle_status AS ENUM ('active', 'maintenance', 'idle', 'charging');
 CREATE TYPE alert_severity AS ENUM ('critical', 'warning', 'info');
 CREATE TYPE alert_status AS ENUM ('active', 'acknowledged', 'resolved');
+CREATE TYPE user_role AS ENUM ('admin', 'dispatcher', 'viewer', 'device');
 
 -- ============================================
 -- Tables
 -- ============================================
 
+-- Users table (API operators, identified by the JWT subject)
+CREATE TABLE users (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    email VARCHAR(255) UNIQUE NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    role user_role NOT NULL DEFAULT 'viewer',
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    updated_at TIMESTAMPTZ DEFAULT NOW()
+);
+
 -- Drivers table
 CREATE TABLE drivers (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -55,6 +67,31 @@ CREATE TABLE vehicles (
     updated_at TIMESTAMPTZ DEFAULT NOW()
 );
 
+-- Driver assignment history: who was driving each vehicle, and when. An
+-- assignment is open until unassigned_at is set.
+CREATE TABLE driver_assignments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    assigned_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    unassigned_at TIMESTAMPTZ,
+    assigned_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (unassigned_at IS NULL OR unassigned_at >= assigned_at)
+);
+
+-- Vehicle status history, from which status timelines and utilization are
+-- built
+CREATE TABLE vehicle_status_changes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    previous_status vehicle_status NOT NULL,
+    status vehicle_status NOT NULL,
+    reason TEXT,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    -- orders changes made at the same time, such as a move through idle
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
 -- Alerts table
 CREATE TABLE alerts (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
@@ -65,9 +102,20 @@ CREATE TABLE alerts (
     message TEXT NOT NULL,
     created_at TIMESTAMPTZ DEFAULT NOW(),
     acknowledged_at TIMESTAMPTZ,
-    acknowledged_by UUID REFERENCES drivers(id),
+    acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
     resolved_at TIMESTAMPTZ,
-    resolved_by UUID REFERENCES drivers(id)
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Device keys table (API keys bound to one vehicle; only a SHA-256 of the key is kept)
+CREATE TABLE device_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL DEFAULT '',
+    prefix VARCHAR(20) NOT NULL,
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    revoked_at TIMESTAMPTZ
 );
 
 -- Telemetry table (time-series data)
@@ -75,27 +123,105 @@ CREATE TABLE telemetry (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
     timestamp TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-    latitude DECIMAL(10, 8),
+    latitude DECIMAL(10, 8) CHECK (latitude >= -90 AND latitude <= 90),
+    longitude DECIMAL(11, 8) CHECK (longitude >= -180 AND longitude <= 180),
+    speed DECIMAL(5, 2) CHECK (speed >= 0 AND speed <= 400),
+    battery_level INTEGER CHECK (battery_level >= 0 AND battery_level <= 100),
+    fuel_level INTEGER CHECK (fuel_level >= 0 AND fuel_level <= 100),
+    engine_temp DECIMAL(4, 1) CHECK (engine_temp >= -50 AND engine_temp <= 200),
+    engine_rpm INTEGER CHECK (engine_rpm >= 0 AND engine_rpm <= 20000),
+    heading DECIMAL(5, 2) CHECK (heading >= 0 AND heading <= 360),
+    ignition BOOLEAN,
+    charging_current DECIMAL(6, 2) CHECK (charging_current >= 0 AND charging_current <= 1000) -- amps drawn from a charger
+);
+
+-- Telemetry aggregates per vehicle at 1m, 15m and 1h resolution,
+-- maintained by the API's rollup job
+CREATE TABLE telemetry_rollups (
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    resolution VARCHAR(3) NOT NULL, -- 1m, 15m, 1h
+    bucket_start TIMESTAMPTZ NOT NULL,
+    samples INTEGER NOT NULL,
+    latitude DECIMAL(10, 8), -- last position in the bucket
     longitude DECIMAL(11, 8),
-    speed DECIMAL(5, 2),
-    battery_level INTEGER,
-    fuel_level INTEGER,
-    engine_temp DECIMAL(4, 1),
-    engine_rpm INTEGER,
-    heading DECIMAL(5, 2)
+    speed_min DECIMAL(5, 2),
+    speed_max DECIMAL(5, 2),
+    speed_avg DECIMAL(5, 2),
+    battery_min INTEGER,
+    battery_max INTEGER,
+    battery_avg DECIMAL(5, 2),
+    engine_temp_min DECIMAL(4, 1),
+    engine_temp_max DECIMAL(4, 1),
+    engine_temp_avg DECIMAL(4, 1),
+    distance_km DECIMAL(10, 3) NOT NULL DEFAULT 0,
+    PRIMARY KEY (vehicle_id, resolution, bucket_start)
+);
+
+-- Trips segmented from telemetry
+CREATE TABLE trips (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    driver_id UUID REFERENCES drivers(id) ON DELETE SET NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ NOT NULL,
+    end_reason VARCHAR(20) NOT NULL, -- ignition_off, idle, signal_lost
+    start_latitude DECIMAL(10, 8) NOT NULL,
+    start_longitude DECIMAL(11, 8) NOT NULL,
+    end_latitude DECIMAL(10, 8) NOT NULL,
+    end_longitude DECIMAL(11, 8) NOT NULL,
+    distance_km DECIMAL(10, 3) NOT NULL,
+    duration_seconds INTEGER NOT NULL,
+    energy_used INTEGER NOT NULL, -- battery percentage points
+    fuel_used INTEGER,
+    max_speed DECIMAL(5, 2) NOT NULL,
+    avg_speed DECIMAL(5, 2) NOT NULL,
+    path JSONB NOT NULL DEFAULT '[]' -- [{lat, lng, speed, timestamp}]
+);
+
+-- Risky driving detected from telemetry, attributed to the driver assigned
+-- at the time; driver scores are computed from these
+CREATE TABLE driving_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    driver_id UUID NOT NULL REFERENCES drivers(id) ON DELETE CASCADE,
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL, -- harsh_acceleration, harsh_braking, speeding, idling, night_driving
+    occurred_at TIMESTAMPTZ NOT NULL,
+    value DECIMAL(10, 2) NOT NULL,
+    latitude DECIMAL(10, 8) NOT NULL,
+    longitude DECIMAL(11, 8) NOT NULL
+);
+
+-- Recurring maintenance plans: due every interval_km of odometer and/or
+-- every interval_days since the last service
+CREATE TABLE maintenance_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    type VARCHAR(100) NOT NULL,
+    description TEXT,
+    interval_km INTEGER CHECK (interval_km > 0),
+    interval_days INTEGER CHECK (interval_days > 0),
+    last_service_date TIMESTAMPTZ NOT NULL,
+    last_service_odometer INTEGER NOT NULL DEFAULT 0,
+    due_since TIMESTAMPTZ,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    CHECK (interval_km IS NOT NULL OR interval_days IS NOT NULL)
 );
 
 -- Maintenance records table
 CREATE TABLE maintenance_records (
     id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
     vehicle_id UUID NOT NULL REFERENCES vehicles(id) ON DELETE CASCADE,
+    plan_id UUID REFERENCES maintenance_plans(id) ON DELETE SET NULL,
     date TIMESTAMPTZ NOT NULL,
     type VARCHAR(100) NOT NULL,
     description TEXT,
     cost DECIMAL(10, 2) DEFAULT 0,
-    status VARCHAR(50) DEFAULT 'scheduled',
+    status VARCHAR(50) DEFAULT 'scheduled', -- scheduled, in_progress, completed, cancelled
+    odometer INTEGER, -- km at service
     technician VARCHAR(255),
     notes TEXT,
+    completed_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
@@ -109,6 +235,8 @@ CREATE TABLE geofences (
     radius_meters DECIMAL(10, 2),
     polygon JSONB, -- Array of {lat, lng} points
     is_active BOOLEAN DEFAULT true,
+    alert_on_enter BOOLEAN NOT NULL DEFAULT false,
+    alert_on_exit BOOLEAN NOT NULL DEFAULT false,
     created_at TIMESTAMPTZ DEFAULT NOW()
 );
 
@@ -121,19 +249,41 @@ CREATE INDEX idx_vehicles_status ON vehicles(status);
 CREATE INDEX idx_vehicles_driver ON vehicles(driver_id);
 CREATE INDEX idx_vehicles_location ON vehicles(latitude, longitude);
 
+-- Driver assignment indexes (at most one open assignment per vehicle and
+-- per driver)
+CREATE INDEX idx_driver_assignments_vehicle ON driver_assignments(vehicle_id, assigned_at DESC);
+CREATE INDEX idx_driver_assignments_driver ON driver_assignments(driver_id, assigned_at DESC);
+CREATE UNIQUE INDEX idx_driver_assignments_open_vehicle ON driver_assignments(vehicle_id) WHERE unassigned_at IS NULL;
+CREATE UNIQUE INDEX idx_driver_assignments_open_driver ON driver_assignments(driver_id) WHERE unassigned_at IS NULL;
+
+-- Vehicle status change indexes
+CREATE INDEX idx_vehicle_status_changes_vehicle ON vehicle_status_changes(vehicle_id, changed_at DESC, recorded_at DESC);
+
 -- Alerts indexes
 CREATE INDEX idx_alerts_vehicle ON alerts(vehicle_id);
 CREATE INDEX idx_alerts_status ON alerts(status);
 CREATE INDEX idx_alerts_severity ON alerts(severity);
 CREATE INDEX idx_alerts_created ON alerts(created_at DESC);
 
+-- Device keys indexes
+CREATE INDEX idx_device_keys_vehicle ON device_keys(vehicle_id);
+
 -- Telemetry indexes (optimized for time-series queries)
 CREATE INDEX idx_telemetry_vehicle_time ON telemetry(vehicle_id, timestamp DESC);
 CREATE INDEX idx_telemetry_timestamp ON telemetry(timestamp DESC);
+CREATE INDEX idx_telemetry_rollups_watermark ON telemetry_rollups(resolution, bucket_start DESC);
+
+-- Trips indexes
+CREATE INDEX idx_trips_vehicle_started ON trips(vehicle_id, started_at DESC);
+CREATE INDEX idx_trips_driver_started ON trips(driver_id, started_at DESC);
+
+-- Driving event indexes
+CREATE INDEX idx_driving_events_driver ON driving_events(driver_id, occurred_at DESC);
 
 -- Maintenance indexes
 CREATE INDEX idx_maintenance_vehicle ON maintenance_records(vehicle_id);
 CREATE INDEX idx_maintenance_date ON maintenance_records(date DESC);
+CREATE INDEX idx_maintenance_plans_vehicle ON maintenance_plans(vehicle_id);
 
 -- ============================================
 -- Triggers
@@ -158,10 +308,22 @@ CREATE TRIGGER drivers_updated_at
     FOR EACH ROW
     EXECUTE FUNCTION update_updated_at();
 
+CREATE TRIGGER users_updated_at
+    BEFORE UPDATE ON users
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at();
+
 -- ============================================
 -- Seed Data (Development)
 -- ============================================
 
+-- Insert sample users
+INSERT INTO users (id, email, name, role) VALUES
+    ('e1111111-1111-1111-1111-111111111111', 'admin@fleetpulse.dev', 'Fleet Admin', 'admin'),
+    ('e2222222-2222-2222-2222-222222222222', 'dispatch@fleetpulse.dev', 'Dispatch Desk', 'dispatcher'),
+    ('e3333333-3333-3333-3333-333333333333', 'viewer@fleetpulse.dev', 'Read Only', 'viewer'),
+    ('e4444444-4444-4444-4444-444444444444', 'simulator@fleetpulse.dev', 'Vehicle Simulator', 'device');
+
 -- Insert sample drivers
 INSERT INTO drivers (id, name, email, avatar, rating) VALUES
     ('d1111111-1111-1111-1111-111111111111', 'Alex M.', 'alex@fleetpulse.dev', 'https://i.pravatar.cc/150?u=a042581f4e29026024d', 4.9),
@@ -176,6 +338,10 @@ INSERT INTO vehicles (id, vin, name, model, brand, image, status, battery_level,
     ('33333333-3333-3333-3333-333333333333', 'RIV-EDV-552', 'Urban Hauler X', 'Rivian EDV', 'Rivian', 'https://images.unsplash.com/photo-1675258364539-780c74996459?auto=format&fit=crop&q=80&w=800', 'idle', 92, 200, 40.7829, -73.9654, 'Central Depot', 0, 'd3333333-3333-3333-3333-333333333333', 22, 8900, '19 kWh/100km'),
     ('44444444-4444-4444-4444-444444444444', 'VOL-FH-883', 'Heavy Freight 02', 'Volvo FH Electric', 'Volvo', 'https://images.unsplash.com/photo-1601584115197-04ecc0da31d7?auto=format&fit=crop&q=80&w=800', 'active', 45, 180, 40.7484, -73.9857, 'Empire State Delivery', 42, 'd4444444-4444-4444-4444-444444444444', 20, 85430, '1.2 kWh/km');
 
+-- Open an assignment for each sample vehicle's driver
+INSERT INTO driver_assignments (driver_id, vehicle_id, assigned_at)
+SELECT driver_id, id, created_at FROM vehicles WHERE driver_id IS NOT NULL;
+
 -- Insert sample alerts
 INSERT INTO alerts (id, vehicle_id, type, severity, status, message) VALUES
     ('a1111111-1111-1111-1111-111111111111', '11111111-1111-1111-1111-111111111111', 'tire_pressure', 'critical', 'active', 'Tire pressure low - Vehicle v1'),